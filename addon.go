@@ -7,19 +7,19 @@ import (
 )
 
 type AddOnList struct {
-	XMLName string  `xml:"add-ons"`
-	AddOns  []AddOn `xml:"add-on"`
+	XMLName string  `xml:"add-ons" json:"-"`
+	AddOns  []AddOn `xml:"add-on" json:"add_on"`
 }
 
 type AddOn struct {
-	XMLName string `xml:"add-on"`
+	XMLName string `xml:"add-on" json:"-"`
 	Modification
 }
 
 const addOnsPath = "add_ons"
 
 func (c *APIClient) ListAddons(ctx context.Context) ([]AddOn, error) {
-	response, err := c.do(ctx, http.MethodGet, addOnsPath, nil)
+	response, err := c.do(ctx, "ListAddons", http.MethodGet, addOnsPath, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -8,50 +8,50 @@ import (
 )
 
 type Address struct {
-	XMLName            xml.Name
-	Id                 string     `xml:"id,omitempty"`
-	CustomerId         string     `xml:"customer-id,omitempty"`
-	FirstName          string     `xml:"first-name,omitempty"`
-	LastName           string     `xml:"last-name,omitempty"`
-	Company            string     `xml:"company,omitempty"`
-	StreetAddress      string     `xml:"street-address,omitempty"`
-	ExtendedAddress    string     `xml:"extended-address,omitempty"`
-	Locality           string     `xml:"locality,omitempty"`
-	Region             string     `xml:"region,omitempty"`
-	PostalCode         string     `xml:"postal-code,omitempty"`
-	CountryCodeAlpha2  string     `xml:"country-code-alpha2,omitempty"`
-	CountryCodeAlpha3  string     `xml:"country-code-alpha3,omitempty"`
-	CountryCodeNumeric string     `xml:"country-code-numeric,omitempty"`
-	CountryName        string     `xml:"country-name,omitempty"`
-	CreatedAt          *time.Time `xml:"created-at,omitempty"`
-	UpdatedAt          *time.Time `xml:"updated-at,omitempty"`
+	XMLName            xml.Name   `json:"-"`
+	Id                 string     `xml:"id,omitempty" json:"id,omitempty"`
+	CustomerId         string     `xml:"customer-id,omitempty" json:"customer_id,omitempty"`
+	FirstName          string     `xml:"first-name,omitempty" json:"first_name,omitempty"`
+	LastName           string     `xml:"last-name,omitempty" json:"last_name,omitempty"`
+	Company            string     `xml:"company,omitempty" json:"company,omitempty"`
+	StreetAddress      string     `xml:"street-address,omitempty" json:"street_address,omitempty"`
+	ExtendedAddress    string     `xml:"extended-address,omitempty" json:"extended_address,omitempty"`
+	Locality           string     `xml:"locality,omitempty" json:"locality,omitempty"`
+	Region             string     `xml:"region,omitempty" json:"region,omitempty"`
+	PostalCode         string     `xml:"postal-code,omitempty" json:"postal_code,omitempty"`
+	CountryCodeAlpha2  string     `xml:"country-code-alpha2,omitempty" json:"country_code_alpha2,omitempty"`
+	CountryCodeAlpha3  string     `xml:"country-code-alpha3,omitempty" json:"country_code_alpha3,omitempty"`
+	CountryCodeNumeric string     `xml:"country-code-numeric,omitempty" json:"country_code_numeric,omitempty"`
+	CountryName        string     `xml:"country-name,omitempty" json:"country_name,omitempty"`
+	CreatedAt          *time.Time `xml:"created-at,omitempty" json:"created_at,omitempty"`
+	UpdatedAt          *time.Time `xml:"updated-at,omitempty" json:"updated_at,omitempty"`
 }
 
 type AddressRequest struct {
-	XMLName            xml.Name `xml:"address"`
-	FirstName          string   `xml:"first-name,omitempty"`
-	LastName           string   `xml:"last-name,omitempty"`
-	Company            string   `xml:"company,omitempty"`
-	StreetAddress      string   `xml:"street-address,omitempty"`
-	ExtendedAddress    string   `xml:"extended-address,omitempty"`
-	Locality           string   `xml:"locality,omitempty"`
-	Region             string   `xml:"region,omitempty"`
-	PostalCode         string   `xml:"postal-code,omitempty"`
-	CountryCodeAlpha2  string   `xml:"country-code-alpha2,omitempty"`
-	CountryCodeAlpha3  string   `xml:"country-code-alpha3,omitempty"`
-	CountryCodeNumeric string   `xml:"country-code-numeric,omitempty"`
-	CountryName        string   `xml:"country-name,omitempty"`
+	XMLName            xml.Name `xml:"address" json:"-"`
+	FirstName          string   `xml:"first-name,omitempty" json:"first_name,omitempty"`
+	LastName           string   `xml:"last-name,omitempty" json:"last_name,omitempty"`
+	Company            string   `xml:"company,omitempty" json:"company,omitempty"`
+	StreetAddress      string   `xml:"street-address,omitempty" json:"street_address,omitempty"`
+	ExtendedAddress    string   `xml:"extended-address,omitempty" json:"extended_address,omitempty"`
+	Locality           string   `xml:"locality,omitempty" json:"locality,omitempty"`
+	Region             string   `xml:"region,omitempty" json:"region,omitempty"`
+	PostalCode         string   `xml:"postal-code,omitempty" json:"postal_code,omitempty"`
+	CountryCodeAlpha2  string   `xml:"country-code-alpha2,omitempty" json:"country_code_alpha2,omitempty"`
+	CountryCodeAlpha3  string   `xml:"country-code-alpha3,omitempty" json:"country_code_alpha3,omitempty"`
+	CountryCodeNumeric string   `xml:"country-code-numeric,omitempty" json:"country_code_numeric,omitempty"`
+	CountryName        string   `xml:"country-name,omitempty" json:"country_name,omitempty"`
 }
 
 type Addresses struct {
-	XMLName string     `xml:"addresses"`
-	Address []*Address `xml:"address"`
+	XMLName string     `xml:"addresses" json:"-"`
+	Address []*Address `xml:"address" json:"address"`
 }
 
 const addressesPath = "addresses"
 
 func (c *APIClient) CreateAddress(ctx context.Context, custID string, request *AddressRequest) (*Address, error) {
-	response, err := c.do(ctx, http.MethodPost, customersPath+"/"+custID+"/"+addressesPath, &request)
+	response, err := c.do(ctx, "CreateAddress", http.MethodPost, customersPath+"/"+custID+"/"+addressesPath, &request)
 	if err != nil {
 		return nil, err
 	}
@@ -67,7 +67,7 @@ func (c *APIClient) CreateAddress(ctx context.Context, custID string, request *A
 }
 
 func (c *APIClient) DeleteAddress(ctx context.Context, custID, id string) error {
-	resp, err := c.do(ctx, http.MethodDelete, customersPath+"/"+custID+"/"+addressesPath+"/"+id, nil)
+	resp, err := c.do(ctx, "DeleteAddress", http.MethodDelete, customersPath+"/"+custID+"/"+addressesPath+"/"+id, nil)
 	if err != nil {
 		return err
 	}
@@ -79,7 +79,7 @@ func (c *APIClient) DeleteAddress(ctx context.Context, custID, id string) error
 }
 
 func (c *APIClient) UpdateAddress(ctx context.Context, custID, id string, request *AddressRequest) (*Address, error) {
-	response, err := c.do(ctx, http.MethodPut, customersPath+"/"+custID+"/"+addressesPath+"/"+id, request)
+	response, err := c.do(ctx, "UpdateAddress", http.MethodPut, customersPath+"/"+custID+"/"+addressesPath+"/"+id, request)
 	if err != nil {
 		return nil, err
 	}
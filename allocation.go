@@ -0,0 +1,126 @@
+package braintree
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+)
+
+// Allocate splits the decimal in parts proportional to the ratios, at the scale given, without losing any
+// unit : the units left once each part is rounded down go to the parts with the largest remainders, the first
+// ones on ties. The decimal must not have more decimals than the scale.
+//
+//	parts, err := NewDecimal(10000, 2).Allocate(2, NewDecimal(1, 0), NewDecimal(1, 0), NewDecimal(1, 0))
+//	// 33.34, 33.33, 33.33
+func (d *Decimal) Allocate(scale int, ratios ...*Decimal) ([]*Decimal, error) {
+	if len(ratios) == 0 {
+		return nil, errors.New("no ratios to allocate by")
+	}
+	total, err := d.Rescale(scale)
+	if err != nil {
+		return nil, err
+	}
+
+	// the ratios as integers, at their greatest scale
+	maxScale := 0
+	for i, ratio := range ratios {
+		if ratio == nil || ratio.Sign() < 0 {
+			return nil, errors.New("the ratios must be positive or zero")
+		}
+		if i == 0 || ratio.Scale > maxScale {
+			maxScale = ratio.Scale
+		}
+	}
+	weights := make([]*big.Int, len(ratios))
+	sum := new(big.Int)
+	for i, ratio := range ratios {
+		weights[i] = new(big.Int).Mul(big.NewInt(ratio.Unscaled), pow10(maxScale-ratio.Scale))
+		sum.Add(sum, weights[i])
+	}
+	if sum.Sign() == 0 {
+		return nil, errors.New("the ratios add up to zero")
+	}
+
+	units := new(big.Int).Abs(big.NewInt(total.Unscaled))
+	shares := make([]*big.Int, len(ratios))
+	remainders := make([]*big.Int, len(ratios))
+	left := new(big.Int).Set(units)
+	for i, weight := range weights {
+		shares[i], remainders[i] = new(big.Int).QuoRem(new(big.Int).Mul(units, weight), sum, new(big.Int))
+		left.Sub(left, shares[i])
+	}
+	order := make([]int, len(ratios))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]].Cmp(remainders[order[b]]) > 0
+	})
+	for i := 0; left.Sign() > 0; i++ {
+		shares[order[i]].Add(shares[order[i]], big.NewInt(1))
+		left.Sub(left, big.NewInt(1))
+	}
+
+	parts := make([]*Decimal, len(shares))
+	for i, share := range shares {
+		if total.Sign() < 0 {
+			share.Neg(share)
+		}
+		parts[i] = &Decimal{Unscaled: share.Int64(), Scale: scale}
+	}
+	return parts, nil
+}
+
+// Split allocates the decimal in n equal parts at the scale given, the first parts receiving the units left
+func (d *Decimal) Split(scale, n int) ([]*Decimal, error) {
+	if n < 1 {
+		return nil, errors.New("cannot split in less than one part")
+	}
+	ratios := make([]*Decimal, n)
+	for i := range ratios {
+		ratios[i] = NewDecimal(1, 0)
+	}
+	return d.Allocate(scale, ratios...)
+}
+
+// Allocate splits the money in parts proportional to the ratios, to the minor unit of its currency
+func (m Money) Allocate(ratios ...*Decimal) ([]Money, error) {
+	exponent, err := m.exponent()
+	if err != nil {
+		return nil, err
+	}
+	parts, err := m.Amount.Allocate(exponent, ratios...)
+	if err != nil {
+		return nil, err
+	}
+	return m.withAmounts(parts), nil
+}
+
+// Split allocates the money in n equal parts, to the minor unit of its currency
+func (m Money) Split(n int) ([]Money, error) {
+	exponent, err := m.exponent()
+	if err != nil {
+		return nil, err
+	}
+	parts, err := m.Amount.Split(exponent, n)
+	if err != nil {
+		return nil, err
+	}
+	return m.withAmounts(parts), nil
+}
+
+func (m Money) exponent() (int, error) {
+	if err := m.Validate(); err != nil {
+		return 0, err
+	}
+	exponent, _ := CurrencyExponent(m.Currency)
+	return exponent, nil
+}
+
+func (m Money) withAmounts(amounts []*Decimal) []Money {
+	result := make([]Money, len(amounts))
+	for i, amount := range amounts {
+		result[i] = Money{Amount: amount, Currency: m.Currency}
+	}
+	return result
+}
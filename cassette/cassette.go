@@ -0,0 +1,258 @@
+// Package cassette provides a record / replay http.RoundTripper for the braintree APIClient, so suites
+// which talk to the gateway can run offline. Credentials, the merchant id and card data are scrubbed before
+// anything is written to disk, and requests are matched on method, path and normalized XML body, tolerating the
+// random ids generated by tests.
+package cassette
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/badu/braintree"
+)
+
+type Mode int
+
+const (
+	// ModeReplay only replays recorded interactions, failing requests which were not recorded
+	ModeReplay Mode = iota
+	// ModeRecord always calls the gateway, recording every interaction
+	ModeRecord
+	// ModeAuto replays recorded interactions and records the ones which are missing
+	ModeAuto
+)
+
+var ErrNoInteraction = errors.New("cassette: no recorded interaction matches the request")
+
+type Request struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Body   string `json:"body,omitempty"`
+}
+
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type file struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Recorder is the http.RoundTripper which records and replays interactions
+type Recorder struct {
+	Path      string
+	Mode      Mode
+	Transport http.RoundTripper // used to reach the gateway when recording, defaults to braintree.DefaultClient's
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	keys         []string
+	used         []bool
+	dirty        bool
+}
+
+// New creates a recorder for the cassette file at path, loading the interactions already recorded there
+func New(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{Path: path, Mode: mode}
+	if mode == ModeRecord {
+		return r, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	switch {
+	case os.IsNotExist(err) && mode == ModeAuto:
+		return r, nil
+	case err != nil:
+		return nil, err
+	}
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("cassette %s : %v", path, err)
+	}
+	for _, interaction := range f.Interactions {
+		r.add(interaction)
+	}
+	r.dirty = false
+	return r, nil
+}
+
+func (r *Recorder) add(interaction *Interaction) {
+	r.interactions = append(r.interactions, interaction)
+	r.keys = append(r.keys, matchKey(interaction.Request.Method, interaction.Request.Path, interaction.Request.Body))
+	r.used = append(r.used, false)
+	r.dirty = true
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	path := scrubPath(req.URL)
+	merchant := merchantID(req.URL)
+	scrubbed := []byte(replaceMerchant(string(braintree.RedactXML(body)), merchant, merchantPlaceholder))
+
+	if r.Mode != ModeRecord {
+		if interaction := r.match(req.Method, path, scrubbed); interaction != nil {
+			return replay(req, interaction, path, scrubbed, merchant), nil
+		}
+		if r.Mode == ModeReplay {
+			return nil, fmt.Errorf("%w : %s %s", ErrNoInteraction, req.Method, path)
+		}
+	}
+
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	transport := r.Transport
+	if transport == nil {
+		transport = braintree.DefaultClient.Transport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readBody(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	header := resp.Header.Clone()
+	header.Del(braintree.HdrContentEncoding)
+	header.Del("Content-Length")
+	header.Del("Set-Cookie")
+	resp.Header = header.Clone()
+	replaceMerchantHeader(header, merchant, merchantPlaceholder)
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+	resp.ContentLength = int64(len(respBody))
+	resp.Uncompressed = true
+
+	r.mu.Lock()
+	r.add(&Interaction{
+		Request: Request{Method: req.Method, Path: path, Body: string(scrubbed)},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     header,
+			Body:       replaceMerchant(string(braintree.RedactXML(respBody)), merchant, merchantPlaceholder),
+		},
+	})
+	r.used[len(r.used)-1] = true
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// match returns the first unused interaction matching the request or, when all of them were used,
+// the last one, so polling the same resource keeps working
+func (r *Recorder) match(method, path string, body []byte) *Interaction {
+	key := matchKey(method, path, string(body))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	last := -1
+	for i, k := range r.keys {
+		if k != key {
+			continue
+		}
+		if !r.used[i] {
+			r.used[i] = true
+			return r.interactions[i]
+		}
+		last = i
+	}
+	if last >= 0 {
+		return r.interactions[last]
+	}
+	return nil
+}
+
+func replay(req *http.Request, interaction *Interaction, path string, body []byte, merchant string) *http.Response {
+	replacer := randomIdReplacer(
+		interaction.Request.Path+"\n"+canonical(interaction.Request.Body),
+		path+"\n"+canonical(string(body)),
+	)
+	respBody := replaceMerchant(replacer.Replace(interaction.Response.Body), merchantPlaceholder, merchant)
+	header := interaction.Response.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	replaceMerchantHeader(header, merchantPlaceholder, merchant)
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+		StatusCode:    interaction.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(respBody)),
+		ContentLength: int64(len(respBody)),
+		Request:       req,
+	}
+}
+
+// Save writes the cassette file, if anything was recorded since it was loaded
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.dirty {
+		return nil
+	}
+	data, err := json.MarshalIndent(&file{Interactions: r.interactions}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.Path), 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(r.Path, data, 0644); err != nil {
+		return err
+	}
+	r.dirty = false
+	return nil
+}
+
+// Unused returns the recorded interactions which were not replayed
+func (r *Recorder) Unused() []*Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*Interaction
+	for i, used := range r.used {
+		if !used {
+			result = append(result, r.interactions[i])
+		}
+	}
+	return result
+}
+
+func readBody(resp *http.Response) ([]byte, error) {
+	defer func() { _ = resp.Body.Close() }()
+	reader := resp.Body
+	if strings.EqualFold(resp.Header.Get(braintree.HdrContentEncoding), "gzip") {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		reader = gzipReader
+	}
+	return ioutil.ReadAll(reader)
+}
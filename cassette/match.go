@@ -0,0 +1,171 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const merchantPlaceholder = "MERCHANT"
+
+var (
+	merchantSegment = regexp.MustCompile(`/merchants/([^/]+)/`)
+	// randomId matches the ids generated by tests, which are formatted random int64 values
+	randomId = regexp.MustCompile(`-?\d{10,}`)
+	// encodedValue matches the base64 values, e.g. of the client tokens, which carry the merchant id as well
+	encodedValue = regexp.MustCompile(`<value>([A-Za-z0-9+/]+=*)</value>`)
+)
+
+// scrubPath removes the host and the merchant id from the request URL
+func scrubPath(u *url.URL) string {
+	path := merchantSegment.ReplaceAllString(u.Path, "/merchants/"+merchantPlaceholder+"/")
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	return path
+}
+
+// merchantID returns the merchant id found in the request URL, if any
+func merchantID(u *url.URL) string {
+	if match := merchantSegment.FindStringSubmatch(u.Path); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// replaceMerchant replaces the merchant id in the bodies and the header values, e.g. with the placeholder
+// before writing them to the cassette, and back with the live merchant id when replaying
+func replaceMerchant(s, from, to string) string {
+	if from == "" || from == to {
+		return s
+	}
+	s = encodedValue.ReplaceAllStringFunc(s, func(value string) string {
+		decoded, err := base64.StdEncoding.DecodeString(encodedValue.FindStringSubmatch(value)[1])
+		if err != nil || !bytes.Contains(decoded, []byte(from)) {
+			return value
+		}
+		return "<value>" + base64.StdEncoding.EncodeToString(bytes.ReplaceAll(decoded, []byte(from), []byte(to))) + "</value>"
+	})
+	return strings.ReplaceAll(s, from, to)
+}
+
+func replaceMerchantHeader(header http.Header, from, to string) {
+	for _, values := range header {
+		for i, value := range values {
+			values[i] = replaceMerchant(value, from, to)
+		}
+	}
+}
+
+func matchKey(method, path, body string) string {
+	return method + " " + normalize(path) + "\n" + normalize(canonical(body))
+}
+
+func normalize(s string) string {
+	return randomId.ReplaceAllString(s, "#")
+}
+
+// randomIdReplacer maps the random ids found in the recorded request to the ones found in the live one.
+// Both are canonical forms, so ids appear in the same order in both.
+func randomIdReplacer(recorded, live string) *strings.Replacer {
+	from, to := randomId.FindAllString(recorded, -1), randomId.FindAllString(live, -1)
+	var pairs []string
+	seen := map[string]bool{}
+	for i := 0; i < len(from) && i < len(to); i++ {
+		if from[i] == to[i] || seen[from[i]] {
+			continue
+		}
+		seen[from[i]] = true
+		pairs = append(pairs, from[i], to[i])
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+type node struct {
+	name     string
+	attrs    []string
+	text     string
+	children []*node
+}
+
+// canonical returns a form of the XML body which does not depend on formatting, on the order of the
+// elements (custom fields are encoded from a map) or on timestamps. Non XML bodies are returned as they are.
+func canonical(body string) string {
+	if strings.TrimSpace(body) == "" {
+		return ""
+	}
+	decoder := xml.NewDecoder(strings.NewReader(body))
+	root := &node{}
+	stack := []*node{root}
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return body
+		}
+		current := stack[len(stack)-1]
+		switch t := token.(type) {
+		case xml.StartElement:
+			n := &node{name: t.Name.Local}
+			for _, attr := range t.Attr {
+				n.attrs = append(n.attrs, attr.Name.Local+"="+attr.Value)
+			}
+			sort.Strings(n.attrs)
+			current.children = append(current.children, n)
+			stack = append(stack, n)
+		case xml.EndElement:
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			current.text += strings.TrimSpace(string(t))
+		}
+	}
+	var b bytes.Buffer
+	for _, child := range sortChildren(root.children) {
+		child.write(&b)
+	}
+	return b.String()
+}
+
+func sortChildren(children []*node) []*node {
+	keys := make(map[*node]string, len(children))
+	for _, child := range children {
+		var b bytes.Buffer
+		child.write(&b)
+		keys[child] = normalize(b.String())
+	}
+	sort.SliceStable(children, func(i, j int) bool {
+		return keys[children[i]] < keys[children[j]]
+	})
+	return children
+}
+
+func (n *node) write(b *bytes.Buffer) {
+	b.WriteString("<" + n.name)
+	isTime := false
+	for _, attr := range n.attrs {
+		b.WriteString(" " + attr)
+		if attr == "type=datetime" {
+			isTime = true
+		}
+	}
+	b.WriteString(">")
+	if isTime {
+		b.WriteString("TIME")
+	} else {
+		b.WriteString(n.text)
+	}
+	for _, child := range sortChildren(n.children) {
+		child.write(b)
+	}
+	b.WriteString("</" + n.name + ">")
+}
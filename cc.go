@@ -12,7 +12,7 @@ import (
 const monthAndYear = "012006"
 
 func (c *APIClient) CreateCard(ctx context.Context, card *CreditCard) (*CreditCard, error) {
-	response, err := c.do(ctx, http.MethodPost, paymentMethodsPath, card)
+	response, err := c.do(ctx, "CreateCard", http.MethodPost, paymentMethodsPath, card)
 	if err != nil {
 		return nil, err
 	}
@@ -28,7 +28,7 @@ func (c *APIClient) CreateCard(ctx context.Context, card *CreditCard) (*CreditCa
 }
 
 func (c *APIClient) UpdateCard(ctx context.Context, card *CreditCard) (*CreditCard, error) {
-	response, err := c.do(ctx, http.MethodPut, paymentMethodsPath+"/"+card.Token, card)
+	response, err := c.do(ctx, "UpdateCard", http.MethodPut, paymentMethodsPath+"/"+card.Token, card)
 	if err != nil {
 		return nil, err
 	}
@@ -44,7 +44,7 @@ func (c *APIClient) UpdateCard(ctx context.Context, card *CreditCard) (*CreditCa
 }
 
 func (c *APIClient) FindCard(ctx context.Context, token string) (*CreditCard, error) {
-	response, err := c.do(ctx, http.MethodGet, paymentMethodsPath+"/"+token, nil)
+	response, err := c.do(ctx, "FindCard", http.MethodGet, paymentMethodsPath+"/"+token, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -60,7 +60,7 @@ func (c *APIClient) FindCard(ctx context.Context, token string) (*CreditCard, er
 }
 
 func (c *APIClient) DeleteCard(ctx context.Context, card *CreditCard) error {
-	resp, err := c.do(ctx, http.MethodDelete, paymentMethodsPath+"/"+card.Token, nil)
+	resp, err := c.do(ctx, "DeleteCard", http.MethodDelete, paymentMethodsPath+"/"+card.Token, nil)
 	if err != nil {
 		return err
 	}
@@ -75,7 +75,7 @@ func (c *APIClient) ExpiringBetween(ctx context.Context, fromDate, toDate time.T
 	qs := url.Values{}
 	qs.Set("start", fromDate.UTC().Format(monthAndYear))
 	qs.Set("end", toDate.UTC().Format(monthAndYear))
-	resp, err := c.do(ctx, http.MethodPost, paymentMethodsPath+"/all/expiring_ids?"+qs.Encode(), nil)
+	resp, err := c.do(ctx, "ExpiringBetween", http.MethodPost, paymentMethodsPath+"/all/expiring_ids?"+qs.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -110,7 +110,7 @@ func (c *APIClient) ExpiringBetweenPaged(ctx context.Context, fromDate, toDate t
 
 	pageQuery := &Search{}
 	pageQuery.AddMultiField("ids").Items = result.IDs[startOffset:endOffset]
-	creditCards, err := c.fetchExpiringBetween(ctx, fromDate, toDate, pageQuery)
+	creditCards, err := c.fetchExpiringBetween(ctx, "ExpiringBetweenPaged", fromDate, toDate, pageQuery)
 
 	pageResult := &CreditCardSearchResult{
 		TotalItems:        len(result.IDs),
@@ -123,11 +123,11 @@ func (c *APIClient) ExpiringBetweenPaged(ctx context.Context, fromDate, toDate t
 	return pageResult, err
 }
 
-func (c *APIClient) fetchExpiringBetween(ctx context.Context, fromDate, toDate time.Time, query *Search) ([]*CreditCard, error) {
+func (c *APIClient) fetchExpiringBetween(ctx context.Context, operation string, fromDate, toDate time.Time, query *Search) ([]*CreditCard, error) {
 	qs := url.Values{}
 	qs.Set("start", fromDate.UTC().Format(monthAndYear))
 	qs.Set("end", toDate.UTC().Format(monthAndYear))
-	resp, err := c.do(ctx, http.MethodPost, paymentMethodsPath+"/all/expiring?"+qs.Encode(), query)
+	resp, err := c.do(ctx, operation, http.MethodPost, paymentMethodsPath+"/all/expiring?"+qs.Encode(), query)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,233 @@
+package braintree
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultDurationBuckets are the upper bounds, in seconds, of the call duration histogram
+var DefaultDurationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// DefaultMaxCalls is the number of most recent calls a MemoryCollector keeps when MaxCalls is not set
+const DefaultMaxCalls = 1000
+
+type durationHistogram struct {
+	bounds []float64 // the Buckets when the operation was first seen
+	counts []uint64  // cumulative, one per bound
+	sum    float64
+	count  uint64
+}
+
+// MemoryCollector is an Instrumentation which keeps aggregated metrics and the most recent calls in memory.
+// It is meant for tests and for exposing metrics in the Prometheus text format, without any dependency.
+type MemoryCollector struct {
+	// Buckets are the upper bounds of the duration histograms. Changing them only affects the operations
+	// seen for the first time afterwards
+	Buckets []float64
+	// MaxCalls is the number of most recent calls kept, DefaultMaxCalls when not set. The aggregated
+	// metrics account for every call
+	MaxCalls int
+
+	mu                 sync.Mutex
+	calls              []*CallMetrics // a ring, the oldest call at next once full
+	next               int
+	active             int
+	requests           map[[2]string]uint64 // operation, status
+	retries            map[string]uint64    // operation
+	validationErrors   map[[2]string]uint64 // operation, code
+	processorResponses map[[2]string]uint64 // operation, response type
+	durations          map[string]*durationHistogram
+}
+
+func NewMemoryCollector() *MemoryCollector {
+	return &MemoryCollector{Buckets: DefaultDurationBuckets, MaxCalls: DefaultMaxCalls}
+}
+
+func (m *MemoryCollector) StartSpan(ctx context.Context, span *Span) context.Context {
+	m.mu.Lock()
+	m.active++
+	m.mu.Unlock()
+	return ctx
+}
+
+func (m *MemoryCollector) EndSpan(ctx context.Context, metrics *CallMetrics) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.requests == nil {
+		m.requests = map[[2]string]uint64{}
+		m.retries = map[string]uint64{}
+		m.validationErrors = map[[2]string]uint64{}
+		m.processorResponses = map[[2]string]uint64{}
+		m.durations = map[string]*durationHistogram{}
+	}
+
+	m.active--
+	m.record(metrics)
+
+	op := metrics.Operation
+	status := "none"
+	if metrics.StatusCode > 0 {
+		status = strconv.Itoa(metrics.StatusCode)
+	}
+	m.requests[[2]string{op, status}]++
+	if retries := metrics.Retries(); retries > 0 {
+		m.retries[op] += uint64(retries)
+	}
+	for _, code := range metrics.ValidationCodes {
+		m.validationErrors[[2]string{op, code}]++
+	}
+	if metrics.ProcessorResponseType != "" {
+		m.processorResponses[[2]string{op, string(metrics.ProcessorResponseType)}]++
+	}
+
+	h, ok := m.durations[op]
+	if !ok {
+		bounds := append([]float64(nil), m.Buckets...)
+		h = &durationHistogram{bounds: bounds, counts: make([]uint64, len(bounds))}
+		m.durations[op] = h
+	}
+	seconds := metrics.Duration.Seconds()
+	for i, upper := range h.bounds {
+		if seconds <= upper {
+			h.counts[i]++
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+func (m *MemoryCollector) record(metrics *CallMetrics) {
+	max := m.MaxCalls
+	if max <= 0 {
+		max = DefaultMaxCalls
+	}
+	if len(m.calls) < max {
+		m.calls = append(m.calls, metrics)
+		return
+	}
+	if len(m.calls) > max {
+		// MaxCalls was lowered, keep the most recent ones
+		m.calls = append(m.calls[m.next:], m.calls[:m.next]...)
+		m.calls, m.next = m.calls[len(m.calls)-max:], 0
+	}
+	m.calls[m.next] = metrics
+	m.next = (m.next + 1) % max
+}
+
+// Calls returns a copy of the most recent calls, in the order they ended
+func (m *MemoryCollector) Calls() []*CallMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make([]*CallMetrics, 0, len(m.calls))
+	result = append(result, m.calls[m.next:]...)
+	return append(result, m.calls[:m.next]...)
+}
+
+// Active returns the number of calls which started but did not end yet
+func (m *MemoryCollector) Active() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.active
+}
+
+func (m *MemoryCollector) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = nil
+	m.next = 0
+	m.requests = nil
+	m.retries = nil
+	m.validationErrors = nil
+	m.processorResponses = nil
+	m.durations = nil
+}
+
+// WritePrometheus writes the aggregated metrics in the Prometheus text exposition format
+func (m *MemoryCollector) WritePrometheus(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+
+	writeHeader(bw, "braintree_requests_total", "counter", "Gateway calls by operation and HTTP status.")
+	for _, key := range sortedPairs(m.requests) {
+		fmt.Fprintf(bw, "braintree_requests_total{operation=%s,status=%s} %d\n", quoteLabel(key[0]), quoteLabel(key[1]), m.requests[key])
+	}
+
+	writeHeader(bw, "braintree_retries_total", "counter", "Retried gateway call attempts by operation.")
+	for _, op := range sortedKeys(m.retries) {
+		fmt.Fprintf(bw, "braintree_retries_total{operation=%s} %d\n", quoteLabel(op), m.retries[op])
+	}
+
+	writeHeader(bw, "braintree_validation_errors_total", "counter", "Validation errors returned by the gateway by operation and code.")
+	for _, key := range sortedPairs(m.validationErrors) {
+		fmt.Fprintf(bw, "braintree_validation_errors_total{operation=%s,code=%s} %d\n", quoteLabel(key[0]), quoteLabel(key[1]), m.validationErrors[key])
+	}
+
+	writeHeader(bw, "braintree_processor_responses_total", "counter", "Processor responses by operation and response type.")
+	for _, key := range sortedPairs(m.processorResponses) {
+		fmt.Fprintf(bw, "braintree_processor_responses_total{operation=%s,type=%s} %d\n", quoteLabel(key[0]), quoteLabel(key[1]), m.processorResponses[key])
+	}
+
+	writeHeader(bw, "braintree_request_duration_seconds", "histogram", "Gateway call latency by operation, including retries.")
+	ops := make([]string, 0, len(m.durations))
+	for op := range m.durations {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+	for _, op := range ops {
+		h := m.durations[op]
+		for i, upper := range h.bounds {
+			fmt.Fprintf(bw, "braintree_request_duration_seconds_bucket{operation=%s,le=%s} %d\n", quoteLabel(op), quoteLabel(formatFloat(upper)), h.counts[i])
+		}
+		fmt.Fprintf(bw, "braintree_request_duration_seconds_bucket{operation=%s,le=\"+Inf\"} %d\n", quoteLabel(op), h.count)
+		fmt.Fprintf(bw, "braintree_request_duration_seconds_sum{operation=%s} %s\n", quoteLabel(op), formatFloat(h.sum))
+		fmt.Fprintf(bw, "braintree_request_duration_seconds_count{operation=%s} %d\n", quoteLabel(op), h.count)
+	}
+
+	return bw.Flush()
+}
+
+func writeHeader(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+var labelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func quoteLabel(value string) string {
+	return `"` + labelReplacer.Replace(value) + `"`
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func sortedKeys(m map[string]uint64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedPairs(m map[[2]string]uint64) [][2]string {
+	keys := make([][2]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	return keys
+}
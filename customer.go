@@ -15,29 +15,29 @@ const advancedSearchPath = "advanced_search"
 const advancedSearchIdsPath = "advanced_search_ids"
 
 type AndroidPayCard struct {
-	XMLName             xml.Name       `xml:"android-pay-card"`
-	Token               string         `xml:"token"`
-	CardType            string         `xml:"-"`
-	Last4               string         `xml:"-"`
-	SourceCardType      string         `xml:"source-card-type"`
-	SourceCardLast4     string         `xml:"source-card-last-4"`
-	SourceDescription   string         `xml:"source-description"`
-	VirtualCardType     string         `xml:"virtual-card-type"`
-	VirtualCardLast4    string         `xml:"virtual-card-last-4"`
-	ExpirationMonth     string         `xml:"expiration-month"`
-	ExpirationYear      string         `xml:"expiration-year"`
-	BIN                 string         `xml:"bin"`
-	GoogleTransactionID string         `xml:"google-transaction-id"`
-	ImageURL            string         `xml:"image-url"`
-	Default             bool           `xml:"default"`
-	CustomerId          string         `xml:"customer-id"`
-	CreatedAt           *time.Time     `xml:"created-at"`
-	UpdatedAt           *time.Time     `xml:"updated-at"`
-	Subscriptions       *Subscriptions `xml:"subscriptions"`
+	XMLName             xml.Name       `xml:"android-pay-card" json:"-"`
+	Token               string         `xml:"token" json:"token"`
+	CardType            string         `xml:"-" json:"-"`
+	Last4               string         `xml:"-" json:"-"`
+	SourceCardType      string         `xml:"source-card-type" json:"source_card_type"`
+	SourceCardLast4     string         `xml:"source-card-last-4" json:"source_card_last_4"`
+	SourceDescription   string         `xml:"source-description" json:"source_description"`
+	VirtualCardType     string         `xml:"virtual-card-type" json:"virtual_card_type"`
+	VirtualCardLast4    string         `xml:"virtual-card-last-4" json:"virtual_card_last_4"`
+	ExpirationMonth     string         `xml:"expiration-month" json:"expiration_month"`
+	ExpirationYear      string         `xml:"expiration-year" json:"expiration_year"`
+	BIN                 string         `xml:"bin" json:"bin"`
+	GoogleTransactionID string         `xml:"google-transaction-id" json:"google_transaction_id"`
+	ImageURL            string         `xml:"image-url" json:"image_url"`
+	Default             bool           `xml:"default" json:"default"`
+	CustomerId          string         `xml:"customer-id" json:"customer_id"`
+	CreatedAt           *time.Time     `xml:"created-at" json:"created_at"`
+	UpdatedAt           *time.Time     `xml:"updated-at" json:"updated_at"`
+	Subscriptions       *Subscriptions `xml:"subscriptions" json:"subscriptions"`
 }
 
 type AndroidPayCards struct {
-	AndroidPayCard []*AndroidPayCard `xml:"android-pay-card"`
+	AndroidPayCard []*AndroidPayCard `xml:"android-pay-card" json:"android_pay_card"`
 }
 
 func (a *AndroidPayCards) PaymentMethods() []PaymentMethod {
@@ -90,26 +90,26 @@ func (a *AndroidPayCard) UnmarshalXML(decoder *xml.Decoder, start xml.StartEleme
 }
 
 type ApplePayCard struct {
-	XMLName               xml.Name       `xml:"apple-pay-card"`
-	Token                 string         `xml:"token"`
-	ImageURL              string         `xml:"image-url"`
-	CardType              string         `xml:"card-type"`
-	PaymentInstrumentName string         `xml:"payment-instrument-name"`
-	SourceDescription     string         `xml:"source-description"`
-	BIN                   string         `xml:"bin"`
-	Last4                 string         `xml:"last-4"`
-	ExpirationMonth       string         `xml:"expiration-month"`
-	ExpirationYear        string         `xml:"expiration-year"`
-	Expired               bool           `xml:"expired"`
-	Default               bool           `xml:"default"`
-	CustomerId            string         `xml:"customer-id"`
-	CreatedAt             *time.Time     `xml:"created-at"`
-	UpdatedAt             *time.Time     `xml:"updated-at"`
-	Subscriptions         *Subscriptions `xml:"subscriptions"`
+	XMLName               xml.Name       `xml:"apple-pay-card" json:"-"`
+	Token                 string         `xml:"token" json:"token"`
+	ImageURL              string         `xml:"image-url" json:"image_url"`
+	CardType              string         `xml:"card-type" json:"card_type"`
+	PaymentInstrumentName string         `xml:"payment-instrument-name" json:"payment_instrument_name"`
+	SourceDescription     string         `xml:"source-description" json:"source_description"`
+	BIN                   string         `xml:"bin" json:"bin"`
+	Last4                 string         `xml:"last-4" json:"last_4"`
+	ExpirationMonth       string         `xml:"expiration-month" json:"expiration_month"`
+	ExpirationYear        string         `xml:"expiration-year" json:"expiration_year"`
+	Expired               bool           `xml:"expired" json:"expired"`
+	Default               bool           `xml:"default" json:"default"`
+	CustomerId            string         `xml:"customer-id" json:"customer_id"`
+	CreatedAt             *time.Time     `xml:"created-at" json:"created_at"`
+	UpdatedAt             *time.Time     `xml:"updated-at" json:"updated_at"`
+	Subscriptions         *Subscriptions `xml:"subscriptions" json:"subscriptions"`
 }
 
 type ApplePayCards struct {
-	Cards []*ApplePayCard `xml:"apple-pay-card"`
+	Cards []*ApplePayCard `xml:"apple-pay-card" json:"apple_pay_card"`
 }
 
 func (a *ApplePayCards) PaymentMethods() []PaymentMethod {
@@ -152,26 +152,26 @@ func (a *ApplePayCard) AllSubscriptions() []*Subscription {
 }
 
 type Customer struct {
-	XMLName            string           `xml:"customer"`
-	Id                 string           `xml:"id"`
-	FirstName          string           `xml:"first-name"`
-	LastName           string           `xml:"last-name"`
-	Company            string           `xml:"company"`
-	Email              string           `xml:"email"`
-	Phone              string           `xml:"phone"`
-	Fax                string           `xml:"fax"`
-	Website            string           `xml:"website"`
-	PaymentMethodNonce string           `xml:"payment-method-nonce"`
-	CustomFields       CustomFields     `xml:"custom-fields"`
-	CreditCard         *CreditCard      `xml:"credit-card"`
-	CreditCards        *CreditCards     `xml:"credit-cards"`
-	PayPalAccounts     *PayPalAccounts  `xml:"paypal-accounts"`
-	VenmoAccounts      *VenmoAccounts   `xml:"venmo-accounts"`
-	AndroidPayCards    *AndroidPayCards `xml:"android-pay-cards"`
-	ApplePayCards      *ApplePayCards   `xml:"apple-pay-cards"`
-	Addresses          *Addresses       `xml:"addresses"`
-	CreatedAt          *time.Time       `xml:"created-at"`
-	UpdatedAt          *time.Time       `xml:"updated-at"`
+	XMLName            string           `xml:"customer" json:"-"`
+	Id                 string           `xml:"id" json:"id"`
+	FirstName          string           `xml:"first-name" json:"first_name"`
+	LastName           string           `xml:"last-name" json:"last_name"`
+	Company            string           `xml:"company" json:"company"`
+	Email              string           `xml:"email" json:"email"`
+	Phone              string           `xml:"phone" json:"phone"`
+	Fax                string           `xml:"fax" json:"fax"`
+	Website            string           `xml:"website" json:"website"`
+	PaymentMethodNonce string           `xml:"payment-method-nonce" json:"payment_method_nonce"`
+	CustomFields       CustomFields     `xml:"custom-fields" json:"custom_fields"`
+	CreditCard         *CreditCard      `xml:"credit-card" json:"credit_card"`
+	CreditCards        *CreditCards     `xml:"credit-cards" json:"credit_cards"`
+	PayPalAccounts     *PayPalAccounts  `xml:"paypal-accounts" json:"paypal_accounts"`
+	VenmoAccounts      *VenmoAccounts   `xml:"venmo-accounts" json:"venmo_accounts"`
+	AndroidPayCards    *AndroidPayCards `xml:"android-pay-cards" json:"android_pay_cards"`
+	ApplePayCards      *ApplePayCards   `xml:"apple-pay-cards" json:"apple_pay_cards"`
+	Addresses          *Addresses       `xml:"addresses" json:"addresses"`
+	CreatedAt          *time.Time       `xml:"created-at" json:"created_at"`
+	UpdatedAt          *time.Time       `xml:"updated-at" json:"updated_at"`
 }
 
 func (c *Customer) PaymentMethods() []PaymentMethod {
@@ -203,7 +203,7 @@ func (c *Customer) DefaultPaymentMethod() *PaymentMethod {
 }
 
 type CreditCards struct {
-	CreditCard []*CreditCard `xml:"credit-card"`
+	CreditCard []*CreditCard `xml:"credit-card" json:"credit_card"`
 }
 
 func (c *CreditCards) PaymentMethods() []PaymentMethod {
@@ -223,12 +223,12 @@ func (c *CreditCards) PaymentMethods() []PaymentMethod {
 }
 
 type CreditCardOptions struct {
-	VenmoSDKSession               string `xml:"venmo-sdk-session,omitempty"`
-	VerificationMerchantAccountId string `xml:"verification-merchant-account-id,omitempty"`
-	UpdateExistingToken           string `xml:"update-existing-token,omitempty"`
-	MakeDefault                   bool   `xml:"make-default,omitempty"`
-	FailOnDuplicatePaymentMethod  bool   `xml:"fail-on-duplicate-payment-method,omitempty"`
-	VerifyCard                    *bool  `xml:"verify-card,omitempty"`
+	VenmoSDKSession               string `xml:"venmo-sdk-session,omitempty" json:"venmo_sdk_session,omitempty"`
+	VerificationMerchantAccountId string `xml:"verification-merchant-account-id,omitempty" json:"verification_merchant_account_id,omitempty"`
+	UpdateExistingToken           string `xml:"update-existing-token,omitempty" json:"update_existing_token,omitempty"`
+	MakeDefault                   bool   `xml:"make-default,omitempty" json:"make_default,omitempty"`
+	FailOnDuplicatePaymentMethod  bool   `xml:"fail-on-duplicate-payment-method,omitempty" json:"fail_on_duplicate_payment_method,omitempty"`
+	VerifyCard                    *bool  `xml:"verify-card,omitempty" json:"verify_card,omitempty"`
 }
 
 func (c *CreditCard) AllSubscriptions() []*Subscription {
@@ -255,48 +255,50 @@ func (cc *CreditCard) ToPaymentMethod() *PaymentMethod {
 }
 
 type CreditCardSearchResult struct {
-	TotalItems        int
-	CurrentPageNumber int
-	PageSize          int
-	TotalIDs          []string
-	CreditCards       []*CreditCard
+	TotalItems        int           `json:"total_items"`
+	CurrentPageNumber int           `json:"current_page_number"`
+	PageSize          int           `json:"page_size"`
+	TotalIDs          []string      `json:"total_ids"`
+	CreditCards       []*CreditCard `json:"credit_cards"`
 }
 
+// CreditCard is a card sent to or returned by the gateway. Its number and CVV are left out of its JSON form,
+// which may be stored or served
 type CreditCard struct {
-	XMLName                   xml.Name           `xml:"credit-card"`
-	CustomerId                string             `xml:"customer-id,omitempty"`
-	Token                     string             `xml:"token,omitempty"`
-	PaymentMethodNonce        string             `xml:"payment-method-nonce,omitempty"`
-	Number                    string             `xml:"number,omitempty"`
-	ExpirationDate            string             `xml:"expiration-date,omitempty"`
-	ExpirationMonth           string             `xml:"expiration-month,omitempty"`
-	ExpirationYear            string             `xml:"expiration-year,omitempty"`
-	CVV                       string             `xml:"cvv,omitempty"`
-	VenmoSDKPaymentMethodCode string             `xml:"venmo-sdk-payment-method-code,omitempty"`
-	Last4                     string             `xml:"last-4,omitempty"`
-	Commercial                string             `xml:"commercial,omitempty"`
-	Debit                     string             `xml:"debit,omitempty"`
-	DurbinRegulated           string             `xml:"durbin-regulated,omitempty"`
-	Healthcare                string             `xml:"healthcare,omitempty"`
-	Payroll                   string             `xml:"payroll,omitempty"`
-	Prepaid                   string             `xml:"prepaid,omitempty"`
-	CountryOfIssuance         string             `xml:"country-of-issuance,omitempty"`
-	IssuingBank               string             `xml:"issuing-bank,omitempty"`
-	UniqueNumberIdentifier    string             `xml:"unique-number-identifier,omitempty"`
-	Bin                       string             `xml:"bin,omitempty"`
-	CardType                  string             `xml:"card-type,omitempty"`
-	CardholderName            string             `xml:"cardholder-name,omitempty"`
-	CustomerLocation          string             `xml:"customer-location,omitempty"`
-	ImageURL                  string             `xml:"image-url,omitempty"`
-	ProductID                 string             `xml:"product-id,omitempty"`
-	VenmoSDK                  bool               `xml:"venmo-sdk,omitempty"`
-	Default                   bool               `xml:"default,omitempty"`
-	Expired                   bool               `xml:"expired,omitempty"`
-	Options                   *CreditCardOptions `xml:"options,omitempty"`
-	UpdatedAt                 *time.Time         `xml:"updated-at,omitempty"`
-	CreatedAt                 *time.Time         `xml:"created-at,omitempty"`
-	BillingAddress            *Address           `xml:"billing-address,omitempty"`
-	Subscriptions             *Subscriptions     `xml:"subscriptions,omitempty"`
+	XMLName                   xml.Name           `xml:"credit-card" json:"-"`
+	CustomerId                string             `xml:"customer-id,omitempty" json:"customer_id,omitempty"`
+	Token                     string             `xml:"token,omitempty" json:"token,omitempty"`
+	PaymentMethodNonce        string             `xml:"payment-method-nonce,omitempty" json:"payment_method_nonce,omitempty"`
+	Number                    string             `xml:"number,omitempty" json:"-"`
+	ExpirationDate            string             `xml:"expiration-date,omitempty" json:"expiration_date,omitempty"`
+	ExpirationMonth           string             `xml:"expiration-month,omitempty" json:"expiration_month,omitempty"`
+	ExpirationYear            string             `xml:"expiration-year,omitempty" json:"expiration_year,omitempty"`
+	CVV                       string             `xml:"cvv,omitempty" json:"-"`
+	VenmoSDKPaymentMethodCode string             `xml:"venmo-sdk-payment-method-code,omitempty" json:"venmo_sdk_payment_method_code,omitempty"`
+	Last4                     string             `xml:"last-4,omitempty" json:"last_4,omitempty"`
+	Commercial                string             `xml:"commercial,omitempty" json:"commercial,omitempty"`
+	Debit                     string             `xml:"debit,omitempty" json:"debit,omitempty"`
+	DurbinRegulated           string             `xml:"durbin-regulated,omitempty" json:"durbin_regulated,omitempty"`
+	Healthcare                string             `xml:"healthcare,omitempty" json:"healthcare,omitempty"`
+	Payroll                   string             `xml:"payroll,omitempty" json:"payroll,omitempty"`
+	Prepaid                   string             `xml:"prepaid,omitempty" json:"prepaid,omitempty"`
+	CountryOfIssuance         string             `xml:"country-of-issuance,omitempty" json:"country_of_issuance,omitempty"`
+	IssuingBank               string             `xml:"issuing-bank,omitempty" json:"issuing_bank,omitempty"`
+	UniqueNumberIdentifier    string             `xml:"unique-number-identifier,omitempty" json:"unique_number_identifier,omitempty"`
+	Bin                       string             `xml:"bin,omitempty" json:"bin,omitempty"`
+	CardType                  string             `xml:"card-type,omitempty" json:"card_type,omitempty"`
+	CardholderName            string             `xml:"cardholder-name,omitempty" json:"cardholder_name,omitempty"`
+	CustomerLocation          string             `xml:"customer-location,omitempty" json:"customer_location,omitempty"`
+	ImageURL                  string             `xml:"image-url,omitempty" json:"image_url,omitempty"`
+	ProductID                 string             `xml:"product-id,omitempty" json:"product_id,omitempty"`
+	VenmoSDK                  bool               `xml:"venmo-sdk,omitempty" json:"venmo_sdk,omitempty"`
+	Default                   bool               `xml:"default,omitempty" json:"default,omitempty"`
+	Expired                   bool               `xml:"expired,omitempty" json:"expired,omitempty"`
+	Options                   *CreditCardOptions `xml:"options,omitempty" json:"options,omitempty"`
+	UpdatedAt                 *time.Time         `xml:"updated-at,omitempty" json:"updated_at,omitempty"`
+	CreatedAt                 *time.Time         `xml:"created-at,omitempty" json:"created_at,omitempty"`
+	BillingAddress            *Address           `xml:"billing-address,omitempty" json:"billing_address,omitempty"`
+	Subscriptions             *Subscriptions     `xml:"subscriptions,omitempty" json:"subscriptions,omitempty"`
 }
 
 type xmlField struct {
@@ -352,30 +354,30 @@ func (c *CustomFields) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement
 }
 
 type CustomerRequest struct {
-	XMLName            string       `xml:"customer"`
-	ID                 string       `xml:"id,omitempty"`
-	FirstName          string       `xml:"first-name,omitempty"`
-	LastName           string       `xml:"last-name,omitempty"`
-	Company            string       `xml:"company,omitempty"`
-	Email              string       `xml:"email,omitempty"`
-	Phone              string       `xml:"phone,omitempty"`
-	Fax                string       `xml:"fax,omitempty"`
-	Website            string       `xml:"website,omitempty"`
-	PaymentMethodNonce string       `xml:"payment-method-nonce,omitempty"`
-	CustomFields       CustomFields `xml:"custom-fields,omitempty"`
-	CreditCard         *CreditCard  `xml:"credit-card,omitempty"`
+	XMLName            string       `xml:"customer" json:"-"`
+	ID                 string       `xml:"id,omitempty" json:"id,omitempty"`
+	FirstName          string       `xml:"first-name,omitempty" json:"first_name,omitempty"`
+	LastName           string       `xml:"last-name,omitempty" json:"last_name,omitempty"`
+	Company            string       `xml:"company,omitempty" json:"company,omitempty"`
+	Email              string       `xml:"email,omitempty" json:"email,omitempty"`
+	Phone              string       `xml:"phone,omitempty" json:"phone,omitempty"`
+	Fax                string       `xml:"fax,omitempty" json:"fax,omitempty"`
+	Website            string       `xml:"website,omitempty" json:"website,omitempty"`
+	PaymentMethodNonce string       `xml:"payment-method-nonce,omitempty" json:"payment_method_nonce,omitempty"`
+	CustomFields       CustomFields `xml:"custom-fields,omitempty" json:"custom_fields,omitempty"`
+	CreditCard         *CreditCard  `xml:"credit-card,omitempty" json:"credit_card,omitempty"`
 }
 
 type CustomerSearchResult struct {
-	TotalItems        int
-	CurrentPageNumber int
-	PageSize          int
-	TotalIDs          []string
-	Customers         []*Customer
+	TotalItems        int         `json:"total_items"`
+	CurrentPageNumber int         `json:"current_page_number"`
+	PageSize          int         `json:"page_size"`
+	TotalIDs          []string    `json:"total_ids"`
+	Customers         []*Customer `json:"customers"`
 }
 
 func (c *APIClient) CreateCustomer(ctx context.Context, request *CustomerRequest) (*Customer, error) {
-	response, err := c.do(ctx, http.MethodPost, customersPath, request)
+	response, err := c.do(ctx, "CreateCustomer", http.MethodPost, customersPath, request)
 	if err != nil {
 		return nil, err
 	}
@@ -391,7 +393,7 @@ func (c *APIClient) CreateCustomer(ctx context.Context, request *CustomerRequest
 }
 
 func (c *APIClient) UpdateCustomer(ctx context.Context, request *CustomerRequest) (*Customer, error) {
-	response, err := c.do(ctx, http.MethodPut, customersPath+"/"+request.ID, request)
+	response, err := c.do(ctx, "UpdateCustomer", http.MethodPut, customersPath+"/"+request.ID, request)
 	if err != nil {
 		return nil, err
 	}
@@ -407,7 +409,7 @@ func (c *APIClient) UpdateCustomer(ctx context.Context, request *CustomerRequest
 }
 
 func (c *APIClient) FindCustomer(ctx context.Context, id string) (*Customer, error) {
-	response, err := c.do(ctx, http.MethodGet, customersPath+"/"+id, nil)
+	response, err := c.do(ctx, "FindCustomer", http.MethodGet, customersPath+"/"+id, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -423,7 +425,7 @@ func (c *APIClient) FindCustomer(ctx context.Context, id string) (*Customer, err
 }
 
 func (c *APIClient) SearchCustomersByIDs(ctx context.Context, query *Search) (*SearchResult, error) {
-	resp, err := c.do(ctx, http.MethodPost, customersPath+"/"+advancedSearchIdsPath, query)
+	resp, err := c.do(ctx, "SearchCustomersByIDs", http.MethodPost, customersPath+"/"+advancedSearchIdsPath, query)
 	if err != nil {
 		return nil, err
 	}
@@ -458,7 +460,7 @@ func (c *APIClient) SearchCustomer(ctx context.Context, query *Search, result *S
 
 	pageQuery := query.ShallowCopy()
 	pageQuery.AddMultiField("ids").Items = result.IDs[startOffset:endOffset]
-	customers, err := c.fetchCustomers(ctx, pageQuery)
+	customers, err := c.fetchCustomers(ctx, "SearchCustomer", pageQuery)
 
 	pageResult := &CustomerSearchResult{
 		TotalItems:        len(result.IDs),
@@ -471,8 +473,8 @@ func (c *APIClient) SearchCustomer(ctx context.Context, query *Search, result *S
 	return pageResult, err
 }
 
-func (c *APIClient) fetchCustomers(ctx context.Context, query *Search) ([]*Customer, error) {
-	resp, err := c.do(ctx, http.MethodPost, customersPath+"/"+advancedSearchPath, query)
+func (c *APIClient) fetchCustomers(ctx context.Context, operation string, query *Search) ([]*Customer, error) {
+	resp, err := c.do(ctx, operation, http.MethodPost, customersPath+"/"+advancedSearchPath, query)
 	if err != nil {
 		return nil, err
 	}
@@ -488,7 +490,7 @@ func (c *APIClient) fetchCustomers(ctx context.Context, query *Search) ([]*Custo
 }
 
 func (c *APIClient) DeleteCustomer(ctx context.Context, id string) error {
-	resp, err := c.do(ctx, http.MethodDelete, customersPath+"/"+id, nil)
+	resp, err := c.do(ctx, "DeleteCustomer", http.MethodDelete, customersPath+"/"+id, nil)
 	if err != nil {
 		return err
 	}
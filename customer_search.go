@@ -0,0 +1,105 @@
+package braintree
+
+import (
+	"time"
+)
+
+// CustomerSearch builds the criteria of a customer search, see TransactionSearch
+//
+//	query, err := NewCustomerSearch().CreatedAt(from, time.Time{}).Search()
+//	it := c.IterateCustomers(query)
+type CustomerSearch struct {
+	criteria
+}
+
+func NewCustomerSearch() *CustomerSearch {
+	return &CustomerSearch{}
+}
+
+// Search returns the search to be given to SearchCustomersByIDs or IterateCustomers, or an *InvalidSearchError
+func (s *CustomerSearch) Search() (*Search, error) {
+	return s.build()
+}
+
+func (s *CustomerSearch) IDs(ids ...string) *CustomerSearch {
+	s.multi("ids", nil, ids)
+	return s
+}
+
+func (s *CustomerSearch) CreatedAt(min, max time.Time) *CustomerSearch {
+	s.timeRange("created-at", min, max)
+	return s
+}
+
+func (s *CustomerSearch) CreditCardBIN(bin string) *CustomerSearch {
+	s.bin("credit-card-number", bin)
+	return s
+}
+
+func (s *CustomerSearch) CreditCardLast4(last4 string) *CustomerSearch {
+	s.last4("credit-card-number", last4)
+	return s
+}
+
+func (s *CustomerSearch) ID() *TextField {
+	return s.text("id")
+}
+
+func (s *CustomerSearch) FirstName() *TextField {
+	return s.text("first-name")
+}
+
+func (s *CustomerSearch) LastName() *TextField {
+	return s.text("last-name")
+}
+
+func (s *CustomerSearch) Company() *TextField {
+	return s.text("company")
+}
+
+func (s *CustomerSearch) Email() *TextField {
+	return s.text("email")
+}
+
+func (s *CustomerSearch) Phone() *TextField {
+	return s.text("phone")
+}
+
+func (s *CustomerSearch) Fax() *TextField {
+	return s.text("fax")
+}
+
+func (s *CustomerSearch) Website() *TextField {
+	return s.text("website")
+}
+
+func (s *CustomerSearch) CardholderName() *TextField {
+	return s.text("cardholder-name")
+}
+
+// CreditCardExpirationDate only supports the Is and IsNot operators, with a MM/YYYY date
+func (s *CustomerSearch) CreditCardExpirationDate() *TextField {
+	return s.text("credit-card-expiration-date")
+}
+
+func (s *CustomerSearch) PaymentMethodToken() *TextField {
+	return s.text("payment-method-token")
+}
+
+// PaymentMethodTokenWithDuplicates finds the customers having the card of the token, or a duplicate of it
+func (s *CustomerSearch) PaymentMethodTokenWithDuplicates() *TextField {
+	return s.text("payment-method-token-with-duplicates")
+}
+
+func (s *CustomerSearch) PayPalAccountEmail() *TextField {
+	return s.text("paypal-account-email")
+}
+
+// Address searches the addresses of the customer, on any field but the company
+func (s *CustomerSearch) Address(field AddressField) *TextField {
+	if field == AddressCompany || !contains(addressFields, string(field)) {
+		s.problem("address-%s : unknown address field", field)
+		return &TextField{}
+	}
+	return s.text("address-" + string(field))
+}
@@ -0,0 +1,240 @@
+package braintree
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrDecimalOverflow  = errors.New("braintree: decimal overflow")
+	ErrDecimalPrecision = errors.New("braintree: decimal precision lost")
+	ErrDivisionByZero   = errors.New("braintree: division by zero")
+)
+
+// RoundingMode tells how the digits dropped by Round and Quo are rounded
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds the halves away from zero : 2.5 => 3, -2.5 => -3
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds the halves to the even neighbour, as bankers do : 2.5 => 2, 3.5 => 4
+	RoundHalfEven
+	// RoundDown truncates towards zero : 2.9 => 2, -2.9 => -2
+	RoundDown
+)
+
+// Decimal is the number Unscaled * 10^-Scale. The arithmetic is exact, the results being returned as new
+// decimals, with ErrDecimalOverflow when they do not fit the 64 bits of Unscaled.
+type Decimal struct {
+	Unscaled int64
+	Scale    int
+}
+
+func NewDecimal(unscaled int64, scale int) *Decimal {
+	return &Decimal{Unscaled: unscaled, Scale: scale}
+}
+
+// ParseDecimal reads a decimal written as an optional sign, digits and an optional fraction, e.g. "-10.50" or
+// ".5"
+func ParseDecimal(s string) (*Decimal, error) {
+	d := &Decimal{}
+	if err := d.UnmarshalText([]byte(s)); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *Decimal) MarshalText() (text []byte, err error) {
+	var b strings.Builder
+	unscaled := strconv.FormatInt(d.Unscaled, 10)
+	if d.Unscaled < 0 {
+		b.WriteString("-")
+		unscaled = unscaled[1:]
+	}
+	if d.Scale <= 0 {
+		b.WriteString(unscaled)
+		if d.Unscaled != 0 {
+			b.WriteString(strings.Repeat("0", -d.Scale))
+		}
+	} else {
+		if len(unscaled) <= d.Scale {
+			unscaled = strings.Repeat("0", (d.Scale+1)-len(unscaled)) + unscaled
+		}
+		b.WriteString(unscaled[:len(unscaled)-d.Scale])
+		b.WriteString(".")
+		b.WriteString(unscaled[len(unscaled)-d.Scale:])
+	}
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText reads the text written by ParseDecimal, rejecting the exponents and the misplaced signs or
+// dots. The empty text leaves the decimal unchanged.
+func (d *Decimal) UnmarshalText(text []byte) (err error) {
+	str := string(text)
+	if str == "" {
+		return nil
+	}
+
+	digits := str
+	if digits[0] == '-' || digits[0] == '+' {
+		digits = digits[1:]
+	}
+	integer, fraction := digits, ""
+	if i := strings.IndexByte(digits, '.'); i != -1 {
+		integer, fraction = digits[:i], digits[i+1:]
+		if fraction == "" {
+			return fmt.Errorf("invalid decimal %q : no digits after the dot", str)
+		}
+	}
+	if integer == "" && fraction == "" {
+		return fmt.Errorf("invalid decimal %q : no digits", str)
+	}
+	for _, part := range []string{integer, fraction} {
+		for i := 0; i < len(part); i++ {
+			if part[i] < '0' || part[i] > '9' {
+				return fmt.Errorf("invalid decimal %q : unexpected %q", str, part[i])
+			}
+		}
+	}
+
+	unscaled, err := strconv.ParseInt(str[:len(str)-len(digits)]+integer+fraction, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w : %q", ErrDecimalOverflow, str)
+	}
+	d.Unscaled = unscaled
+	d.Scale = len(fraction)
+	return nil
+}
+
+func (d *Decimal) Cmp(y *Decimal) int {
+	x, yy, _ := align(d, y)
+	return x.Cmp(yy)
+}
+
+func (d *Decimal) String() string {
+	b, err := d.MarshalText()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return string(b)
+}
+
+// Sign returns -1, 0 or 1 as the decimal is negative, zero or positive
+func (d *Decimal) Sign() int {
+	switch {
+	case d.Unscaled < 0:
+		return -1
+	case d.Unscaled > 0:
+		return 1
+	}
+	return 0
+}
+
+func (d *Decimal) Neg() (*Decimal, error) {
+	return newDecimal(new(big.Int).Neg(big.NewInt(d.Unscaled)), d.Scale)
+}
+
+func (d *Decimal) Abs() (*Decimal, error) {
+	return newDecimal(new(big.Int).Abs(big.NewInt(d.Unscaled)), d.Scale)
+}
+
+// Add returns d + y, at the greater of their scales
+func (d *Decimal) Add(y *Decimal) (*Decimal, error) {
+	x, yy, scale := align(d, y)
+	return newDecimal(x.Add(x, yy), scale)
+}
+
+// Sub returns d - y, at the greater of their scales
+func (d *Decimal) Sub(y *Decimal) (*Decimal, error) {
+	x, yy, scale := align(d, y)
+	return newDecimal(x.Sub(x, yy), scale)
+}
+
+// Mul returns d * y, at the sum of their scales
+func (d *Decimal) Mul(y *Decimal) (*Decimal, error) {
+	x := new(big.Int).Mul(big.NewInt(d.Unscaled), big.NewInt(y.Unscaled))
+	return newDecimal(x, d.Scale+y.Scale)
+}
+
+// Quo returns d / y at the scale given, the digits past it being rounded with the mode
+func (d *Decimal) Quo(y *Decimal, scale int, mode RoundingMode) (*Decimal, error) {
+	if y.Unscaled == 0 {
+		return nil, ErrDivisionByZero
+	}
+	// d / y = (d.Unscaled / y.Unscaled) * 10^(y.Scale - d.Scale), wanted as a number of 10^-scale
+	num, den := big.NewInt(d.Unscaled), big.NewInt(y.Unscaled)
+	if shift := scale - d.Scale + y.Scale; shift >= 0 {
+		num.Mul(num, pow10(shift))
+	} else {
+		den.Mul(den, pow10(-shift))
+	}
+	return newDecimal(quo(num, den, mode), scale)
+}
+
+// Round returns the decimal at the scale given, the digits past it being rounded with the mode
+func (d *Decimal) Round(scale int, mode RoundingMode) (*Decimal, error) {
+	if scale >= d.Scale {
+		return d.Rescale(scale)
+	}
+	return newDecimal(quo(big.NewInt(d.Unscaled), pow10(d.Scale-scale), mode), scale)
+}
+
+// Rescale returns the same number at another scale, failing with ErrDecimalPrecision when digits would be lost
+func (d *Decimal) Rescale(scale int) (*Decimal, error) {
+	if scale >= d.Scale {
+		return newDecimal(new(big.Int).Mul(big.NewInt(d.Unscaled), pow10(scale-d.Scale)), scale)
+	}
+	q, r := new(big.Int).QuoRem(big.NewInt(d.Unscaled), pow10(d.Scale-scale), new(big.Int))
+	if r.Sign() != 0 {
+		return nil, fmt.Errorf("%w : %s at scale %d", ErrDecimalPrecision, d, scale)
+	}
+	return newDecimal(q, scale)
+}
+
+func newDecimal(unscaled *big.Int, scale int) (*Decimal, error) {
+	if !unscaled.IsInt64() {
+		return nil, ErrDecimalOverflow
+	}
+	return &Decimal{Unscaled: unscaled.Int64(), Scale: scale}, nil
+}
+
+// align returns the unscaled values of the decimals at the greater of their scales
+func align(x, y *Decimal) (*big.Int, *big.Int, int) {
+	a, b := big.NewInt(x.Unscaled), big.NewInt(y.Unscaled)
+	switch {
+	case x.Scale > y.Scale:
+		b.Mul(b, pow10(x.Scale-y.Scale))
+		return a, b, x.Scale
+	case y.Scale > x.Scale:
+		a.Mul(a, pow10(y.Scale-x.Scale))
+	}
+	return a, b, y.Scale
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// quo divides, rounding the remainder with the mode
+func quo(num, den *big.Int, mode RoundingMode) *big.Int {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() == 0 || mode == RoundDown {
+		return q
+	}
+	half := new(big.Int).Abs(r)
+	half.Mul(half, big.NewInt(2)).Sub(half, new(big.Int).Abs(den))
+	if half.Sign() > 0 || half.Sign() == 0 && (mode == RoundHalfUp || q.Bit(0) == 1) {
+		// away from zero, the sign of the quotient being the one of the operands
+		if num.Sign()*den.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
@@ -10,8 +10,8 @@ import (
 const discounts = "discounts"
 
 type DiscountList struct {
-	XMLName   string     `xml:"discounts"`
-	Discounts []Discount `xml:"discount"`
+	XMLName   string     `xml:"discounts" json:"-"`
+	Discounts []Discount `xml:"discount" json:"discount"`
 }
 
 const (
@@ -20,25 +20,25 @@ const (
 )
 
 type Modification struct {
-	Id                    string     `xml:"id"`
-	Amount                *Decimal   `xml:"amount"`
-	Description           string     `xml:"description"`
-	Kind                  string     `xml:"kind"`
-	Name                  string     `xml:"name"`
-	NeverExpires          bool       `xml:"never-expires"`
-	Quantity              int        `xml:"quantity"`
-	NumberOfBillingCycles int        `xml:"number-of-billing-cycles"`
-	CurrentBillingCycle   int        `xml:"current-billing-cycle"`
-	UpdatedAt             *time.Time `xml:"updated_at"`
+	Id                    string     `xml:"id" json:"id"`
+	Amount                *Decimal   `xml:"amount" json:"amount"`
+	Description           string     `xml:"description" json:"description"`
+	Kind                  string     `xml:"kind" json:"kind"`
+	Name                  string     `xml:"name" json:"name"`
+	NeverExpires          bool       `xml:"never-expires" json:"never_expires"`
+	Quantity              int        `xml:"quantity" json:"quantity"`
+	NumberOfBillingCycles int        `xml:"number-of-billing-cycles" json:"number_of_billing_cycles"`
+	CurrentBillingCycle   int        `xml:"current-billing-cycle" json:"current_billing_cycle"`
+	UpdatedAt             *time.Time `xml:"updated_at" json:"updated_at"`
 }
 
 type Discount struct {
-	XMLName string `xml:"discount"`
+	XMLName string `xml:"discount" json:"-"`
 	Modification
 }
 
 func (c *APIClient) AllDiscounts(ctx context.Context) ([]Discount, error) {
-	response, err := c.do(ctx, http.MethodGet, discounts, nil)
+	response, err := c.do(ctx, "AllDiscounts", http.MethodGet, discounts, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -42,21 +42,21 @@ const (
 )
 
 type DisputeEvidence struct {
-	XMLName           string          `xml:"evidence"`
-	Comment           string          `xml:"comment"`
-	CreatedAt         *time.Time      `xml:"created-at"`
-	ID                string          `xml:"id"`
-	SentToProcessorAt string          `xml:"sent-to-processor-at"`
-	URL               string          `xml:"url"`
-	Category          DisputeCategory `xml:"category"`
-	SequenceNumber    string          `xml:"sequence-number"`
+	XMLName           string          `xml:"evidence" json:"-"`
+	Comment           string          `xml:"comment" json:"comment"`
+	CreatedAt         *time.Time      `xml:"created-at" json:"created_at"`
+	ID                string          `xml:"id" json:"id"`
+	SentToProcessorAt string          `xml:"sent-to-processor-at" json:"sent_to_processor_at"`
+	URL               string          `xml:"url" json:"url"`
+	Category          DisputeCategory `xml:"category" json:"category"`
+	SequenceNumber    string          `xml:"sequence-number" json:"sequence_number"`
 }
 
 type DisputeTextEvidenceRequest struct {
-	XMLName        xml.Name        `xml:"evidence"`
-	Content        string          `xml:"comments"`
-	Category       DisputeCategory `xml:"category,omitempty"`
-	SequenceNumber string          `xml:"sequence-number,omitempty"`
+	XMLName        xml.Name        `xml:"evidence" json:"-"`
+	Content        string          `xml:"comments" json:"comments"`
+	Category       DisputeCategory `xml:"category,omitempty" json:"category,omitempty"`
+	SequenceNumber string          `xml:"sequence-number,omitempty" json:"sequence_number,omitempty"`
 }
 
 type DisputeKind string
@@ -94,49 +94,49 @@ const (
 )
 
 type Dispute struct {
-	XMLName           string                       `xml:"dispute"`
-	CaseNumber        string                       `xml:"case-number"`
-	CurrencyISOCode   string                       `xml:"currency-iso-code"`
-	ID                string                       `xml:"id"`
-	MerchantAccountID string                       `xml:"merchant-account-id"`
-	OriginalDisputeID string                       `xml:"original-dispute-id"`
-	ProcessorComments string                       `xml:"processor-comments"`
-	ReturnCode        string                       `xml:"return-code"`
-	ReceivedDate      string                       `xml:"received-date"`
-	ReferenceNumber   string                       `xml:"reference-number"`
-	ReplyByDate       string                       `xml:"reply-by-date"`
-	Kind              DisputeKind                  `xml:"kind"`
-	Reason            DisputeReason                `xml:"reason"`
-	Status            DisputeStatus                `xml:"status"`
-	AmountDisputed    *Decimal                     `xml:"amount-disputed"`
-	AmountWon         *Decimal                     `xml:"amount-won"`
-	UpdatedAt         *time.Time                   `xml:"updated-at"`
-	CreatedAt         *time.Time                   `xml:"created-at"`
-	Evidence          []*DisputeEvidence           `xml:"evidence>evidence"`
-	StatusHistory     []*DisputeStatusHistoryEvent `xml:"status-history>status-history"`
-	Transaction       *DisputeTransaction          `xml:"transaction"`
+	XMLName           string                       `xml:"dispute" json:"-"`
+	CaseNumber        string                       `xml:"case-number" json:"case_number"`
+	CurrencyISOCode   string                       `xml:"currency-iso-code" json:"currency_iso_code"`
+	ID                string                       `xml:"id" json:"id"`
+	MerchantAccountID string                       `xml:"merchant-account-id" json:"merchant_account_id"`
+	OriginalDisputeID string                       `xml:"original-dispute-id" json:"original_dispute_id"`
+	ProcessorComments string                       `xml:"processor-comments" json:"processor_comments"`
+	ReturnCode        string                       `xml:"return-code" json:"return_code"`
+	ReceivedDate      string                       `xml:"received-date" json:"received_date"`
+	ReferenceNumber   string                       `xml:"reference-number" json:"reference_number"`
+	ReplyByDate       string                       `xml:"reply-by-date" json:"reply_by_date"`
+	Kind              DisputeKind                  `xml:"kind" json:"kind"`
+	Reason            DisputeReason                `xml:"reason" json:"reason"`
+	Status            DisputeStatus                `xml:"status" json:"status"`
+	AmountDisputed    *Decimal                     `xml:"amount-disputed" json:"amount_disputed"`
+	AmountWon         *Decimal                     `xml:"amount-won" json:"amount_won"`
+	UpdatedAt         *time.Time                   `xml:"updated-at" json:"updated_at"`
+	CreatedAt         *time.Time                   `xml:"created-at" json:"created_at"`
+	Evidence          []*DisputeEvidence           `xml:"evidence>evidence" json:"evidence"`
+	StatusHistory     []*DisputeStatusHistoryEvent `xml:"status-history>status-history" json:"status_history"`
+	Transaction       *DisputeTransaction          `xml:"transaction" json:"transaction"`
 }
 
 type DisputeStatusHistoryEvent struct {
-	XMLName          string     `xml:"status-history"`
-	DisbursementDate string     `xml:"disbursement-date"`
-	EffectiveDate    string     `xml:"effective-date"`
-	Status           string     `xml:"status"`
-	Timestamp        *time.Time `xml:"timestamp"`
+	XMLName          string     `xml:"status-history" json:"-"`
+	DisbursementDate string     `xml:"disbursement-date" json:"disbursement_date"`
+	EffectiveDate    string     `xml:"effective-date" json:"effective_date"`
+	Status           string     `xml:"status" json:"status"`
+	Timestamp        *time.Time `xml:"timestamp" json:"timestamp"`
 }
 
 type DisputeTransaction struct {
-	XMLName                  string     `xml:"transaction"`
-	ID                       string     `xml:"id"`
-	OrderID                  string     `xml:"order-id"`
-	PaymentInstrumentSubtype string     `xml:"payment-instrument-subtype"`
-	PurchaseOrderNumber      string     `xml:"purchase-order-number"`
-	Amount                   *Decimal   `xml:"amount"`
-	CreatedAt                *time.Time `xml:"created-at"`
+	XMLName                  string     `xml:"transaction" json:"-"`
+	ID                       string     `xml:"id" json:"id"`
+	OrderID                  string     `xml:"order-id" json:"order_id"`
+	PaymentInstrumentSubtype string     `xml:"payment-instrument-subtype" json:"payment_instrument_subtype"`
+	PurchaseOrderNumber      string     `xml:"purchase-order-number" json:"purchase_order_number"`
+	Amount                   *Decimal   `xml:"amount" json:"amount"`
+	CreatedAt                *time.Time `xml:"created-at" json:"created_at"`
 }
 
 func (c *APIClient) FindDispute(ctx context.Context, disputeID string) (*Dispute, error) {
-	response, err := c.call(ctx, http.MethodGet, disputesPath+"/"+disputeID, nil, apiVersion4)
+	response, err := c.call(ctx, "FindDispute", http.MethodGet, disputesPath+"/"+disputeID, nil, apiVersion4)
 	if err != nil {
 		return nil, err
 	}
@@ -152,7 +152,7 @@ func (c *APIClient) FindDispute(ctx context.Context, disputeID string) (*Dispute
 }
 
 func (c *APIClient) AddTextEvidence(ctx context.Context, disputeID string, evidence *DisputeTextEvidenceRequest) (*DisputeEvidence, error) {
-	response, err := c.call(ctx, http.MethodPost, disputesPath+"/"+disputeID+"/evidence", evidence, apiVersion4)
+	response, err := c.call(ctx, "AddTextEvidence", http.MethodPost, disputesPath+"/"+disputeID+"/evidence", evidence, apiVersion4)
 	if err != nil {
 		return nil, err
 	}
@@ -168,7 +168,7 @@ func (c *APIClient) AddTextEvidence(ctx context.Context, disputeID string, evide
 }
 
 func (c *APIClient) RemoveEvidence(ctx context.Context, disputeID string, id string) error {
-	resp, err := c.call(ctx, http.MethodDelete, disputesPath+"/"+disputeID+"/evidence/"+id, nil, apiVersion4)
+	resp, err := c.call(ctx, "RemoveEvidence", http.MethodDelete, disputesPath+"/"+disputeID+"/evidence/"+id, nil, apiVersion4)
 	if err != nil {
 		return err
 	}
@@ -180,7 +180,7 @@ func (c *APIClient) RemoveEvidence(ctx context.Context, disputeID string, id str
 }
 
 func (c *APIClient) Accept(ctx context.Context, disputeID string) error {
-	resp, err := c.call(ctx, http.MethodPut, disputesPath+"/"+disputeID+"/accept", nil, apiVersion4)
+	resp, err := c.call(ctx, "Accept", http.MethodPut, disputesPath+"/"+disputeID+"/accept", nil, apiVersion4)
 	if err != nil {
 		return nil
 	}
@@ -192,7 +192,7 @@ func (c *APIClient) Accept(ctx context.Context, disputeID string) error {
 }
 
 func (c *APIClient) Finalize(ctx context.Context, disputeID string) error {
-	resp, err := c.call(ctx, http.MethodPut, disputesPath+"/"+disputeID+"/finalize", nil, apiVersion4)
+	resp, err := c.call(ctx, "Finalize", http.MethodPut, disputesPath+"/"+disputeID+"/finalize", nil, apiVersion4)
 	if err != nil {
 		return err
 	}
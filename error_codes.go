@@ -0,0 +1,649 @@
+package braintree
+
+// ErrorCode is the code of a gateway validation error, as found in ValidationError.Code
+type ErrorCode string
+
+// ErrorClass tells who can fix a validation error : the payer, by correcting what was typed in the form,
+// or the developer, because the request was built wrong
+type ErrorClass int
+
+const (
+	ErrorClassUnknown ErrorClass = iota
+	ErrorClassUser
+	ErrorClassIntegration
+)
+
+func (c ErrorClass) String() string {
+	switch c {
+	case ErrorClassUser:
+		return "user"
+	case ErrorClassIntegration:
+		return "integration"
+	}
+	return "unknown"
+}
+
+// Address
+const (
+	CodeAddressCannotBeBlank                     ErrorCode = "81801"
+	CodeAddressCompanyIsTooLong                  ErrorCode = "81802"
+	CodeAddressExtendedAddressIsTooLong          ErrorCode = "81804"
+	CodeAddressFirstNameIsTooLong                ErrorCode = "81805"
+	CodeAddressLastNameIsTooLong                 ErrorCode = "81806"
+	CodeAddressLocalityIsTooLong                 ErrorCode = "81807"
+	CodeAddressPostalCodeIsRequired              ErrorCode = "81808"
+	CodeAddressPostalCodeIsTooLong               ErrorCode = "81809"
+	CodeAddressRegionIsTooLong                   ErrorCode = "81810"
+	CodeAddressStreetAddressIsRequired           ErrorCode = "81811"
+	CodeAddressStreetAddressIsTooLong            ErrorCode = "81812"
+	CodeAddressPostalCodeInvalidCharacters       ErrorCode = "81813"
+	CodeAddressStateIsInvalidForSellerProtection ErrorCode = "81827"
+	CodeAddressCountryNameIsNotAccepted          ErrorCode = "91803"
+	CodeAddressCountryCodeAlpha2IsNotAccepted    ErrorCode = "91814"
+	CodeAddressInconsistentCountry               ErrorCode = "91815"
+	CodeAddressCountryCodeAlpha3IsNotAccepted    ErrorCode = "91816"
+	CodeAddressCountryCodeNumericIsNotAccepted   ErrorCode = "91817"
+	CodeAddressTooManyAddressesPerCustomer       ErrorCode = "91818"
+	CodeAddressFirstNameIsInvalid                ErrorCode = "91819"
+	CodeAddressLastNameIsInvalid                 ErrorCode = "91820"
+	CodeAddressCompanyIsInvalid                  ErrorCode = "91821"
+	CodeAddressStreetAddressIsInvalid            ErrorCode = "91822"
+	CodeAddressExtendedAddressIsInvalid          ErrorCode = "91823"
+	CodeAddressLocalityIsInvalid                 ErrorCode = "91824"
+	CodeAddressRegionIsInvalid                   ErrorCode = "91825"
+	CodeAddressPostalCodeIsInvalid               ErrorCode = "91826"
+)
+
+// Client token
+const (
+	CodeClientTokenCustomerDoesNotExist ErrorCode = "92804"
+)
+
+// Credit card
+const (
+	CodeCreditCardTypeIsNotAccepted                       ErrorCode = "81703"
+	CodeCreditCardCVVIsRequired                           ErrorCode = "81706"
+	CodeCreditCardCVVIsInvalid                            ErrorCode = "81707"
+	CodeCreditCardExpirationDateIsRequired                ErrorCode = "81709"
+	CodeCreditCardExpirationDateIsInvalid                 ErrorCode = "81710"
+	CodeCreditCardExpirationDateYearIsInvalid             ErrorCode = "81711"
+	CodeCreditCardExpirationMonthIsInvalid                ErrorCode = "81712"
+	CodeCreditCardExpirationYearIsInvalid                 ErrorCode = "81713"
+	CodeCreditCardNumberIsRequired                        ErrorCode = "81714"
+	CodeCreditCardNumberIsInvalid                         ErrorCode = "81715"
+	CodeCreditCardNumberLengthIsInvalid                   ErrorCode = "81716"
+	CodeCreditCardNumberMustBeTestNumber                  ErrorCode = "81717"
+	CodeCreditCardTypeIsNotAcceptedBySubscriptionMerchant ErrorCode = "81718"
+	CodeCreditCardCardholderNameIsTooLong                 ErrorCode = "81723"
+	CodeCreditCardDuplicateCardExists                     ErrorCode = "81724"
+	CodeCreditCardPaymentMethodConflict                   ErrorCode = "81725"
+	CodeCreditCardCVVVerificationFailed                   ErrorCode = "81736"
+	CodeCreditCardPostalCodeVerificationFailed            ErrorCode = "81737"
+	CodeCreditCardBillingAddressConflict                  ErrorCode = "91701"
+	CodeCreditCardBillingAddressIdIsInvalid               ErrorCode = "91702"
+	CodeCreditCardCustomerIdIsRequired                    ErrorCode = "91704"
+	CodeCreditCardCustomerIdIsInvalid                     ErrorCode = "91705"
+	CodeCreditCardExpirationDateConflict                  ErrorCode = "91708"
+	CodeCreditCardTokenFormatIsInvalid                    ErrorCode = "91718"
+	CodeCreditCardTokenIsInUse                            ErrorCode = "91719"
+	CodeCreditCardTokenIsTooLong                          ErrorCode = "91720"
+	CodeCreditCardTokenIsNotAllowed                       ErrorCode = "91721"
+	CodeCreditCardTokenIsRequired                         ErrorCode = "91722"
+	CodeCreditCardUpdateExistingTokenIsInvalid            ErrorCode = "91723"
+	CodeCreditCardVerificationMerchantAccountIdIsInvalid  ErrorCode = "91728"
+	CodeCreditCardUpdateExistingTokenNotAllowed           ErrorCode = "91729"
+	CodeCreditCardVerificationNotSupportedOnMerchant      ErrorCode = "91730"
+	CodeCreditCardPaymentMethodNonceConsumed              ErrorCode = "91731"
+	CodeCreditCardPaymentMethodNonceUnknown               ErrorCode = "91732"
+	CodeCreditCardPaymentMethodNonceLocked                ErrorCode = "91733"
+	CodeCreditCardPaymentMethodNonceCardTypeIsNotAccepted ErrorCode = "91734"
+)
+
+// Customer
+const (
+	CodeCustomerCompanyIsTooLong          ErrorCode = "81601"
+	CodeCustomerCustomFieldIsTooLong      ErrorCode = "81603"
+	CodeCustomerEmailIsInvalid            ErrorCode = "81604"
+	CodeCustomerEmailIsTooLong            ErrorCode = "81605"
+	CodeCustomerEmailIsRequired           ErrorCode = "81606"
+	CodeCustomerFaxIsTooLong              ErrorCode = "81607"
+	CodeCustomerFirstNameIsTooLong        ErrorCode = "81608"
+	CodeCustomerLastNameIsTooLong         ErrorCode = "81613"
+	CodeCustomerPhoneIsTooLong            ErrorCode = "81614"
+	CodeCustomerWebsiteIsTooLong          ErrorCode = "81615"
+	CodeCustomerWebsiteIsInvalid          ErrorCode = "81616"
+	CodeCustomerCustomFieldIsInvalid      ErrorCode = "91602"
+	CodeCustomerIdIsInUse                 ErrorCode = "91609"
+	CodeCustomerIdIsInvalid               ErrorCode = "91610"
+	CodeCustomerIdIsNotAllowed            ErrorCode = "91611"
+	CodeCustomerIdIsTooLong               ErrorCode = "91612"
+	CodeCustomerIdIsRequired              ErrorCode = "91613"
+	CodeCustomerNonceBelongsToAnotherUser ErrorCode = "91617"
+)
+
+// Descriptor
+const (
+	CodeDescriptorNameFormatIsInvalid  ErrorCode = "92201"
+	CodeDescriptorPhoneFormatIsInvalid ErrorCode = "92202"
+	CodeDescriptorURLFormatIsInvalid   ErrorCode = "92206"
+)
+
+// Dispute
+const (
+	CodeDisputeCanOnlyAddEvidenceToOpenDispute      ErrorCode = "95701"
+	CodeDisputeCanOnlyRemoveEvidenceFromOpenDispute ErrorCode = "95702"
+	CodeDisputeCanOnlyAddEvidenceDocumentToDispute  ErrorCode = "95703"
+	CodeDisputeCanOnlyAcceptOpenDispute             ErrorCode = "95704"
+	CodeDisputeCanOnlyFinalizeOpenDispute           ErrorCode = "95705"
+	CodeDisputeEvidenceCategoryIsInvalid            ErrorCode = "95706"
+	CodeDisputeEvidenceContentDateIsInvalid         ErrorCode = "95707"
+	CodeDisputeEvidenceContentIsTooLong             ErrorCode = "95708"
+	CodeDisputeEvidenceContentARNIsTooLong          ErrorCode = "95709"
+	CodeDisputeEvidenceContentPhoneIsTooLong        ErrorCode = "95710"
+	CodeDisputeEvidenceCategoryTextOnly             ErrorCode = "95711"
+	CodeDisputeEvidenceCategoryDocumentOnly         ErrorCode = "95712"
+	CodeDisputeEvidenceCategoryNotForReasonCode     ErrorCode = "95713"
+	CodeDisputeEvidenceCategoryDuplicate            ErrorCode = "95714"
+	CodeDisputeEvidenceContentEmailIsInvalid        ErrorCode = "95715"
+	CodeDisputeEvidenceContentCannotBeBlank         ErrorCode = "95725"
+	CodeDisputeValidEvidenceRequiredToFinalize      ErrorCode = "95726"
+)
+
+// Merchant account
+const (
+	CodeMerchantAccountIdIsTooLong                        ErrorCode = "82602"
+	CodeMerchantAccountIdFormatIsInvalid                  ErrorCode = "82603"
+	CodeMerchantAccountIdIsInUse                          ErrorCode = "82604"
+	CodeMerchantAccountIdIsNotAllowed                     ErrorCode = "82605"
+	CodeMerchantAccountMasterMerchantAccountIdIsRequired  ErrorCode = "82606"
+	CodeMerchantAccountMasterMerchantAccountIdIsInvalid   ErrorCode = "82607"
+	CodeMerchantAccountMasterMerchantAccountMustBeActive  ErrorCode = "82608"
+	CodeMerchantAccountFirstNameIsRequired                ErrorCode = "82609"
+	CodeMerchantAccountTosAcceptedIsRequired              ErrorCode = "82610"
+	CodeMerchantAccountLastNameIsRequired                 ErrorCode = "82611"
+	CodeMerchantAccountDateOfBirthIsRequired              ErrorCode = "82612"
+	CodeMerchantAccountRoutingNumberIsRequired            ErrorCode = "82613"
+	CodeMerchantAccountAccountNumberIsRequired            ErrorCode = "82614"
+	CodeMerchantAccountSSNIsInvalid                       ErrorCode = "82615"
+	CodeMerchantAccountEmailIsInvalid                     ErrorCode = "82616"
+	CodeMerchantAccountDeclinedOFAC                       ErrorCode = "82621"
+	CodeMerchantAccountDeclinedMasterCardMatch            ErrorCode = "82622"
+	CodeMerchantAccountDeclinedFailedKYC                  ErrorCode = "82623"
+	CodeMerchantAccountDeclinedSSNInvalid                 ErrorCode = "82624"
+	CodeMerchantAccountDeclined                           ErrorCode = "82626"
+	CodeMerchantAccountFirstNameIsInvalid                 ErrorCode = "82627"
+	CodeMerchantAccountLastNameIsInvalid                  ErrorCode = "82628"
+	CodeMerchantAccountCannotBeUpdated                    ErrorCode = "82674"
+	CodeMerchantAccountIdCannotBeUpdated                  ErrorCode = "82675"
+	CodeMerchantAccountMasterMerchantAccountIdNotEditable ErrorCode = "82676"
+)
+
+// Payment method
+const (
+	CodePaymentMethodNonceIsRequired ErrorCode = "93106"
+)
+
+// Settlement batch summary
+const (
+	CodeSettlementBatchSummarySettlementDateIsInvalid ErrorCode = "82302"
+)
+
+// Subscription
+const (
+	CodeSubscriptionCannotEditCanceled                    ErrorCode = "81901"
+	CodeSubscriptionIdIsInUse                             ErrorCode = "81902"
+	CodeSubscriptionPriceCannotBeBlank                    ErrorCode = "81903"
+	CodeSubscriptionPriceIsInvalid                        ErrorCode = "81904"
+	CodeSubscriptionStatusIsCanceled                      ErrorCode = "81905"
+	CodeSubscriptionTokenFormatIsInvalid                  ErrorCode = "81906"
+	CodeSubscriptionTrialDurationIsInvalid                ErrorCode = "81907"
+	CodeSubscriptionTrialDurationIsRequired               ErrorCode = "81908"
+	CodeSubscriptionTrialDurationUnitIsInvalid            ErrorCode = "81909"
+	CodeSubscriptionCannotEditExpired                     ErrorCode = "81910"
+	CodeSubscriptionPriceIsTooLarge                       ErrorCode = "81923"
+	CodeSubscriptionMerchantAccountIdIsInvalid            ErrorCode = "91901"
+	CodeSubscriptionPaymentMethodTokenCardTypeNotAccepted ErrorCode = "91902"
+	CodeSubscriptionPaymentMethodTokenIsInvalid           ErrorCode = "91903"
+	CodeSubscriptionPlanIdIsInvalid                       ErrorCode = "91904"
+	CodeSubscriptionPaymentMethodTokenNotOfCustomer       ErrorCode = "91905"
+	CodeSubscriptionNumberOfBillingCyclesMustBeNumeric    ErrorCode = "91906"
+	CodeSubscriptionNumberOfBillingCyclesMustBePositive   ErrorCode = "91907"
+	CodeSubscriptionInconsistentNumberOfBillingCycles     ErrorCode = "91908"
+	CodeSubscriptionNumberOfBillingCyclesIsTooSmall       ErrorCode = "91909"
+	CodeSubscriptionCannotAddDuplicateAddOnOrDiscount     ErrorCode = "91911"
+	CodeSubscriptionNumberOfBillingCyclesCannotBeBlank    ErrorCode = "91912"
+	CodeSubscriptionBillingDayOfMonthMustBeNumeric        ErrorCode = "91913"
+	CodeSubscriptionBillingDayOfMonthIsInvalid            ErrorCode = "91914"
+	CodeSubscriptionFirstBillingDateIsInvalid             ErrorCode = "91915"
+	CodeSubscriptionFirstBillingDateCannotBeInThePast     ErrorCode = "91916"
+	CodeSubscriptionInconsistentStartDate                 ErrorCode = "91917"
+	CodeSubscriptionBillingDayOfMonthCannotBeUpdated      ErrorCode = "91918"
+	CodeSubscriptionFirstBillingDateCannotBeUpdated       ErrorCode = "91919"
+	CodeSubscriptionCannotEditPriceOnPastDue              ErrorCode = "91920"
+	CodeSubscriptionInvalidRequestFormat                  ErrorCode = "91921"
+	CodeSubscriptionPlanBillingFrequencyCannotBeUpdated   ErrorCode = "91922"
+	CodeSubscriptionMismatchCurrencyISOCode               ErrorCode = "91923"
+	CodeSubscriptionPaymentMethodNonceCardTypeNotAccepted ErrorCode = "91924"
+	CodeSubscriptionPaymentMethodNonceIsInvalid           ErrorCode = "91925"
+	CodeSubscriptionPaymentMethodNonceNotOfCustomer       ErrorCode = "91926"
+	CodeSubscriptionPaymentMethodNonceUnvaultedCard       ErrorCode = "91927"
+)
+
+// Transaction
+const (
+	CodeTransactionAmountCannotBeNegative                ErrorCode = "81501"
+	CodeTransactionAmountIsRequired                      ErrorCode = "81502"
+	CodeTransactionAmountIsInvalid                       ErrorCode = "81503"
+	CodeTransactionCustomerDefaultCardTypeNotAccepted    ErrorCode = "81509"
+	CodeTransactionProcessorAuthorizationCodeIsInvalid   ErrorCode = "81520"
+	CodeTransactionCustomFieldIsTooLong                  ErrorCode = "81527"
+	CodeTransactionAmountIsTooLarge                      ErrorCode = "81528"
+	CodeTransactionAmountMustBeGreaterThanZero           ErrorCode = "81531"
+	CodeTransactionTaxAmountCannotBeNegative             ErrorCode = "81534"
+	CodeTransactionTaxAmountIsInvalid                    ErrorCode = "81535"
+	CodeTransactionTaxAmountIsTooLarge                   ErrorCode = "81536"
+	CodeTransactionThreeDSecureAuthenticationFailed      ErrorCode = "81571"
+	CodeTransactionOrderIdIsTooLong                      ErrorCode = "91501"
+	CodeTransactionCannotBeVoided                        ErrorCode = "91504"
+	CodeTransactionCannotRefundCredit                    ErrorCode = "91505"
+	CodeTransactionCannotRefundUnlessSettled             ErrorCode = "91506"
+	CodeTransactionCannotSubmitForSettlement             ErrorCode = "91507"
+	CodeTransactionCreditCardIsRequired                  ErrorCode = "91508"
+	CodeTransactionCustomerIdIsInvalid                   ErrorCode = "91510"
+	CodeTransactionCustomerDoesNotHaveCreditCard         ErrorCode = "91511"
+	CodeTransactionHasAlreadyBeenRefunded                ErrorCode = "91512"
+	CodeTransactionMerchantAccountIdIsInvalid            ErrorCode = "91513"
+	CodeTransactionMerchantAccountIsSuspended            ErrorCode = "91514"
+	CodeTransactionPaymentMethodConflict                 ErrorCode = "91515"
+	CodeTransactionPaymentMethodNotOfCustomer            ErrorCode = "91516"
+	CodeTransactionPaymentMethodTokenCardTypeNotAccepted ErrorCode = "91517"
+	CodeTransactionPaymentMethodTokenIsInvalid           ErrorCode = "91518"
+	CodeTransactionProcessorAuthorizationCodeCannotBeSet ErrorCode = "91519"
+	CodeTransactionRefundAmountIsTooLarge                ErrorCode = "91521"
+	CodeTransactionSettlementAmountIsTooLarge            ErrorCode = "91522"
+	CodeTransactionTypeIsInvalid                         ErrorCode = "91523"
+	CodeTransactionTypeIsRequired                        ErrorCode = "91524"
+	CodeTransactionCustomFieldIsInvalid                  ErrorCode = "91526"
+	CodeTransactionPaymentMethodNotOfSubscription        ErrorCode = "91527"
+	CodeTransactionSubscriptionIdIsInvalid               ErrorCode = "91528"
+	CodeTransactionSubscriptionNotOfCustomer             ErrorCode = "91529"
+	CodeTransactionBillingAddressConflict                ErrorCode = "91530"
+	CodeTransactionSubscriptionStatusMustBePastDue       ErrorCode = "91531"
+	CodeTransactionPurchaseOrderNumberIsTooLong          ErrorCode = "91537"
+	CodeTransactionCannotRefundWithSuspendedMerchant     ErrorCode = "91538"
+	CodeTransactionCannotCloneVaultCreditCard            ErrorCode = "91540"
+	CodeTransactionCannotCloneVoiceAuthorization         ErrorCode = "91541"
+	CodeTransactionCannotCloneUnsuccessful               ErrorCode = "91542"
+	CodeTransactionCannotCloneCredit                     ErrorCode = "91543"
+	CodeTransactionProcessorDoesNotSupportCredits        ErrorCode = "91546"
+	CodeTransactionMerchantAccountDoesNotSupportRefunds  ErrorCode = "91547"
+	CodeTransactionPurchaseOrderNumberIsInvalid          ErrorCode = "91548"
+	CodeTransactionChannelIsTooLong                      ErrorCode = "91550"
+	CodeTransactionSettlementAmountBelowServiceFee       ErrorCode = "91551"
+	CodeTransactionServiceFeeIsNotAllowedOnCredits       ErrorCode = "91552"
+	CodeTransactionServiceFeeAmountCannotBeNegative      ErrorCode = "91554"
+	CodeTransactionServiceFeeAmountIsInvalid             ErrorCode = "91555"
+	CodeTransactionServiceFeeAmountIsTooLarge            ErrorCode = "91556"
+	CodeTransactionServiceFeeNotAllowedOnMasterMerchant  ErrorCode = "91557"
+	CodeTransactionMerchantAccountDoesNotSupportMOTO     ErrorCode = "91558"
+	CodeTransactionCannotRefundWithPendingMerchant       ErrorCode = "91559"
+	CodeTransactionCannotHoldInEscrow                    ErrorCode = "91560"
+	CodeTransactionCannotReleaseFromEscrow               ErrorCode = "91561"
+	CodeTransactionCannotCancelRelease                   ErrorCode = "91562"
+	CodeTransactionCannotPartiallyRefundEscrowed         ErrorCode = "91563"
+	CodeTransactionPaymentMethodNonceConsumed            ErrorCode = "91564"
+	CodeTransactionPaymentMethodNonceUnknown             ErrorCode = "91565"
+	CodeTransactionPaymentMethodNonceLocked              ErrorCode = "91566"
+	CodeTransactionPaymentMethodNonceCardTypeNotAccepted ErrorCode = "91567"
+	CodeTransactionThreeDSecureTokenIsInvalid            ErrorCode = "91568"
+	CodeTransactionCannotRefundSettling                  ErrorCode = "91574"
+	CodeTransactionCannotRefundHeldInEscrow              ErrorCode = "91573"
+	CodeTransactionCannotSimulateSettlement              ErrorCode = "91575"
+	CodeTransactionCannotSettleUnlessSubmitted           ErrorCode = "91576"
+	CodeTransactionCannotSubmitForPartialSettlement      ErrorCode = "915103"
+	CodeTransactionProcessorDoesNotSupportPartialSettle  ErrorCode = "915102"
+	CodeTransactionCannotUpdateDetailsUnlessSubmitted    ErrorCode = "915129"
+	CodeTransactionProcessorDoesNotSupportUpdateDetails  ErrorCode = "915130"
+	CodeTransactionTooManyLineItems                      ErrorCode = "915157"
+	CodeTransactionMustBeInStateAuthorized               ErrorCode = "915218"
+	CodeTransactionProcessorDoesNotSupportAuthAdjustment ErrorCode = "915222"
+	CodeTransactionAdjustmentAmountMustBeGreaterThanZero ErrorCode = "95605"
+	CodeTransactionNoNetAmountToPerformAuthAdjustment    ErrorCode = "95606"
+)
+
+// Transaction line item
+const (
+	CodeLineItemCommodityCodeIsTooLong           ErrorCode = "95801"
+	CodeLineItemDescriptionIsTooLong             ErrorCode = "95803"
+	CodeLineItemDiscountAmountIsInvalid          ErrorCode = "95804"
+	CodeLineItemDiscountAmountIsTooLarge         ErrorCode = "95805"
+	CodeLineItemDiscountAmountCannotBeNegative   ErrorCode = "95806"
+	CodeLineItemKindIsInvalid                    ErrorCode = "95807"
+	CodeLineItemKindIsRequired                   ErrorCode = "95808"
+	CodeLineItemProductCodeIsTooLong             ErrorCode = "95809"
+	CodeLineItemQuantityIsInvalid                ErrorCode = "95810"
+	CodeLineItemQuantityIsRequired               ErrorCode = "95811"
+	CodeLineItemQuantityIsTooLarge               ErrorCode = "95812"
+	CodeLineItemTotalAmountIsInvalid             ErrorCode = "95813"
+	CodeLineItemTotalAmountIsRequired            ErrorCode = "95814"
+	CodeLineItemTotalAmountIsTooLarge            ErrorCode = "95815"
+	CodeLineItemTotalAmountMustBeGreaterThanZero ErrorCode = "95816"
+	CodeLineItemUnitAmountIsInvalid              ErrorCode = "95817"
+	CodeLineItemUnitAmountIsRequired             ErrorCode = "95818"
+	CodeLineItemUnitAmountIsTooLarge             ErrorCode = "95819"
+	CodeLineItemUnitAmountMustBeGreaterThanZero  ErrorCode = "95820"
+	CodeLineItemUnitOfMeasureIsTooLong           ErrorCode = "95821"
+	CodeLineItemNameIsRequired                   ErrorCode = "95822"
+	CodeLineItemNameIsTooLong                    ErrorCode = "95823"
+	CodeLineItemUnitTaxAmountIsInvalid           ErrorCode = "95824"
+	CodeLineItemUnitTaxAmountIsTooLarge          ErrorCode = "95825"
+	CodeLineItemUnitTaxAmountCannotBeNegative    ErrorCode = "95826"
+	CodeLineItemTaxAmountIsTooLarge              ErrorCode = "95828"
+	CodeLineItemTaxAmountCannotBeNegative        ErrorCode = "95829"
+)
+
+type errorCodeInfo struct {
+	description string
+	class       ErrorClass
+}
+
+var errorCodes = map[ErrorCode]errorCodeInfo{
+	CodeAddressCannotBeBlank:                     {"Addresses must have at least one field filled in.", ErrorClassUser},
+	CodeAddressCompanyIsTooLong:                  {"Company is too long.", ErrorClassUser},
+	CodeAddressExtendedAddressIsTooLong:          {"Extended address is too long.", ErrorClassUser},
+	CodeAddressFirstNameIsTooLong:                {"First name is too long.", ErrorClassUser},
+	CodeAddressLastNameIsTooLong:                 {"Last name is too long.", ErrorClassUser},
+	CodeAddressLocalityIsTooLong:                 {"Locality is too long.", ErrorClassUser},
+	CodeAddressPostalCodeIsRequired:              {"Postal code is required.", ErrorClassUser},
+	CodeAddressPostalCodeIsTooLong:               {"Postal code is too long.", ErrorClassUser},
+	CodeAddressRegionIsTooLong:                   {"Region is too long.", ErrorClassUser},
+	CodeAddressStreetAddressIsRequired:           {"Street address is required.", ErrorClassUser},
+	CodeAddressStreetAddressIsTooLong:            {"Street address is too long.", ErrorClassUser},
+	CodeAddressPostalCodeInvalidCharacters:       {"Postal code can only contain letters, numbers, spaces, and hyphens.", ErrorClassUser},
+	CodeAddressStateIsInvalidForSellerProtection: {"State code is invalid for seller protection.", ErrorClassUser},
+	CodeAddressCountryNameIsNotAccepted:          {"Country name is not an accepted country.", ErrorClassUser},
+	CodeAddressCountryCodeAlpha2IsNotAccepted:    {"Country code (alpha2) is not an accepted country.", ErrorClassIntegration},
+	CodeAddressInconsistentCountry:               {"Inconsistent country.", ErrorClassIntegration},
+	CodeAddressCountryCodeAlpha3IsNotAccepted:    {"Country code (alpha3) is not an accepted country.", ErrorClassIntegration},
+	CodeAddressCountryCodeNumericIsNotAccepted:   {"Country code (numeric) is not an accepted country.", ErrorClassIntegration},
+	CodeAddressTooManyAddressesPerCustomer:       {"Customer has already reached the maximum of 50 addresses.", ErrorClassIntegration},
+	CodeAddressFirstNameIsInvalid:                {"First name is invalid.", ErrorClassUser},
+	CodeAddressLastNameIsInvalid:                 {"Last name is invalid.", ErrorClassUser},
+	CodeAddressCompanyIsInvalid:                  {"Company is invalid.", ErrorClassUser},
+	CodeAddressStreetAddressIsInvalid:            {"Street address is invalid.", ErrorClassUser},
+	CodeAddressExtendedAddressIsInvalid:          {"Extended address is invalid.", ErrorClassUser},
+	CodeAddressLocalityIsInvalid:                 {"Locality is invalid.", ErrorClassUser},
+	CodeAddressRegionIsInvalid:                   {"Region is invalid.", ErrorClassUser},
+	CodeAddressPostalCodeIsInvalid:               {"Postal code is invalid.", ErrorClassUser},
+
+	CodeClientTokenCustomerDoesNotExist: {"Customer specified by customer_id does not exist.", ErrorClassIntegration},
+
+	CodeCreditCardTypeIsNotAccepted:                       {"Credit card type is not accepted by this merchant account.", ErrorClassUser},
+	CodeCreditCardCVVIsRequired:                           {"CVV is required.", ErrorClassUser},
+	CodeCreditCardCVVIsInvalid:                            {"CVV must be 4 digits for American Express and 3 digits for other card types.", ErrorClassUser},
+	CodeCreditCardExpirationDateIsRequired:                {"Expiration date is required.", ErrorClassUser},
+	CodeCreditCardExpirationDateIsInvalid:                 {"Expiration date is invalid.", ErrorClassUser},
+	CodeCreditCardExpirationDateYearIsInvalid:             {"Expiration year is invalid. It must be between 1975 and 2200.", ErrorClassUser},
+	CodeCreditCardExpirationMonthIsInvalid:                {"Expiration month is invalid.", ErrorClassUser},
+	CodeCreditCardExpirationYearIsInvalid:                 {"Expiration year is invalid.", ErrorClassUser},
+	CodeCreditCardNumberIsRequired:                        {"Credit card number is required.", ErrorClassUser},
+	CodeCreditCardNumberIsInvalid:                         {"Credit card number is invalid.", ErrorClassUser},
+	CodeCreditCardNumberLengthIsInvalid:                   {"Credit card number must be 12-19 digits.", ErrorClassUser},
+	CodeCreditCardNumberMustBeTestNumber:                  {"Credit card number must be a test number in the sandbox.", ErrorClassIntegration},
+	CodeCreditCardTypeIsNotAcceptedBySubscriptionMerchant: {"Credit card type is not accepted by the subscription merchant account.", ErrorClassUser},
+	CodeCreditCardCardholderNameIsTooLong:                 {"Cardholder name is too long.", ErrorClassUser},
+	CodeCreditCardDuplicateCardExists:                     {"Duplicate card exists in the vault.", ErrorClassUser},
+	CodeCreditCardPaymentMethodConflict:                   {"Cannot provide both a payment method nonce and card details.", ErrorClassIntegration},
+	CodeCreditCardCVVVerificationFailed:                   {"CVV verification failed.", ErrorClassUser},
+	CodeCreditCardPostalCodeVerificationFailed:            {"Postal code verification failed.", ErrorClassUser},
+	CodeCreditCardBillingAddressConflict:                  {"Cannot provide both a billing address and a billing address id.", ErrorClassIntegration},
+	CodeCreditCardBillingAddressIdIsInvalid:               {"Billing address id is invalid.", ErrorClassIntegration},
+	CodeCreditCardCustomerIdIsRequired:                    {"Customer ID is required.", ErrorClassIntegration},
+	CodeCreditCardCustomerIdIsInvalid:                     {"Customer ID is invalid.", ErrorClassIntegration},
+	CodeCreditCardExpirationDateConflict:                  {"Expiration date and month/year cannot both be provided.", ErrorClassIntegration},
+	CodeCreditCardTokenFormatIsInvalid:                    {"Token is invalid.", ErrorClassIntegration},
+	CodeCreditCardTokenIsInUse:                            {"Token is already in use.", ErrorClassIntegration},
+	CodeCreditCardTokenIsTooLong:                          {"Token is too long.", ErrorClassIntegration},
+	CodeCreditCardTokenIsNotAllowed:                       {"Token is not allowed.", ErrorClassIntegration},
+	CodeCreditCardTokenIsRequired:                         {"Token is required.", ErrorClassIntegration},
+	CodeCreditCardUpdateExistingTokenIsInvalid:            {"Update existing token is invalid.", ErrorClassIntegration},
+	CodeCreditCardVerificationMerchantAccountIdIsInvalid:  {"Verification merchant account id is invalid.", ErrorClassIntegration},
+	CodeCreditCardUpdateExistingTokenNotAllowed:           {"Update existing token is not allowed when creating a customer.", ErrorClassIntegration},
+	CodeCreditCardVerificationNotSupportedOnMerchant:      {"Verifications are not supported on this merchant account.", ErrorClassIntegration},
+	CodeCreditCardPaymentMethodNonceConsumed:              {"Payment method nonce has already been consumed.", ErrorClassIntegration},
+	CodeCreditCardPaymentMethodNonceUnknown:               {"Unknown or expired payment method nonce.", ErrorClassIntegration},
+	CodeCreditCardPaymentMethodNonceLocked:                {"Payment method nonce is locked.", ErrorClassIntegration},
+	CodeCreditCardPaymentMethodNonceCardTypeIsNotAccepted: {"Payment method nonce card type is not accepted by this merchant account.", ErrorClassUser},
+
+	CodeCustomerCompanyIsTooLong:          {"Company is too long.", ErrorClassUser},
+	CodeCustomerCustomFieldIsTooLong:      {"Custom field is too long.", ErrorClassIntegration},
+	CodeCustomerEmailIsInvalid:            {"Email is an invalid format.", ErrorClassUser},
+	CodeCustomerEmailIsTooLong:            {"Email is too long.", ErrorClassUser},
+	CodeCustomerEmailIsRequired:           {"Email is required.", ErrorClassUser},
+	CodeCustomerFaxIsTooLong:              {"Fax is too long.", ErrorClassUser},
+	CodeCustomerFirstNameIsTooLong:        {"First name is too long.", ErrorClassUser},
+	CodeCustomerLastNameIsTooLong:         {"Last name is too long.", ErrorClassUser},
+	CodeCustomerPhoneIsTooLong:            {"Phone is too long.", ErrorClassUser},
+	CodeCustomerWebsiteIsTooLong:          {"Website is too long.", ErrorClassUser},
+	CodeCustomerWebsiteIsInvalid:          {"Website is an invalid format.", ErrorClassUser},
+	CodeCustomerCustomFieldIsInvalid:      {"Custom field is invalid.", ErrorClassIntegration},
+	CodeCustomerIdIsInUse:                 {"Customer ID has already been taken.", ErrorClassIntegration},
+	CodeCustomerIdIsInvalid:               {"Customer ID is invalid.", ErrorClassIntegration},
+	CodeCustomerIdIsNotAllowed:            {"Customer ID is not allowed.", ErrorClassIntegration},
+	CodeCustomerIdIsTooLong:               {"Customer ID is too long.", ErrorClassIntegration},
+	CodeCustomerIdIsRequired:              {"Customer ID is required.", ErrorClassIntegration},
+	CodeCustomerNonceBelongsToAnotherUser: {"Vaulted payment method nonce belongs to a different customer.", ErrorClassIntegration},
+
+	CodeDescriptorNameFormatIsInvalid:  {"Descriptor name format is invalid.", ErrorClassIntegration},
+	CodeDescriptorPhoneFormatIsInvalid: {"Descriptor phone format is invalid.", ErrorClassIntegration},
+	CodeDescriptorURLFormatIsInvalid:   {"Descriptor url format is invalid.", ErrorClassIntegration},
+
+	CodeDisputeCanOnlyAddEvidenceToOpenDispute:      {"Evidence can only be attached to disputes that are in an Open state.", ErrorClassIntegration},
+	CodeDisputeCanOnlyRemoveEvidenceFromOpenDispute: {"Evidence can only be removed from disputes that are in an Open state.", ErrorClassIntegration},
+	CodeDisputeCanOnlyAddEvidenceDocumentToDispute:  {"A document with the dispute evidence kind can only be added to a dispute.", ErrorClassIntegration},
+	CodeDisputeCanOnlyAcceptOpenDispute:             {"Disputes can only be accepted when they are in an Open state.", ErrorClassIntegration},
+	CodeDisputeCanOnlyFinalizeOpenDispute:           {"Disputes can only be finalized when they are in an Open state.", ErrorClassIntegration},
+	CodeDisputeEvidenceCategoryIsInvalid:            {"Evidence can only be created with a valid category.", ErrorClassIntegration},
+	CodeDisputeEvidenceContentDateIsInvalid:         {"The date provided as evidence content is invalid.", ErrorClassUser},
+	CodeDisputeEvidenceContentIsTooLong:             {"The evidence content is too long.", ErrorClassUser},
+	CodeDisputeEvidenceContentARNIsTooLong:          {"The ARN provided as evidence content is too long.", ErrorClassUser},
+	CodeDisputeEvidenceContentPhoneIsTooLong:        {"The phone number provided as evidence content is too long.", ErrorClassUser},
+	CodeDisputeEvidenceCategoryTextOnly:             {"This evidence category only accepts text evidence.", ErrorClassIntegration},
+	CodeDisputeEvidenceCategoryDocumentOnly:         {"This evidence category only accepts document evidence.", ErrorClassIntegration},
+	CodeDisputeEvidenceCategoryNotForReasonCode:     {"This evidence category is not allowed for the reason of the dispute.", ErrorClassIntegration},
+	CodeDisputeEvidenceCategoryDuplicate:            {"Evidence with this category was already provided.", ErrorClassIntegration},
+	CodeDisputeEvidenceContentEmailIsInvalid:        {"The email provided as evidence content is invalid.", ErrorClassUser},
+	CodeDisputeEvidenceContentCannotBeBlank:         {"Content cannot be blank.", ErrorClassIntegration},
+	CodeDisputeValidEvidenceRequiredToFinalize:      {"Valid evidence is required to finalize the dispute.", ErrorClassIntegration},
+
+	CodeMerchantAccountIdIsTooLong:                        {"Merchant account id is too long.", ErrorClassIntegration},
+	CodeMerchantAccountIdFormatIsInvalid:                  {"Merchant account id format is invalid.", ErrorClassIntegration},
+	CodeMerchantAccountIdIsInUse:                          {"Merchant account id is in use.", ErrorClassIntegration},
+	CodeMerchantAccountIdIsNotAllowed:                     {"Merchant account id is not allowed.", ErrorClassIntegration},
+	CodeMerchantAccountMasterMerchantAccountIdIsRequired:  {"Master merchant account id is required.", ErrorClassIntegration},
+	CodeMerchantAccountMasterMerchantAccountIdIsInvalid:   {"Master merchant account id is invalid.", ErrorClassIntegration},
+	CodeMerchantAccountMasterMerchantAccountMustBeActive:  {"Master merchant account must be active.", ErrorClassIntegration},
+	CodeMerchantAccountFirstNameIsRequired:                {"First name is required.", ErrorClassUser},
+	CodeMerchantAccountTosAcceptedIsRequired:              {"Terms of service need to be accepted.", ErrorClassUser},
+	CodeMerchantAccountLastNameIsRequired:                 {"Last name is required.", ErrorClassUser},
+	CodeMerchantAccountDateOfBirthIsRequired:              {"Date of birth is required.", ErrorClassUser},
+	CodeMerchantAccountRoutingNumberIsRequired:            {"Routing number is required.", ErrorClassUser},
+	CodeMerchantAccountAccountNumberIsRequired:            {"Account number is required.", ErrorClassUser},
+	CodeMerchantAccountSSNIsInvalid:                       {"SSN must be either blank, last 4 digits, or full 9 digits.", ErrorClassUser},
+	CodeMerchantAccountEmailIsInvalid:                     {"Email address is invalid.", ErrorClassUser},
+	CodeMerchantAccountDeclinedOFAC:                       {"Applicant declined due to OFAC.", ErrorClassUser},
+	CodeMerchantAccountDeclinedMasterCardMatch:            {"Applicant declined due to MasterCard MATCH.", ErrorClassUser},
+	CodeMerchantAccountDeclinedFailedKYC:                  {"Applicant declined due to failed KYC.", ErrorClassUser},
+	CodeMerchantAccountDeclinedSSNInvalid:                 {"Applicant declined due to invalid SSN.", ErrorClassUser},
+	CodeMerchantAccountDeclined:                           {"Applicant declined.", ErrorClassUser},
+	CodeMerchantAccountFirstNameIsInvalid:                 {"First name is invalid.", ErrorClassUser},
+	CodeMerchantAccountLastNameIsInvalid:                  {"Last name is invalid.", ErrorClassUser},
+	CodeMerchantAccountCannotBeUpdated:                    {"Merchant account cannot be updated.", ErrorClassIntegration},
+	CodeMerchantAccountIdCannotBeUpdated:                  {"Merchant account id cannot be updated.", ErrorClassIntegration},
+	CodeMerchantAccountMasterMerchantAccountIdNotEditable: {"Master merchant account id cannot be updated.", ErrorClassIntegration},
+
+	CodePaymentMethodNonceIsRequired: {"Nonce is required.", ErrorClassIntegration},
+
+	CodeSettlementBatchSummarySettlementDateIsInvalid: {"Settlement Date is invalid.", ErrorClassIntegration},
+
+	CodeSubscriptionCannotEditCanceled:                    {"Cannot edit a canceled subscription.", ErrorClassIntegration},
+	CodeSubscriptionIdIsInUse:                             {"ID has already been taken.", ErrorClassIntegration},
+	CodeSubscriptionPriceCannotBeBlank:                    {"Price cannot be blank.", ErrorClassIntegration},
+	CodeSubscriptionPriceIsInvalid:                        {"Price is an invalid format.", ErrorClassIntegration},
+	CodeSubscriptionStatusIsCanceled:                      {"Subscription has already been canceled.", ErrorClassIntegration},
+	CodeSubscriptionTokenFormatIsInvalid:                  {"ID is invalid (use only letters, numbers, '-', and '_').", ErrorClassIntegration},
+	CodeSubscriptionTrialDurationIsInvalid:                {"Trial duration is an invalid format.", ErrorClassIntegration},
+	CodeSubscriptionTrialDurationIsRequired:               {"Trial duration is required.", ErrorClassIntegration},
+	CodeSubscriptionTrialDurationUnitIsInvalid:            {"Trial duration unit is invalid.", ErrorClassIntegration},
+	CodeSubscriptionCannotEditExpired:                     {"Cannot edit an expired subscription.", ErrorClassIntegration},
+	CodeSubscriptionPriceIsTooLarge:                       {"Price is too large.", ErrorClassIntegration},
+	CodeSubscriptionMerchantAccountIdIsInvalid:            {"Merchant account ID is invalid.", ErrorClassIntegration},
+	CodeSubscriptionPaymentMethodTokenCardTypeNotAccepted: {"Payment method token card type is not accepted by this merchant account.", ErrorClassUser},
+	CodeSubscriptionPaymentMethodTokenIsInvalid:           {"Payment method token is invalid.", ErrorClassIntegration},
+	CodeSubscriptionPlanIdIsInvalid:                       {"Plan ID is invalid.", ErrorClassIntegration},
+	CodeSubscriptionPaymentMethodTokenNotOfCustomer:       {"Payment method token does not belong to the subscription's customer.", ErrorClassIntegration},
+	CodeSubscriptionNumberOfBillingCyclesMustBeNumeric:    {"Number of billing cycles must be numeric.", ErrorClassIntegration},
+	CodeSubscriptionNumberOfBillingCyclesMustBePositive:   {"Number of billing cycles must be greater than zero.", ErrorClassIntegration},
+	CodeSubscriptionInconsistentNumberOfBillingCycles:     {"Number of billing cycles cannot be specified if never expires is true.", ErrorClassIntegration},
+	CodeSubscriptionNumberOfBillingCyclesIsTooSmall:       {"Number of billing cycles cannot be less than the current billing cycle.", ErrorClassIntegration},
+	CodeSubscriptionCannotAddDuplicateAddOnOrDiscount:     {"Cannot add duplicate add-on or discount.", ErrorClassIntegration},
+	CodeSubscriptionNumberOfBillingCyclesCannotBeBlank:    {"Number of billing cycles cannot be blank if the subscription expires.", ErrorClassIntegration},
+	CodeSubscriptionBillingDayOfMonthMustBeNumeric:        {"Billing day of month must be numeric.", ErrorClassIntegration},
+	CodeSubscriptionBillingDayOfMonthIsInvalid:            {"Billing day of month must be a number between 1 and 31.", ErrorClassIntegration},
+	CodeSubscriptionFirstBillingDateIsInvalid:             {"First billing date is invalid.", ErrorClassIntegration},
+	CodeSubscriptionFirstBillingDateCannotBeInThePast:     {"First billing date cannot be in the past.", ErrorClassIntegration},
+	CodeSubscriptionInconsistentStartDate:                 {"Billing day of month and first billing date cannot both be specified.", ErrorClassIntegration},
+	CodeSubscriptionBillingDayOfMonthCannotBeUpdated:      {"Billing day of month cannot be updated.", ErrorClassIntegration},
+	CodeSubscriptionFirstBillingDateCannotBeUpdated:       {"First billing date cannot be updated.", ErrorClassIntegration},
+	CodeSubscriptionCannotEditPriceOnPastDue:              {"Cannot edit price changing fields on a past due subscription.", ErrorClassIntegration},
+	CodeSubscriptionInvalidRequestFormat:                  {"Invalid request format.", ErrorClassIntegration},
+	CodeSubscriptionPlanBillingFrequencyCannotBeUpdated:   {"Cannot update a subscription to a plan with a different billing frequency.", ErrorClassIntegration},
+	CodeSubscriptionMismatchCurrencyISOCode:               {"Merchant account currency does not match the plan currency.", ErrorClassIntegration},
+	CodeSubscriptionPaymentMethodNonceCardTypeNotAccepted: {"Payment method nonce card type is not accepted by this merchant account.", ErrorClassUser},
+	CodeSubscriptionPaymentMethodNonceIsInvalid:           {"Payment method nonce is invalid.", ErrorClassIntegration},
+	CodeSubscriptionPaymentMethodNonceNotOfCustomer:       {"Payment method nonce does not belong to the subscription's customer.", ErrorClassIntegration},
+	CodeSubscriptionPaymentMethodNonceUnvaultedCard:       {"Payment method nonce must refer to a vaulted card.", ErrorClassIntegration},
+
+	CodeTransactionAmountCannotBeNegative:                {"Amount cannot be negative.", ErrorClassIntegration},
+	CodeTransactionAmountIsRequired:                      {"Amount is required.", ErrorClassIntegration},
+	CodeTransactionAmountIsInvalid:                       {"Amount is an invalid format.", ErrorClassIntegration},
+	CodeTransactionCustomerDefaultCardTypeNotAccepted:    {"Customer's default payment method card type is not accepted by this merchant account.", ErrorClassUser},
+	CodeTransactionProcessorAuthorizationCodeIsInvalid:   {"Processor authorization code is invalid.", ErrorClassIntegration},
+	CodeTransactionCustomFieldIsTooLong:                  {"Custom field is too long.", ErrorClassIntegration},
+	CodeTransactionAmountIsTooLarge:                      {"Amount is too large.", ErrorClassIntegration},
+	CodeTransactionAmountMustBeGreaterThanZero:           {"Amount must be greater than zero.", ErrorClassIntegration},
+	CodeTransactionTaxAmountCannotBeNegative:             {"Tax amount cannot be negative.", ErrorClassIntegration},
+	CodeTransactionTaxAmountIsInvalid:                    {"Tax amount is an invalid format.", ErrorClassIntegration},
+	CodeTransactionTaxAmountIsTooLarge:                   {"Tax amount is too large.", ErrorClassIntegration},
+	CodeTransactionThreeDSecureAuthenticationFailed:      {"3D Secure authentication failed.", ErrorClassUser},
+	CodeTransactionOrderIdIsTooLong:                      {"Order ID is too long.", ErrorClassIntegration},
+	CodeTransactionCannotBeVoided:                        {"Transaction can only be voided if status is authorized or submitted_for_settlement.", ErrorClassIntegration},
+	CodeTransactionCannotRefundCredit:                    {"Cannot refund a credit.", ErrorClassIntegration},
+	CodeTransactionCannotRefundUnlessSettled:             {"Cannot refund transaction unless it is settled.", ErrorClassIntegration},
+	CodeTransactionCannotSubmitForSettlement:             {"Cannot submit for settlement unless status is authorized.", ErrorClassIntegration},
+	CodeTransactionCreditCardIsRequired:                  {"Credit card is required.", ErrorClassIntegration},
+	CodeTransactionCustomerIdIsInvalid:                   {"Customer ID is invalid.", ErrorClassIntegration},
+	CodeTransactionCustomerDoesNotHaveCreditCard:         {"Customer specified does not have a credit card.", ErrorClassIntegration},
+	CodeTransactionHasAlreadyBeenRefunded:                {"Transaction has already been fully refunded.", ErrorClassIntegration},
+	CodeTransactionMerchantAccountIdIsInvalid:            {"Merchant account ID is invalid.", ErrorClassIntegration},
+	CodeTransactionMerchantAccountIsSuspended:            {"Merchant account is suspended.", ErrorClassIntegration},
+	CodeTransactionPaymentMethodConflict:                 {"Cannot provide more than one payment method.", ErrorClassIntegration},
+	CodeTransactionPaymentMethodNotOfCustomer:            {"Payment method does not belong to the customer.", ErrorClassIntegration},
+	CodeTransactionPaymentMethodTokenCardTypeNotAccepted: {"Payment method token card type is not accepted by this merchant account.", ErrorClassUser},
+	CodeTransactionPaymentMethodTokenIsInvalid:           {"Payment method token is invalid.", ErrorClassIntegration},
+	CodeTransactionProcessorAuthorizationCodeCannotBeSet: {"Processor authorization code cannot be set unless for a voice authorization.", ErrorClassIntegration},
+	CodeTransactionRefundAmountIsTooLarge:                {"Refund amount is too large.", ErrorClassIntegration},
+	CodeTransactionSettlementAmountIsTooLarge:            {"Settlement amount is too large.", ErrorClassIntegration},
+	CodeTransactionTypeIsInvalid:                         {"Transaction type is invalid.", ErrorClassIntegration},
+	CodeTransactionTypeIsRequired:                        {"Transaction type is required.", ErrorClassIntegration},
+	CodeTransactionCustomFieldIsInvalid:                  {"Custom field is invalid.", ErrorClassIntegration},
+	CodeTransactionPaymentMethodNotOfSubscription:        {"Payment method does not belong to the subscription.", ErrorClassIntegration},
+	CodeTransactionSubscriptionIdIsInvalid:               {"Subscription ID is invalid.", ErrorClassIntegration},
+	CodeTransactionSubscriptionNotOfCustomer:             {"Subscription does not belong to the customer.", ErrorClassIntegration},
+	CodeTransactionBillingAddressConflict:                {"Cannot provide both a billing address and a billing address id.", ErrorClassIntegration},
+	CodeTransactionSubscriptionStatusMustBePastDue:       {"Subscription status must be Past Due in order to retry.", ErrorClassIntegration},
+	CodeTransactionPurchaseOrderNumberIsTooLong:          {"Purchase order number is too long.", ErrorClassIntegration},
+	CodeTransactionCannotRefundWithSuspendedMerchant:     {"Cannot refund a transaction on a suspended merchant account.", ErrorClassIntegration},
+	CodeTransactionCannotCloneVaultCreditCard:            {"Cannot clone a transaction using a vaulted credit card with cvv or billing address.", ErrorClassIntegration},
+	CodeTransactionCannotCloneVoiceAuthorization:         {"Cannot clone voice authorization transactions.", ErrorClassIntegration},
+	CodeTransactionCannotCloneUnsuccessful:               {"Cannot clone an unsuccessful transaction.", ErrorClassIntegration},
+	CodeTransactionCannotCloneCredit:                     {"Cannot clone a credit.", ErrorClassIntegration},
+	CodeTransactionProcessorDoesNotSupportCredits:        {"Processor does not support credits.", ErrorClassIntegration},
+	CodeTransactionMerchantAccountDoesNotSupportRefunds:  {"Merchant account does not support refunds.", ErrorClassIntegration},
+	CodeTransactionPurchaseOrderNumberIsInvalid:          {"Purchase order number is invalid.", ErrorClassIntegration},
+	CodeTransactionChannelIsTooLong:                      {"Channel is too long.", ErrorClassIntegration},
+	CodeTransactionSettlementAmountBelowServiceFee:       {"Settlement amount cannot be less than the service fee amount.", ErrorClassIntegration},
+	CodeTransactionServiceFeeIsNotAllowedOnCredits:       {"Service fee is not allowed on credits.", ErrorClassIntegration},
+	CodeTransactionServiceFeeAmountCannotBeNegative:      {"Service fee amount cannot be negative.", ErrorClassIntegration},
+	CodeTransactionServiceFeeAmountIsInvalid:             {"Service fee amount is an invalid format.", ErrorClassIntegration},
+	CodeTransactionServiceFeeAmountIsTooLarge:            {"Service fee amount is too large.", ErrorClassIntegration},
+	CodeTransactionServiceFeeNotAllowedOnMasterMerchant:  {"Service fee is not allowed on a master merchant account.", ErrorClassIntegration},
+	CodeTransactionMerchantAccountDoesNotSupportMOTO:     {"Merchant account does not support MOTO transactions.", ErrorClassIntegration},
+	CodeTransactionCannotRefundWithPendingMerchant:       {"Cannot refund a transaction on a pending merchant account.", ErrorClassIntegration},
+	CodeTransactionCannotHoldInEscrow:                    {"Transaction could not be held in escrow.", ErrorClassIntegration},
+	CodeTransactionCannotReleaseFromEscrow:               {"Cannot release a transaction that is not escrowed.", ErrorClassIntegration},
+	CodeTransactionCannotCancelRelease:                   {"Release can only be cancelled if the transaction is submitted for release.", ErrorClassIntegration},
+	CodeTransactionCannotPartiallyRefundEscrowed:         {"Cannot partially refund an escrowed transaction.", ErrorClassIntegration},
+	CodeTransactionPaymentMethodNonceConsumed:            {"Payment method nonce has already been consumed.", ErrorClassIntegration},
+	CodeTransactionPaymentMethodNonceUnknown:             {"Unknown or expired payment method nonce.", ErrorClassIntegration},
+	CodeTransactionPaymentMethodNonceLocked:              {"Payment method nonce is locked.", ErrorClassIntegration},
+	CodeTransactionPaymentMethodNonceCardTypeNotAccepted: {"Payment method nonce card type is not accepted by this merchant account.", ErrorClassUser},
+	CodeTransactionThreeDSecureTokenIsInvalid:            {"3D Secure token is invalid.", ErrorClassIntegration},
+	CodeTransactionCannotRefundSettling:                  {"Cannot refund a transaction while it is settling.", ErrorClassIntegration},
+	CodeTransactionCannotRefundHeldInEscrow:              {"Cannot refund a transaction that is held in escrow.", ErrorClassIntegration},
+	CodeTransactionCannotSimulateSettlement:              {"Settlement can only be simulated in the sandbox.", ErrorClassIntegration},
+	CodeTransactionCannotSettleUnlessSubmitted:           {"Cannot settle a transaction unless it is submitted for settlement.", ErrorClassIntegration},
+	CodeTransactionCannotSubmitForPartialSettlement:      {"Cannot submit for partial settlement.", ErrorClassIntegration},
+	CodeTransactionProcessorDoesNotSupportPartialSettle:  {"Processor does not support partial settlement.", ErrorClassIntegration},
+	CodeTransactionCannotUpdateDetailsUnlessSubmitted:    {"Transaction details can only be updated while submitted for settlement.", ErrorClassIntegration},
+	CodeTransactionProcessorDoesNotSupportUpdateDetails:  {"Processor does not support updating transaction details.", ErrorClassIntegration},
+	CodeTransactionTooManyLineItems:                      {"Too many line items.", ErrorClassIntegration},
+	CodeTransactionMustBeInStateAuthorized:               {"Transaction must be in state authorized.", ErrorClassIntegration},
+	CodeTransactionProcessorDoesNotSupportAuthAdjustment: {"Processor does not support authorization adjustment.", ErrorClassIntegration},
+	CodeTransactionAdjustmentAmountMustBeGreaterThanZero: {"Adjustment amount must be greater than zero.", ErrorClassIntegration},
+	CodeTransactionNoNetAmountToPerformAuthAdjustment:    {"There is no net amount to perform an authorization adjustment.", ErrorClassIntegration},
+
+	CodeLineItemCommodityCodeIsTooLong:           {"Commodity code is too long.", ErrorClassIntegration},
+	CodeLineItemDescriptionIsTooLong:             {"Description is too long.", ErrorClassIntegration},
+	CodeLineItemDiscountAmountIsInvalid:          {"Discount amount is an invalid format.", ErrorClassIntegration},
+	CodeLineItemDiscountAmountIsTooLarge:         {"Discount amount is too large.", ErrorClassIntegration},
+	CodeLineItemDiscountAmountCannotBeNegative:   {"Discount amount cannot be negative.", ErrorClassIntegration},
+	CodeLineItemKindIsInvalid:                    {"Kind is invalid.", ErrorClassIntegration},
+	CodeLineItemKindIsRequired:                   {"Kind is required.", ErrorClassIntegration},
+	CodeLineItemProductCodeIsTooLong:             {"Product code is too long.", ErrorClassIntegration},
+	CodeLineItemQuantityIsInvalid:                {"Quantity is an invalid format.", ErrorClassIntegration},
+	CodeLineItemQuantityIsRequired:               {"Quantity is required.", ErrorClassIntegration},
+	CodeLineItemQuantityIsTooLarge:               {"Quantity is too large.", ErrorClassIntegration},
+	CodeLineItemTotalAmountIsInvalid:             {"Total amount is an invalid format.", ErrorClassIntegration},
+	CodeLineItemTotalAmountIsRequired:            {"Total amount is required.", ErrorClassIntegration},
+	CodeLineItemTotalAmountIsTooLarge:            {"Total amount is too large.", ErrorClassIntegration},
+	CodeLineItemTotalAmountMustBeGreaterThanZero: {"Total amount must be greater than zero.", ErrorClassIntegration},
+	CodeLineItemUnitAmountIsInvalid:              {"Unit amount is an invalid format.", ErrorClassIntegration},
+	CodeLineItemUnitAmountIsRequired:             {"Unit amount is required.", ErrorClassIntegration},
+	CodeLineItemUnitAmountIsTooLarge:             {"Unit amount is too large.", ErrorClassIntegration},
+	CodeLineItemUnitAmountMustBeGreaterThanZero:  {"Unit amount must be greater than zero.", ErrorClassIntegration},
+	CodeLineItemUnitOfMeasureIsTooLong:           {"Unit of measure is too long.", ErrorClassIntegration},
+	CodeLineItemNameIsRequired:                   {"Name is required.", ErrorClassIntegration},
+	CodeLineItemNameIsTooLong:                    {"Name is too long.", ErrorClassIntegration},
+	CodeLineItemUnitTaxAmountIsInvalid:           {"Unit tax amount is an invalid format.", ErrorClassIntegration},
+	CodeLineItemUnitTaxAmountIsTooLarge:          {"Unit tax amount is too large.", ErrorClassIntegration},
+	CodeLineItemUnitTaxAmountCannotBeNegative:    {"Unit tax amount cannot be negative.", ErrorClassIntegration},
+	CodeLineItemTaxAmountIsTooLarge:              {"Tax amount is too large.", ErrorClassIntegration},
+	CodeLineItemTaxAmountCannotBeNegative:        {"Tax amount cannot be negative.", ErrorClassIntegration},
+}
+
+// Known reports whether the code is in the catalog
+func (c ErrorCode) Known() bool {
+	_, ok := errorCodes[c]
+	return ok
+}
+
+// Description returns the human readable description of the code, or an empty string for unknown codes
+func (c ErrorCode) Description() string {
+	return errorCodes[c].description
+}
+
+// Class returns who can fix the error, ErrorClassUnknown for codes not in the catalog
+func (c ErrorCode) Class() ErrorClass {
+	return errorCodes[c].class
+}
@@ -0,0 +1,89 @@
+package braintree
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// HdrRequestID is the header carrying the id the gateway gave to the request, to be quoted to Braintree support
+const HdrRequestID = "X-Request-Id"
+
+// Sentinel errors, to be checked with errors.Is on the errors returned by the APIClient methods
+var (
+	ErrNotFound           = errors.New("braintree: not found")
+	ErrAuthentication     = errors.New("braintree: authentication failed")
+	ErrAuthorization      = errors.New("braintree: not authorized")
+	ErrUpgradeRequired    = errors.New("braintree: client library upgrade required")
+	ErrRateLimited        = errors.New("braintree: too many requests")
+	ErrServerError        = errors.New("braintree: server error")
+	ErrDownForMaintenance = errors.New("braintree: down for maintenance")
+	ErrProcessorDeclined  = errors.New("braintree: processor declined")
+	ErrGatewayRejected    = errors.New("braintree: gateway rejected")
+	ErrInvalidTxType      = errors.New("braintree: invalid transaction type")
+	// ErrSettlementAmountTooLarge is returned, without calling the gateway, for the partial settlements
+	// exceeding the amount left on the authorization
+	ErrSettlementAmountTooLarge = errors.New("braintree: settlement amount exceeds the authorization")
+	// ErrInvalidTxUpdate is returned, without calling the gateway, for the malformed TxDetailsRequest and
+	// AdjustAuthorizationRequest
+	ErrInvalidTxUpdate = errors.New("braintree: invalid transaction update")
+	// ErrOperationNotAllowed is returned, without calling the gateway, by the clients with Guards for the
+	// operations the transaction does not allow in its status, and for the partial settlements of the
+	// transactions which are not authorized
+	ErrOperationNotAllowed = errors.New("braintree: operation not allowed in the transaction status")
+
+	ErrNotAllowedInProduction = errors.New("braintree: operation not allowed in production environment")
+)
+
+// ResponseError is implemented by the errors built from a gateway response (*APIError, *HTTPError and
+// the invalid response error), to be retrieved with errors.As
+type ResponseError interface {
+	IAPIError
+	Response() *Response
+	Path() string
+	RequestID() string
+}
+
+// statusError returns the sentinel matching the HTTP status code, if any
+func statusError(code int) error {
+	switch code {
+	case http.StatusUnauthorized:
+		return ErrAuthentication
+	case http.StatusForbidden:
+		return ErrAuthorization
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusUpgradeRequired:
+		return ErrUpgradeRequired
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusServiceUnavailable:
+		return ErrDownForMaintenance
+	}
+	if code >= http.StatusInternalServerError {
+		return ErrServerError
+	}
+	return nil
+}
+
+// requestPath returns the path of the request relative to the merchant, as passed to the client methods
+func requestPath(r *Response) string {
+	if r == nil || r.Response == nil || r.Request == nil || r.Request.URL == nil {
+		return ""
+	}
+	path := strings.TrimPrefix(r.Request.URL.Path, "/")
+	if strings.HasPrefix(path, "merchants/") {
+		parts := strings.SplitN(path, "/", 3)
+		if len(parts) == 3 {
+			return parts[2]
+		}
+	}
+	return path
+}
+
+func requestID(r *Response) string {
+	if r == nil || r.Response == nil {
+		return ""
+	}
+	return r.Header.Get(HdrRequestID)
+}
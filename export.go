@@ -0,0 +1,319 @@
+package braintree
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ExportFormat string
+
+const (
+	ExportCSV   ExportFormat = "csv"
+	ExportJSONL ExportFormat = "jsonl"
+)
+
+// Exporter writes the records matching a search as CSV, with a header line, or as JSON Lines, one object per
+// record. The records are streamed page by page, so that the memory used does not depend on their number.
+//
+// A column is the path of a field, its segments being the field names of the gateway with underscores or
+// dashes (or the Go field names) : "credit_card.last4", "customer.email". A segment is a key in custom fields
+// ("custom_fields.po") and an index in lists ("disputes.0.status"). Missing values are written empty, or as
+// null in JSON. Times are written in RFC 3339 UTC and the amounts as decimal strings.
+//
+//	e := &Exporter{Client: c, Format: ExportCSV, Columns: []string{"id", "status", "amount", "customer.email"}}
+//	n, err := e.ExportTxs(ctx, file, query)
+type Exporter struct {
+	Client *APIClient
+	Format ExportFormat
+	// Columns are the exported fields, a default set for the kind of record when empty
+	Columns []string
+	// Concurrency is the number of pages fetched ahead, see TxIterator
+	Concurrency int
+}
+
+var (
+	defaultTxColumns           = []string{"id", "type", "status", "amount", "currency", "order_id", "created_at"}
+	defaultCustomerColumns     = []string{"id", "first_name", "last_name", "company", "email", "created_at"}
+	defaultSubscriptionColumns = []string{"id", "plan_id", "status", "price", "next_billing_date", "created_at"}
+
+	// txColumnAliases are the short names of the columns of transactions
+	txColumnAliases = map[string]string{"currency": "currency_iso_code"}
+)
+
+// ExportTxs writes the transactions matching the search, returning the number of transactions written
+func (e *Exporter) ExportTxs(ctx context.Context, w io.Writer, query *Search) (int, error) {
+	it := e.Client.IterateTxs(query)
+	it.Concurrency = e.Concurrency
+	next := func() (interface{}, bool) {
+		if !it.Next(ctx) {
+			return nil, false
+		}
+		return it.Value(), true
+	}
+	return e.export(w, reflect.TypeOf(Tx{}), defaultTxColumns, txColumnAliases, next, it.Err)
+}
+
+func (e *Exporter) ExportCustomers(ctx context.Context, w io.Writer, query *Search) (int, error) {
+	it := e.Client.IterateCustomers(query)
+	it.Concurrency = e.Concurrency
+	next := func() (interface{}, bool) {
+		if !it.Next(ctx) {
+			return nil, false
+		}
+		return it.Value(), true
+	}
+	return e.export(w, reflect.TypeOf(Customer{}), defaultCustomerColumns, nil, next, it.Err)
+}
+
+func (e *Exporter) ExportSubscriptions(ctx context.Context, w io.Writer, query *Search) (int, error) {
+	it := e.Client.IterateSubscriptions(query)
+	it.Concurrency = e.Concurrency
+	next := func() (interface{}, bool) {
+		if !it.Next(ctx) {
+			return nil, false
+		}
+		return it.Value(), true
+	}
+	return e.export(w, reflect.TypeOf(Subscription{}), defaultSubscriptionColumns, nil, next, it.Err)
+}
+
+func (e *Exporter) export(w io.Writer, record reflect.Type, defaults []string, aliases map[string]string, next func() (interface{}, bool), iterErr func() error) (int, error) {
+	names := e.Columns
+	if len(names) == 0 {
+		names = defaults
+	}
+	columns := make([]exportColumn, len(names))
+	for i, name := range names {
+		path := name
+		if alias, ok := aliases[name]; ok {
+			path = alias
+		}
+		column, err := resolveColumn(record, name, path)
+		if err != nil {
+			return 0, err
+		}
+		columns[i] = column
+	}
+
+	var (
+		write func(values []interface{}) error
+		flush func() error
+	)
+	switch e.Format {
+	case ExportCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(names); err != nil {
+			return 0, err
+		}
+		row := make([]string, len(columns))
+		write = func(values []interface{}) error {
+			for i, value := range values {
+				row[i] = ""
+				if value != nil {
+					row[i] = fmt.Sprint(value)
+				}
+			}
+			return cw.Write(row)
+		}
+		flush = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+	case ExportJSONL:
+		bw := bufio.NewWriter(w)
+		keys := make([][]byte, len(names))
+		for i, name := range names {
+			keys[i], _ = json.Marshal(name)
+		}
+		write = func(values []interface{}) error {
+			bw.WriteByte('{')
+			for i, value := range values {
+				if i > 0 {
+					bw.WriteByte(',')
+				}
+				bw.Write(keys[i])
+				bw.WriteByte(':')
+				b, err := json.Marshal(value)
+				if err != nil {
+					return err
+				}
+				bw.Write(b)
+			}
+			_, err := bw.WriteString("}\n")
+			return err
+		}
+		flush = bw.Flush
+	default:
+		return 0, fmt.Errorf("unknown export format %q", e.Format)
+	}
+
+	count := 0
+	values := make([]interface{}, len(columns))
+	for {
+		record, ok := next()
+		if !ok {
+			break
+		}
+		v := reflect.ValueOf(record)
+		for i, column := range columns {
+			values[i] = column.value(v)
+		}
+		if err := write(values); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if err := flush(); err != nil {
+		return count, err
+	}
+	return count, iterErr()
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	dateType    = reflect.TypeOf(Date{})
+	decimalType = reflect.TypeOf(Decimal{})
+)
+
+// exportColumn is the path of a column, as struct field indexes, map keys or slice indexes
+type exportColumn struct {
+	steps []exportStep
+}
+
+type exportStep struct {
+	field int
+	key   string
+	index int
+	kind  reflect.Kind
+}
+
+func resolveColumn(record reflect.Type, name, path string) (exportColumn, error) {
+	var column exportColumn
+	t := record
+	for _, segment := range strings.Split(path, ".") {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		switch {
+		case t.Kind() == reflect.Struct && !isExportLeaf(t):
+			field, ok := exportField(t, segment)
+			if !ok {
+				return column, fmt.Errorf("export column %q : %s has no field %q", name, t.Name(), segment)
+			}
+			column.steps = append(column.steps, exportStep{kind: reflect.Struct, field: field})
+			t = t.Field(field).Type
+		case t.Kind() == reflect.Map && t.Key().Kind() == reflect.String:
+			column.steps = append(column.steps, exportStep{kind: reflect.Map, key: segment})
+			t = t.Elem()
+		case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 {
+				return column, fmt.Errorf("export column %q : %q is not an index of a list", name, segment)
+			}
+			column.steps = append(column.steps, exportStep{kind: reflect.Slice, index: index})
+			t = t.Elem()
+		default:
+			return column, fmt.Errorf("export column %q : %q is a value, it has no field %q", name, t.Name(), segment)
+		}
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if !isExportLeaf(t) {
+			return column, fmt.Errorf("export column %q : %s is not a value, pick one of its fields", name, t.Name())
+		}
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Interface, reflect.Func, reflect.Chan:
+		return column, fmt.Errorf("export column %q is not a value, pick one of its elements", name)
+	}
+	return column, nil
+}
+
+func isExportLeaf(t reflect.Type) bool {
+	return t == timeType || t == dateType || t == decimalType
+}
+
+// exportField finds the field by its xml name or its Go name, ignoring the case, the dashes and the underscores
+func exportField(t reflect.Type, segment string) (int, bool) {
+	normalize := func(s string) string {
+		return strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(s))
+	}
+	want := normalize(segment)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" || field.Name == "XMLName" {
+			continue
+		}
+		tag := field.Tag.Get("xml")
+		if j := strings.IndexAny(tag, ",>"); j >= 0 {
+			tag = tag[:j]
+		}
+		if normalize(tag) == want || normalize(field.Name) == want {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// value walks the column path, returning nil when a part of it is missing
+func (c exportColumn) value(v reflect.Value) interface{} {
+	for _, step := range c.steps {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return nil
+			}
+			v = v.Elem()
+		}
+		switch step.kind {
+		case reflect.Struct:
+			v = v.Field(step.field)
+		case reflect.Map:
+			v = v.MapIndex(reflect.ValueOf(step.key).Convert(v.Type().Key()))
+			if !v.IsValid() {
+				return nil
+			}
+		case reflect.Slice:
+			if step.index >= v.Len() {
+				return nil
+			}
+			v = v.Index(step.index)
+		}
+	}
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	switch v.Type() {
+	case timeType:
+		return v.Interface().(time.Time).UTC().Format(time.RFC3339)
+	case dateType:
+		return v.Interface().(Date).Format(DateFormat)
+	case decimalType:
+		d := v.Interface().(Decimal)
+		return d.String()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint()
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	}
+	return fmt.Sprint(v.Interface())
+}
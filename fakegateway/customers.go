@@ -0,0 +1,731 @@
+package fakegateway
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/badu/braintree"
+)
+
+// nonces accepted without being created first, as the sandbox does
+var fakeNonces = map[string]string{
+	"fake-valid-nonce":            "4111111111111111",
+	"fake-valid-visa-nonce":       "4111111111111111",
+	"fake-valid-mastercard-nonce": "5555555555554444",
+	"fake-valid-amex-nonce":       "378282246310005",
+	"fake-valid-discover-nonce":   "6011111111111117",
+}
+
+func cardType(number string) string {
+	switch {
+	case strings.HasPrefix(number, "4"):
+		return "Visa"
+	case strings.HasPrefix(number, "5"), strings.HasPrefix(number, "2"):
+		return "MasterCard"
+	case strings.HasPrefix(number, "34"), strings.HasPrefix(number, "37"):
+		return "American Express"
+	case strings.HasPrefix(number, "6011"), strings.HasPrefix(number, "65"):
+		return "Discover"
+	case strings.HasPrefix(number, "35"):
+		return "JCB"
+	}
+	return "Unknown"
+}
+
+func luhn(number string) bool {
+	if len(number) < 12 || len(number) > 19 {
+		return false
+	}
+	sum := 0
+	for i := 0; i < len(number); i++ {
+		digit := int(number[len(number)-1-i] - '0')
+		if digit < 0 || digit > 9 {
+			return false
+		}
+		if i%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	return sum%10 == 0
+}
+
+// expiration returns the month and the four digits year of the card
+func expiration(card *braintree.CreditCard) (string, string, bool) {
+	month, year := card.ExpirationMonth, card.ExpirationYear
+	if card.ExpirationDate != "" {
+		parts := strings.Split(card.ExpirationDate, "/")
+		if len(parts) != 2 {
+			return "", "", false
+		}
+		month, year = parts[0], parts[1]
+	}
+	if len(year) == 2 {
+		year = "20" + year
+	}
+	m, err := strconv.Atoi(month)
+	if err != nil || m < 1 || m > 12 {
+		return "", "", false
+	}
+	if _, err := strconv.Atoi(year); err != nil || len(year) != 4 {
+		return "", "", false
+	}
+	return month, year, true
+}
+
+func validateCard(card *braintree.CreditCard, path ...string) []fieldError {
+	var errors []fieldError
+	switch {
+	case card.Number == "":
+		errors = append(errors, newError(braintree.CodeCreditCardNumberIsRequired, "number", "Credit card number is required.", path...))
+	case !luhn(card.Number):
+		errors = append(errors, newError(braintree.CodeCreditCardNumberIsInvalid, "number", "Credit card number is invalid.", path...))
+	}
+	if card.ExpirationDate == "" && card.ExpirationMonth == "" && card.ExpirationYear == "" {
+		errors = append(errors, newError(braintree.CodeCreditCardExpirationDateIsRequired, "expiration_date", "Expiration date is required.", path...))
+	} else if _, _, ok := expiration(card); !ok {
+		errors = append(errors, newError(braintree.CodeCreditCardExpirationDateIsInvalid, "expiration_date", "Expiration date is invalid.", path...))
+	}
+	if card.CVV != "" {
+		want := 3
+		if cardType(card.Number) == "American Express" {
+			want = 4
+		}
+		if len(card.CVV) != want {
+			errors = append(errors, newError(braintree.CodeCreditCardCVVIsInvalid, "cvv", "CVV must be 4 digits for American Express and 3 digits for other card types.", path...))
+		}
+	}
+	return errors
+}
+
+// newCard builds the card as the gateway returns it, without the number and the cvv
+func (s *Server) newCard(in *braintree.CreditCard) *braintree.CreditCard {
+	now := s.now()
+	month, year, _ := expiration(in)
+	card := &braintree.CreditCard{
+		XMLName:                xml.Name{Local: "credit-card"},
+		Token:                  in.Token,
+		Bin:                    in.Number[:6],
+		Last4:                  in.Number[len(in.Number)-4:],
+		CardType:               cardType(in.Number),
+		CardholderName:         in.CardholderName,
+		ExpirationMonth:        month,
+		ExpirationYear:         year,
+		ExpirationDate:         month + "/" + year,
+		UniqueNumberIdentifier: "unique-" + in.Number[len(in.Number)-4:] + "-" + in.Number[:6],
+		ImageURL:               "https://assets.braintreegateway.com/payment_method_logo/" + strings.ToLower(strings.Replace(cardType(in.Number), " ", "_", -1)) + ".png",
+		CreatedAt:              &now,
+		UpdatedAt:              &now,
+	}
+	if card.Token == "" {
+		card.Token = s.newID()
+	}
+	if in.BillingAddress != nil {
+		address := *in.BillingAddress
+		address.XMLName = xml.Name{Local: "billing-address"}
+		card.BillingAddress = &address
+	}
+	expires, _ := time.Parse("01/2006", card.ExpirationDate)
+	card.Expired = !expires.AddDate(0, 1, 0).After(now)
+	return card
+}
+
+// resolveNonce returns the vaulted card token or the card number behind a nonce
+func (s *Server) resolveNonce(nonce string) (string, *braintree.CreditCard, bool) {
+	if token, ok := s.nonces[nonce]; ok {
+		return token, nil, true
+	}
+	if number, ok := fakeNonces[nonce]; ok {
+		return "", &braintree.CreditCard{Number: number, ExpirationDate: "12/" + strconv.Itoa(s.now().Year()+3)}, true
+	}
+	return "", nil, false
+}
+
+// vault stores the card for the customer, making it the default one when asked or when it is the first card
+func (s *Server) vault(customerID string, card *braintree.CreditCard, makeDefault bool) {
+	card.CustomerId = customerID
+	if makeDefault || len(s.customerCards(customerID)) == 0 {
+		for _, other := range s.customerCards(customerID) {
+			other.Default = false
+		}
+		card.Default = true
+	}
+	if _, ok := s.cards[card.Token]; !ok {
+		s.cardTokens = append(s.cardTokens, card.Token)
+	}
+	s.cards[card.Token] = card
+}
+
+func (s *Server) customerCards(customerID string) []*braintree.CreditCard {
+	var result []*braintree.CreditCard
+	for _, token := range s.cardTokens {
+		if card, ok := s.cards[token]; ok && card.CustomerId == customerID {
+			result = append(result, card)
+		}
+	}
+	return result
+}
+
+// customer returns the customer with its payment methods, as found by the gateway
+func (s *Server) customer(id string) *braintree.Customer {
+	stored, ok := s.customers[id]
+	if !ok {
+		return nil
+	}
+	customer := *stored
+	customer.CreditCards = &braintree.CreditCards{CreditCard: s.customerCards(id)}
+	return &customer
+}
+
+func (s *Server) serveCustomers(w http.ResponseWriter, req *request) {
+	switch {
+	case req.is(http.MethodPost, "customers"):
+		s.createCustomer(w, req)
+	case req.is(http.MethodPost, "customers", "advanced_search_ids"):
+		s.searchCustomerIDs(w, req)
+	case req.is(http.MethodPost, "customers", "advanced_search"):
+		s.searchCustomers(w, req)
+	case req.is(http.MethodGet, "customers", "*"):
+		customer := s.customer(req.segments[1])
+		if customer == nil {
+			notFound(w)
+			return
+		}
+		writeXML(w, http.StatusOK, customer)
+	case req.is(http.MethodPut, "customers", "*"):
+		s.updateCustomer(w, req)
+	case req.is(http.MethodDelete, "customers", "*"):
+		id := req.segments[1]
+		if _, ok := s.customers[id]; !ok {
+			notFound(w)
+			return
+		}
+		for _, card := range s.customerCards(id) {
+			delete(s.cards, card.Token)
+		}
+		delete(s.customers, id)
+		w.WriteHeader(http.StatusOK)
+	case len(req.segments) >= 3 && req.segments[2] == "addresses":
+		s.serveAddresses(w, req)
+	default:
+		notFound(w)
+	}
+}
+
+func (s *Server) createCustomer(w http.ResponseWriter, req *request) {
+	var in braintree.CustomerRequest
+	if !decode(w, req, &in) {
+		return
+	}
+	if in.ID == "" {
+		in.ID = s.newID()
+	}
+	var errors []fieldError
+	if _, ok := s.customers[in.ID]; ok {
+		errors = append(errors, newError(braintree.CodeCustomerIdIsInUse, "id", "Customer ID has already been taken.", "customer"))
+	}
+	if in.Email != "" && !strings.Contains(in.Email, "@") {
+		errors = append(errors, newError(braintree.CodeCustomerEmailIsInvalid, "email", "Email is an invalid format.", "customer"))
+	}
+	card, cardErrors := s.customerCard(&in)
+	errors = append(errors, cardErrors...)
+	if len(errors) > 0 {
+		writeErrors(w, "", errors, nil)
+		return
+	}
+	if card != nil && in.CreditCard != nil && in.CreditCard.Options != nil {
+		if !s.verifyOnVault(w, card, in.CreditCard.Options.VerifyCard, in.CreditCard.Options.VerificationMerchantAccountId) {
+			return
+		}
+	}
+
+	now := s.now()
+	s.customers[in.ID] = &braintree.Customer{
+		Id:           in.ID,
+		FirstName:    in.FirstName,
+		LastName:     in.LastName,
+		Company:      in.Company,
+		Email:        in.Email,
+		Phone:        in.Phone,
+		Fax:          in.Fax,
+		Website:      in.Website,
+		CustomFields: in.CustomFields,
+		CreatedAt:    &now,
+		UpdatedAt:    &now,
+	}
+	s.customerIDs = append(s.customerIDs, in.ID)
+	if card != nil {
+		s.vault(in.ID, card, true)
+	}
+	writeXML(w, http.StatusCreated, s.customer(in.ID))
+}
+
+// customerCard validates and builds the card sent along a customer, either in clear or as a nonce
+func (s *Server) customerCard(in *braintree.CustomerRequest) (*braintree.CreditCard, []fieldError) {
+	card := in.CreditCard
+	nonce := in.PaymentMethodNonce
+	if card != nil && card.PaymentMethodNonce != "" {
+		nonce = card.PaymentMethodNonce
+	}
+	if nonce != "" {
+		token, fromNonce, ok := s.resolveNonce(nonce)
+		if !ok || token != "" {
+			return nil, []fieldError{newError(braintree.CodeCreditCardPaymentMethodNonceUnknown, "payment_method_nonce", "Unknown or expired payment_method_nonce.", "customer")}
+		}
+		if card != nil {
+			fromNonce.CardholderName = card.CardholderName
+			fromNonce.Token = card.Token
+			fromNonce.BillingAddress = card.BillingAddress
+		}
+		card = fromNonce
+	}
+	if card == nil {
+		return nil, nil
+	}
+	if errors := validateCard(card, "customer", "credit-card"); len(errors) > 0 {
+		return nil, errors
+	}
+	if card.Token != "" {
+		if _, ok := s.cards[card.Token]; ok {
+			return nil, []fieldError{newError(braintree.CodeCreditCardTokenIsInUse, "token", "Token is already in use.", "customer", "credit-card")}
+		}
+	}
+	return s.newCard(card), nil
+}
+
+func (s *Server) updateCustomer(w http.ResponseWriter, req *request) {
+	stored, ok := s.customers[req.segments[1]]
+	if !ok {
+		notFound(w)
+		return
+	}
+	var in braintree.CustomerRequest
+	if !decode(w, req, &in) {
+		return
+	}
+	if in.Email != "" && !strings.Contains(in.Email, "@") {
+		writeErrors(w, "", []fieldError{newError(braintree.CodeCustomerEmailIsInvalid, "email", "Email is an invalid format.", "customer")}, nil)
+		return
+	}
+
+	var card *braintree.CreditCard
+	if in.CreditCard != nil && in.CreditCard.Options != nil && in.CreditCard.Options.UpdateExistingToken != "" {
+		existing, ok := s.cards[in.CreditCard.Options.UpdateExistingToken]
+		if !ok || existing.CustomerId != stored.Id {
+			writeErrors(w, "", []fieldError{newError(braintree.CodeCreditCardUpdateExistingTokenIsInvalid, "update_existing_token", "Update Existing Token is invalid.", "customer", "credit-card", "options")}, nil)
+			return
+		}
+		if errors := updateCard(existing, in.CreditCard, "customer", "credit-card"); len(errors) > 0 {
+			writeErrors(w, "", errors, nil)
+			return
+		}
+	} else {
+		var errors []fieldError
+		card, errors = s.customerCard(&in)
+		if len(errors) > 0 {
+			writeErrors(w, "", errors, nil)
+			return
+		}
+	}
+
+	setString(&stored.FirstName, in.FirstName)
+	setString(&stored.LastName, in.LastName)
+	setString(&stored.Company, in.Company)
+	setString(&stored.Email, in.Email)
+	setString(&stored.Phone, in.Phone)
+	setString(&stored.Fax, in.Fax)
+	setString(&stored.Website, in.Website)
+	if len(in.CustomFields) > 0 {
+		stored.CustomFields = in.CustomFields
+	}
+	now := s.now()
+	stored.UpdatedAt = &now
+	if card != nil {
+		makeDefault := in.CreditCard != nil && in.CreditCard.Options != nil && in.CreditCard.Options.MakeDefault
+		s.vault(stored.Id, card, makeDefault)
+	}
+	writeXML(w, http.StatusOK, s.customer(stored.Id))
+}
+
+func setString(field *string, value string) {
+	if value != "" {
+		*field = value
+	}
+}
+
+// updateCard applies the changes to a vaulted card, a new number being validated as on creation
+func updateCard(card, in *braintree.CreditCard, path ...string) []fieldError {
+	if in.Number != "" {
+		probe := *in
+		if probe.ExpirationDate == "" && probe.ExpirationMonth == "" {
+			probe.ExpirationDate = card.ExpirationDate
+		}
+		if errors := validateCard(&probe, path...); len(errors) > 0 {
+			return errors
+		}
+		card.Bin = in.Number[:6]
+		card.Last4 = in.Number[len(in.Number)-4:]
+		card.CardType = cardType(in.Number)
+	}
+	if in.ExpirationDate != "" || in.ExpirationMonth != "" || in.ExpirationYear != "" {
+		month, year, ok := expiration(in)
+		if !ok {
+			return []fieldError{newError(braintree.CodeCreditCardExpirationDateIsInvalid, "expiration_date", "Expiration date is invalid.", path...)}
+		}
+		card.ExpirationMonth, card.ExpirationYear, card.ExpirationDate = month, year, month+"/"+year
+	}
+	setString(&card.CardholderName, in.CardholderName)
+	if in.BillingAddress != nil {
+		address := *in.BillingAddress
+		address.XMLName = xml.Name{Local: "billing-address"}
+		card.BillingAddress = &address
+	}
+	return nil
+}
+
+func (s *Server) serveAddresses(w http.ResponseWriter, req *request) {
+	customer, ok := s.customers[req.segments[1]]
+	if !ok {
+		notFound(w)
+		return
+	}
+	if customer.Addresses == nil {
+		customer.Addresses = &braintree.Addresses{}
+	}
+	find := func(id string) int {
+		for i, address := range customer.Addresses.Address {
+			if address.Id == id {
+				return i
+			}
+		}
+		return -1
+	}
+
+	switch {
+	case req.is(http.MethodPost, "customers", "*", "addresses"):
+		var in braintree.AddressRequest
+		if !decode(w, req, &in) {
+			return
+		}
+		if len(customer.Addresses.Address) >= 50 {
+			writeErrors(w, "", []fieldError{newError(braintree.CodeAddressTooManyAddressesPerCustomer, "base", "Customer has already reached the maximum of 50 addresses.", "address")}, nil)
+			return
+		}
+		now := s.now()
+		address := &braintree.Address{
+			XMLName:    xml.Name{Local: "address"},
+			Id:         s.newID(),
+			CustomerId: customer.Id,
+			CreatedAt:  &now,
+			UpdatedAt:  &now,
+		}
+		applyAddress(address, &in)
+		customer.Addresses.Address = append(customer.Addresses.Address, address)
+		writeXML(w, http.StatusCreated, address)
+	case req.is(http.MethodPut, "customers", "*", "addresses", "*"):
+		i := find(req.segments[3])
+		if i < 0 {
+			notFound(w)
+			return
+		}
+		var in braintree.AddressRequest
+		if !decode(w, req, &in) {
+			return
+		}
+		address := customer.Addresses.Address[i]
+		applyAddress(address, &in)
+		now := s.now()
+		address.UpdatedAt = &now
+		writeXML(w, http.StatusOK, address)
+	case req.is(http.MethodDelete, "customers", "*", "addresses", "*"):
+		i := find(req.segments[3])
+		if i < 0 {
+			notFound(w)
+			return
+		}
+		customer.Addresses.Address = append(customer.Addresses.Address[:i], customer.Addresses.Address[i+1:]...)
+		w.WriteHeader(http.StatusOK)
+	default:
+		notFound(w)
+	}
+}
+
+func applyAddress(address *braintree.Address, in *braintree.AddressRequest) {
+	setString(&address.FirstName, in.FirstName)
+	setString(&address.LastName, in.LastName)
+	setString(&address.Company, in.Company)
+	setString(&address.StreetAddress, in.StreetAddress)
+	setString(&address.ExtendedAddress, in.ExtendedAddress)
+	setString(&address.Locality, in.Locality)
+	setString(&address.Region, in.Region)
+	setString(&address.PostalCode, in.PostalCode)
+	setString(&address.CountryCodeAlpha2, in.CountryCodeAlpha2)
+	setString(&address.CountryCodeAlpha3, in.CountryCodeAlpha3)
+	setString(&address.CountryCodeNumeric, in.CountryCodeNumeric)
+	setString(&address.CountryName, in.CountryName)
+}
+
+func (s *Server) servePaymentMethods(w http.ResponseWriter, req *request) {
+	switch {
+	case req.is(http.MethodPost, "payment_methods"):
+		s.createPaymentMethod(w, req)
+	case req.is(http.MethodPost, "payment_methods", "all", "expiring_ids"):
+		s.writeSearchIDs(w, s.expiring(req))
+	case req.is(http.MethodPost, "payment_methods", "all", "expiring"):
+		s.fetchExpiring(w, req)
+	case req.is(http.MethodPost, "payment_methods", "*", "nonces"):
+		card, ok := s.cards[req.segments[1]]
+		if !ok {
+			notFound(w)
+			return
+		}
+		nonce := "fake-nonce-" + s.newID()
+		s.nonces[nonce] = card.Token
+		writeXML(w, http.StatusCreated, s.nonce(nonce, card))
+	case req.is(http.MethodGet, "payment_methods", "*"), req.is(http.MethodGet, "payment_methods", "any", "*"):
+		card, ok := s.cards[req.segments[len(req.segments)-1]]
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeXML(w, http.StatusOK, card)
+	case req.is(http.MethodPut, "payment_methods", "*"), req.is(http.MethodPut, "payment_methods", "any", "*"):
+		s.updatePaymentMethod(w, req)
+	case req.is(http.MethodDelete, "payment_methods", "*"), req.is(http.MethodDelete, "payment_methods", "any", "*"):
+		token := req.segments[len(req.segments)-1]
+		card, ok := s.cards[token]
+		if !ok {
+			notFound(w)
+			return
+		}
+		delete(s.cards, token)
+		if card.Default {
+			if cards := s.customerCards(card.CustomerId); len(cards) > 0 {
+				cards[0].Default = true
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	default:
+		notFound(w)
+	}
+}
+
+// createPaymentMethod handles both the v3 credit card creation and the v4 payment method (nonce) one
+func (s *Server) createPaymentMethod(w http.ResponseWriter, req *request) {
+	var root struct {
+		XMLName xml.Name
+	}
+	if err := xml.Unmarshal(req.body, &root); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var (
+		in                   braintree.CreditCard
+		makeDefault          bool
+		verifyCard           *bool
+		verificationMerchant string
+		object               = "credit-card"
+	)
+	switch root.XMLName.Local {
+	case "credit-card":
+		if !decode(w, req, &in) {
+			return
+		}
+		if in.Options != nil {
+			makeDefault, verifyCard, verificationMerchant = in.Options.MakeDefault, in.Options.VerifyCard, in.Options.VerificationMerchantAccountId
+		}
+	case "payment-method":
+		object = "payment-method"
+		var pm braintree.PaymentMethodRequest
+		if !decode(w, req, &pm) {
+			return
+		}
+		in.CustomerId, in.Token, in.PaymentMethodNonce = pm.CustomerId, pm.Token, pm.PaymentMethodNonce
+		if pm.Options != nil {
+			makeDefault, verifyCard, verificationMerchant = pm.Options.MakeDefault, pm.Options.VerifyCard, pm.Options.VerificationMerchantAccountId
+		}
+		if in.PaymentMethodNonce == "" {
+			writeErrors(w, "", []fieldError{newError(braintree.CodePaymentMethodNonceIsRequired, "payment_method_nonce", "Nonce is required.", "payment-method")}, nil)
+			return
+		}
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if in.PaymentMethodNonce != "" {
+		token, card, ok := s.resolveNonce(in.PaymentMethodNonce)
+		if !ok || token != "" {
+			writeErrors(w, "", []fieldError{newError(braintree.CodeCreditCardPaymentMethodNonceUnknown, "payment_method_nonce", "Unknown or expired payment_method_nonce.", object)}, nil)
+			return
+		}
+		card.Token, card.CardholderName, card.BillingAddress = in.Token, in.CardholderName, in.BillingAddress
+		card.CustomerId = in.CustomerId
+		in = *card
+	}
+
+	var errors []fieldError
+	if in.CustomerId == "" {
+		errors = append(errors, newError(braintree.CodeCreditCardCustomerIdIsRequired, "customer_id", "Customer ID is required.", object))
+	} else if _, ok := s.customers[in.CustomerId]; !ok {
+		errors = append(errors, newError(braintree.CodeCreditCardCustomerIdIsInvalid, "customer_id", "Customer ID is invalid.", object))
+	}
+	if in.Token != "" {
+		if _, ok := s.cards[in.Token]; ok {
+			errors = append(errors, newError(braintree.CodeCreditCardTokenIsInUse, "token", "Token is already in use.", object))
+		}
+	}
+	errors = append(errors, validateCard(&in, object)...)
+	if len(errors) > 0 {
+		writeErrors(w, "", errors, nil)
+		return
+	}
+
+	card := s.newCard(&in)
+	if !s.verifyOnVault(w, card, verifyCard, verificationMerchant) {
+		return
+	}
+	s.vault(in.CustomerId, card, makeDefault)
+	writeXML(w, http.StatusCreated, card)
+}
+
+func (s *Server) updatePaymentMethod(w http.ResponseWriter, req *request) {
+	card, ok := s.cards[req.segments[len(req.segments)-1]]
+	if !ok {
+		notFound(w)
+		return
+	}
+	var root struct {
+		XMLName xml.Name
+	}
+	if err := xml.Unmarshal(req.body, &root); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	makeDefault := false
+	switch root.XMLName.Local {
+	case "credit-card":
+		var in braintree.CreditCard
+		if !decode(w, req, &in) {
+			return
+		}
+		if errors := updateCard(card, &in, "credit-card"); len(errors) > 0 {
+			writeErrors(w, "", errors, nil)
+			return
+		}
+		makeDefault = in.Options != nil && in.Options.MakeDefault
+	case "payment-method":
+		var pm braintree.PaymentMethodRequest
+		if !decode(w, req, &pm) {
+			return
+		}
+		if pm.PaymentMethodNonce != "" {
+			_, in, ok := s.resolveNonce(pm.PaymentMethodNonce)
+			if !ok || in == nil {
+				writeErrors(w, "", []fieldError{newError(braintree.CodeCreditCardPaymentMethodNonceUnknown, "payment_method_nonce", "Unknown or expired payment_method_nonce.", "payment-method")}, nil)
+				return
+			}
+			_ = updateCard(card, in, "payment-method")
+		}
+		makeDefault = pm.Options != nil && pm.Options.MakeDefault
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	now := s.now()
+	card.UpdatedAt = &now
+	if makeDefault {
+		s.vault(card.CustomerId, card, true)
+	}
+	writeXML(w, http.StatusOK, card)
+}
+
+// expiring returns the tokens of the cards expiring between the start and end months (MMYYYY) of the query
+func (s *Server) expiring(req *request) []string {
+	parse := func(value string) int {
+		if len(value) != 6 {
+			return 0
+		}
+		month, _ := strconv.Atoi(value[:2])
+		year, _ := strconv.Atoi(value[2:])
+		return year*12 + month
+	}
+	start, end := parse(req.query.Get("start")), parse(req.query.Get("end"))
+	var tokens []string
+	for _, token := range s.cardTokens {
+		card, ok := s.cards[token]
+		if !ok {
+			continue
+		}
+		month, _ := strconv.Atoi(card.ExpirationMonth)
+		year, _ := strconv.Atoi(card.ExpirationYear)
+		if at := year*12 + month; at >= start && at <= end {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+func (s *Server) fetchExpiring(w http.ResponseWriter, req *request) {
+	query, ok := parseSearch(w, req)
+	if !ok {
+		return
+	}
+	result := struct {
+		XMLName     xml.Name                `xml:"payment-methods"`
+		CreditCards []*braintree.CreditCard `xml:"credit-card"`
+	}{}
+	for _, token := range s.expiring(req) {
+		if query.matchIDs(token) {
+			result.CreditCards = append(result.CreditCards, s.cards[token])
+		}
+	}
+	writeXML(w, http.StatusOK, &result)
+}
+
+type nonceResponse struct {
+	XMLName xml.Name `xml:"payment-method-nonce"`
+	*braintree.PaymentMethodNonce
+}
+
+func (s *Server) nonce(nonce string, card *braintree.CreditCard) *nonceResponse {
+	return &nonceResponse{PaymentMethodNonce: &braintree.PaymentMethodNonce{
+		Type:  "CreditCard",
+		Nonce: nonce,
+		Details: &braintree.PaymentMethodNonceDetails{
+			CardType: card.CardType,
+			Last2:    card.Last4[2:],
+		},
+	}}
+}
+
+func (s *Server) serveNonces(w http.ResponseWriter, req *request) {
+	if !req.is(http.MethodGet, "payment_method_nonces", "*") {
+		notFound(w)
+		return
+	}
+	nonce := req.segments[1]
+	token, in, ok := s.resolveNonce(nonce)
+	if !ok {
+		notFound(w)
+		return
+	}
+	card := s.cards[token]
+	if card == nil {
+		if in == nil {
+			notFound(w)
+			return
+		}
+		card = &braintree.CreditCard{CardType: cardType(in.Number), Last4: in.Number[len(in.Number)-4:]}
+	}
+	writeXML(w, http.StatusOK, s.nonce(nonce, card))
+}
@@ -0,0 +1,133 @@
+package fakegateway
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/badu/braintree"
+)
+
+// OpenDispute opens a chargeback on the transaction, as the processor would
+func (s *Server) OpenDispute(txID string, reason braintree.DisputeReason) (*braintree.Dispute, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tx, ok := s.transactions[txID]
+	if !ok {
+		return nil, false
+	}
+	dispute := *s.openDispute(tx, reason)
+	return &dispute, true
+}
+
+func (s *Server) openDispute(tx *braintree.Tx, reason braintree.DisputeReason) *braintree.Dispute {
+	now := s.now()
+	dispute := &braintree.Dispute{
+		ID:                s.newID(),
+		CaseNumber:        "CB" + strconv.Itoa(100000+s.seq),
+		CurrencyISOCode:   tx.CurrencyISOCode,
+		MerchantAccountID: tx.MerchantAccountId,
+		ReceivedDate:      now.Format(braintree.DateFormat),
+		ReplyByDate:       now.AddDate(0, 0, 7).Format(braintree.DateFormat),
+		Kind:              braintree.DisputeChargeback,
+		Reason:            reason,
+		AmountDisputed:    tx.Amount,
+		AmountWon:         braintree.NewDecimal(0, 2),
+		CreatedAt:         &now,
+		UpdatedAt:         &now,
+		Transaction: &braintree.DisputeTransaction{
+			ID:                  tx.Id,
+			OrderID:             tx.OrderId,
+			PurchaseOrderNumber: tx.PurchaseOrderNumber,
+			Amount:              tx.Amount,
+			CreatedAt:           tx.CreatedAt,
+		},
+	}
+	s.setDisputeStatus(dispute, braintree.DisputeStatusOpen)
+	s.disputes[dispute.ID] = dispute
+	tx.Disputes = append(tx.Disputes, dispute)
+	return dispute
+}
+
+func (s *Server) setDisputeStatus(dispute *braintree.Dispute, status braintree.DisputeStatus) {
+	now := s.now()
+	dispute.Status = status
+	dispute.UpdatedAt = &now
+	dispute.StatusHistory = append(dispute.StatusHistory, &braintree.DisputeStatusHistoryEvent{
+		EffectiveDate: now.Format(braintree.DateFormat),
+		Status:        string(status),
+		Timestamp:     &now,
+	})
+}
+
+func (s *Server) serveDisputes(w http.ResponseWriter, req *request) {
+	if len(req.segments) < 2 {
+		notFound(w)
+		return
+	}
+	dispute, ok := s.disputes[req.segments[1]]
+	if !ok {
+		notFound(w)
+		return
+	}
+	fail := func(code braintree.ErrorCode, attribute, message string) {
+		writeErrors(w, "", []fieldError{newError(code, attribute, message, "dispute")}, nil)
+	}
+
+	switch {
+	case req.is(http.MethodGet, "disputes", "*"):
+		writeXML(w, http.StatusOK, dispute)
+	case req.is(http.MethodPost, "disputes", "*", "evidence"):
+		var in braintree.DisputeTextEvidenceRequest
+		if !decode(w, req, &in) {
+			return
+		}
+		if dispute.Status != braintree.DisputeStatusOpen {
+			fail(braintree.CodeDisputeCanOnlyAddEvidenceToOpenDispute, "status", "Evidence can only be attached to disputes that are in an Open state")
+			return
+		}
+		if in.Content == "" {
+			fail(braintree.CodeDisputeEvidenceContentCannotBeBlank, "comments", "Content cannot be blank.")
+			return
+		}
+		now := s.now()
+		evidence := &braintree.DisputeEvidence{
+			ID:             s.newID(),
+			Comment:        in.Content,
+			Category:       in.Category,
+			SequenceNumber: in.SequenceNumber,
+			CreatedAt:      &now,
+		}
+		dispute.Evidence = append(dispute.Evidence, evidence)
+		dispute.UpdatedAt = &now
+		writeXML(w, http.StatusOK, evidence)
+	case req.is(http.MethodDelete, "disputes", "*", "evidence", "*"):
+		if dispute.Status != braintree.DisputeStatusOpen {
+			fail(braintree.CodeDisputeCanOnlyRemoveEvidenceFromOpenDispute, "status", "Evidence can only be removed from disputes that are in an Open state")
+			return
+		}
+		for i, evidence := range dispute.Evidence {
+			if evidence.ID == req.segments[3] {
+				dispute.Evidence = append(dispute.Evidence[:i], dispute.Evidence[i+1:]...)
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+		}
+		notFound(w)
+	case req.is(http.MethodPut, "disputes", "*", "accept"):
+		if dispute.Status != braintree.DisputeStatusOpen {
+			fail(braintree.CodeDisputeCanOnlyAcceptOpenDispute, "status", "Disputes can only be accepted when they are in an Open state")
+			return
+		}
+		s.setDisputeStatus(dispute, braintree.DisputeStatusAccepted)
+		w.WriteHeader(http.StatusOK)
+	case req.is(http.MethodPut, "disputes", "*", "finalize"):
+		if dispute.Status != braintree.DisputeStatusOpen {
+			fail(braintree.CodeDisputeCanOnlyFinalizeOpenDispute, "status", "Disputes can only be finalized when they are in an Open state")
+			return
+		}
+		s.setDisputeStatus(dispute, braintree.DisputeStatusDisputed)
+		w.WriteHeader(http.StatusOK)
+	default:
+		notFound(w)
+	}
+}
@@ -0,0 +1,425 @@
+package fakegateway
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/badu/braintree"
+)
+
+const searchTimeFormat = "2006-01-02T15:04:05Z"
+
+type searchNode struct {
+	XMLName  xml.Name
+	Type     string       `xml:"type,attr"`
+	Text     string       `xml:",chardata"`
+	Children []searchNode `xml:",any"`
+}
+
+// criterion is a search field : the operators of text, range and time fields, or the items of multi fields
+type criterion struct {
+	name      string
+	operators map[string]string
+	isTime    bool
+	multi     bool
+	items     []string
+}
+
+type query []criterion
+
+// parseSearch decodes the <search> document sent by the client, an empty body matching everything
+func parseSearch(w http.ResponseWriter, req *request) (query, bool) {
+	var root searchNode
+	if !decode(w, req, &root) {
+		return nil, false
+	}
+	var q query
+	for _, field := range root.Children {
+		c := criterion{name: field.XMLName.Local, operators: map[string]string{}}
+		if field.Type == "array" {
+			c.multi = true
+			for _, item := range field.Children {
+				c.items = append(c.items, strings.TrimSpace(item.Text))
+			}
+		}
+		if len(field.Children) == 0 && strings.TrimSpace(field.Text) != "" {
+			// key value fields, e.g. <refund>true</refund>
+			c.operators["is"] = strings.TrimSpace(field.Text)
+		}
+		for _, operator := range field.Children {
+			if c.multi {
+				break
+			}
+			c.operators[operator.XMLName.Local] = strings.TrimSpace(operator.Text)
+			if operator.Type == "datetime" {
+				c.isTime = true
+			}
+		}
+		q = append(q, c)
+	}
+	return q, true
+}
+
+// matchIDs only checks the ids criterion, if any
+func (q query) matchIDs(id string) bool {
+	for _, c := range q {
+		if c.name == "ids" {
+			return c.match([]string{id})
+		}
+	}
+	return true
+}
+
+// match checks every criterion against the values of the fields, ignoring the fields the entity doesn't have
+func (q query) match(fields map[string][]string) bool {
+	for _, c := range q {
+		values, ok := fields[c.name]
+		if !ok {
+			continue
+		}
+		if !c.match(values) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c criterion) match(values []string) bool {
+	if c.multi {
+		for _, item := range c.items {
+			for _, value := range values {
+				if strings.EqualFold(item, value) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	for _, value := range values {
+		if c.matchValue(value) {
+			return true
+		}
+	}
+	return len(values) == 0 && len(c.operators) == 0
+}
+
+func (c criterion) matchValue(value string) bool {
+	for operator, operand := range c.operators {
+		var ok bool
+		switch operator {
+		case "is":
+			ok = compare(value, operand, c.isTime) == 0
+		case "is-not":
+			ok = compare(value, operand, c.isTime) != 0
+		case "starts-with":
+			ok = strings.HasPrefix(strings.ToLower(value), strings.ToLower(operand))
+		case "ends-with":
+			ok = strings.HasSuffix(strings.ToLower(value), strings.ToLower(operand))
+		case "contains":
+			ok = strings.Contains(strings.ToLower(value), strings.ToLower(operand))
+		case "min":
+			ok = compare(value, operand, c.isTime) >= 0
+		case "max":
+			ok = compare(value, operand, c.isTime) <= 0
+		default:
+			ok = true
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// compare compares times, numbers or (case insensitive) texts
+func compare(value, operand string, isTime bool) int {
+	if isTime {
+		v, err1 := time.Parse(searchTimeFormat, value)
+		o, err2 := time.Parse(searchTimeFormat, operand)
+		if err1 == nil && err2 == nil {
+			switch {
+			case v.Before(o):
+				return -1
+			case v.After(o):
+				return 1
+			}
+			return 0
+		}
+	}
+	v, err1 := strconv.ParseFloat(value, 64)
+	o, err2 := strconv.ParseFloat(operand, 64)
+	if err1 == nil && err2 == nil {
+		switch {
+		case v < o:
+			return -1
+		case v > o:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(strings.ToLower(value), strings.ToLower(operand))
+}
+
+func formatTime(t *time.Time) []string {
+	if t == nil {
+		return nil
+	}
+	return []string{t.UTC().Format(searchTimeFormat)}
+}
+
+func formatDate(date string) []string {
+	t, err := time.Parse(braintree.DateFormat, date)
+	if err != nil {
+		return nil
+	}
+	return formatTime(&t)
+}
+
+func formatDecimal(d *braintree.Decimal) []string {
+	if d == nil {
+		return nil
+	}
+	return []string{d.String()}
+}
+
+func nonEmpty(values ...string) []string {
+	var result []string
+	for _, value := range values {
+		if value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func cardNumber(card *braintree.CreditCard) string {
+	if card == nil {
+		return ""
+	}
+	return card.Bin + "******" + card.Last4
+}
+
+func (s *Server) transactionFields(tx *braintree.Tx) map[string][]string {
+	fields := map[string][]string{
+		"id":                           {tx.Id},
+		"ids":                          {tx.Id},
+		"status":                       {string(tx.Status)},
+		"type":                         {string(tx.Type)},
+		"amount":                       formatDecimal(tx.Amount),
+		"created-at":                   formatTime(tx.CreatedAt),
+		"order-id":                     nonEmpty(tx.OrderId),
+		"payment-method-token":         nonEmpty(tx.PaymentMethodToken),
+		"merchant-account-id":          {tx.MerchantAccountId},
+		"currency":                     {tx.CurrencyISOCode},
+		"payment-instrument-type":      {string(tx.PaymentInstrumentType)},
+		"processor-authorization-code": nonEmpty(tx.ProcessorAuthorizationCode),
+		"settlement-batch-id":          nonEmpty(tx.SettlementBatchId),
+		"subscription-id":              nonEmpty(tx.SubscriptionId),
+		"plan-id":                      nonEmpty(tx.PlanId),
+		"refund":                       {strconv.FormatBool(tx.RefundedTransactionId != nil)},
+		"created-using":                {"full_information"},
+	}
+	if tx.PaymentMethodToken != "" {
+		fields["created-using"] = []string{"token"}
+	}
+	for status, at := range s.txEvents[tx.Id] {
+		at := at
+		fields[strings.Replace(string(status), "_", "-", -1)+"-at"] = formatTime(&at)
+	}
+	if card := tx.CreditCard; card != nil {
+		fields["credit-card-number"] = []string{cardNumber(card)}
+		fields["credit-card-cardholder-name"] = nonEmpty(card.CardholderName)
+		fields["credit-card-expiration-date"] = nonEmpty(card.ExpirationDate)
+		fields["credit-card-card-type"] = nonEmpty(card.CardType)
+		fields["credit-card-unique-identifier"] = nonEmpty(card.UniqueNumberIdentifier)
+	}
+	if customer := tx.Customer; customer != nil {
+		fields["customer-id"] = nonEmpty(customer.Id)
+		fields["customer-first-name"] = nonEmpty(customer.FirstName)
+		fields["customer-last-name"] = nonEmpty(customer.LastName)
+		fields["customer-company"] = nonEmpty(customer.Company)
+		fields["customer-email"] = nonEmpty(customer.Email)
+		fields["customer-phone"] = nonEmpty(customer.Phone)
+		fields["customer-fax"] = nonEmpty(customer.Fax)
+		fields["customer-website"] = nonEmpty(customer.Website)
+	}
+	for prefix, a := range map[string]*braintree.Address{"billing": tx.BillingAddress, "shipping": tx.ShippingAddress} {
+		if a == nil {
+			continue
+		}
+		fields[prefix+"-first-name"] = nonEmpty(a.FirstName)
+		fields[prefix+"-last-name"] = nonEmpty(a.LastName)
+		fields[prefix+"-company"] = nonEmpty(a.Company)
+		fields[prefix+"-street-address"] = nonEmpty(a.StreetAddress)
+		fields[prefix+"-extended-address"] = nonEmpty(a.ExtendedAddress)
+		fields[prefix+"-locality"] = nonEmpty(a.Locality)
+		fields[prefix+"-region"] = nonEmpty(a.Region)
+		fields[prefix+"-postal-code"] = nonEmpty(a.PostalCode)
+		fields[prefix+"-country-name"] = nonEmpty(a.CountryName)
+	}
+	if len(tx.Disputes) > 0 {
+		fields["dispute-date"] = formatDate(tx.Disputes[0].ReceivedDate)
+	}
+	return fields
+}
+
+func (s *Server) customerFields(customer *braintree.Customer) map[string][]string {
+	fields := map[string][]string{
+		"id":         {customer.Id},
+		"ids":        {customer.Id},
+		"first-name": nonEmpty(customer.FirstName),
+		"last-name":  nonEmpty(customer.LastName),
+		"company":    nonEmpty(customer.Company),
+		"email":      nonEmpty(customer.Email),
+		"phone":      nonEmpty(customer.Phone),
+		"fax":        nonEmpty(customer.Fax),
+		"website":    nonEmpty(customer.Website),
+		"created-at": formatTime(customer.CreatedAt),
+	}
+	for _, card := range s.customerCards(customer.Id) {
+		fields["payment-method-token"] = append(fields["payment-method-token"], card.Token)
+		fields["credit-card-number"] = append(fields["credit-card-number"], cardNumber(card))
+		fields["credit-card-expiration-date"] = append(fields["credit-card-expiration-date"], card.ExpirationDate)
+		fields["cardholder-name"] = append(fields["cardholder-name"], nonEmpty(card.CardholderName)...)
+	}
+	if customer.Addresses != nil {
+		for _, a := range customer.Addresses.Address {
+			fields["address-first-name"] = append(fields["address-first-name"], nonEmpty(a.FirstName)...)
+			fields["address-last-name"] = append(fields["address-last-name"], nonEmpty(a.LastName)...)
+			fields["address-street-address"] = append(fields["address-street-address"], nonEmpty(a.StreetAddress)...)
+			fields["address-locality"] = append(fields["address-locality"], nonEmpty(a.Locality)...)
+			fields["address-region"] = append(fields["address-region"], nonEmpty(a.Region)...)
+			fields["address-postal-code"] = append(fields["address-postal-code"], nonEmpty(a.PostalCode)...)
+			fields["address-country-name"] = append(fields["address-country-name"], nonEmpty(a.CountryName)...)
+		}
+	}
+	return fields
+}
+
+func (s *Server) subscriptionFields(sub *braintree.Subscription) map[string][]string {
+	fields := map[string][]string{
+		"id":                  {sub.Id},
+		"ids":                 {sub.Id},
+		"status":              {string(sub.Status)},
+		"plan-id":             {sub.PlanId},
+		"price":               formatDecimal(sub.Price),
+		"merchant-account-id": {sub.MerchantAccountId},
+		"in-trial-period":     {strconv.FormatBool(sub.TrialPeriod)},
+		"next-billing-date":   formatDate(sub.RawNextBillingDate()),
+		"created-at":          formatTime(sub.CreatedAt),
+	}
+	if sub.Status == braintree.SubscriptionStatusPastDue {
+		fields["days-past-due"] = []string{strconv.Itoa(sub.DaysPastDue)}
+	}
+	if sub.NumberOfBillingCycles != nil {
+		fields["billing-cycles-remaining"] = []string{strconv.Itoa(*sub.NumberOfBillingCycles - sub.CurrentBillingCycle)}
+	}
+	if sub.Transactions != nil {
+		for _, tx := range sub.Transactions.Transaction {
+			fields["transaction-id"] = append(fields["transaction-id"], tx.Id)
+		}
+	}
+	return fields
+}
+
+func (s *Server) matchingTransactions(q query) []*braintree.Tx {
+	var result []*braintree.Tx
+	for _, id := range s.txIDs {
+		if tx, ok := s.transactions[id]; ok && q.match(s.transactionFields(tx)) {
+			result = append(result, tx)
+		}
+	}
+	return result
+}
+
+func (s *Server) searchTransactionIDs(w http.ResponseWriter, req *request) {
+	q, ok := parseSearch(w, req)
+	if !ok {
+		return
+	}
+	var ids []string
+	for _, tx := range s.matchingTransactions(q) {
+		ids = append(ids, tx.Id)
+	}
+	s.writeSearchIDs(w, ids)
+}
+
+func (s *Server) searchTransactions(w http.ResponseWriter, req *request) {
+	q, ok := parseSearch(w, req)
+	if !ok {
+		return
+	}
+	writeXML(w, http.StatusOK, &struct {
+		XMLName      xml.Name        `xml:"credit-card-transactions"`
+		Transactions []*braintree.Tx `xml:"transaction"`
+	}{Transactions: s.matchingTransactions(q)})
+}
+
+func (s *Server) matchingCustomers(q query) []*braintree.Customer {
+	var result []*braintree.Customer
+	for _, id := range s.customerIDs {
+		if customer := s.customer(id); customer != nil && q.match(s.customerFields(customer)) {
+			result = append(result, customer)
+		}
+	}
+	return result
+}
+
+func (s *Server) searchCustomerIDs(w http.ResponseWriter, req *request) {
+	q, ok := parseSearch(w, req)
+	if !ok {
+		return
+	}
+	var ids []string
+	for _, customer := range s.matchingCustomers(q) {
+		ids = append(ids, customer.Id)
+	}
+	s.writeSearchIDs(w, ids)
+}
+
+func (s *Server) searchCustomers(w http.ResponseWriter, req *request) {
+	q, ok := parseSearch(w, req)
+	if !ok {
+		return
+	}
+	writeXML(w, http.StatusOK, &struct {
+		XMLName   xml.Name              `xml:"customers"`
+		Customers []*braintree.Customer `xml:"customer"`
+	}{Customers: s.matchingCustomers(q)})
+}
+
+func (s *Server) matchingSubscriptions(q query) []*braintree.Subscription {
+	var result []*braintree.Subscription
+	for _, id := range s.subscriptionIDs {
+		if sub, ok := s.subscriptions[id]; ok && q.match(s.subscriptionFields(sub)) {
+			result = append(result, sub)
+		}
+	}
+	return result
+}
+
+func (s *Server) searchSubscriptionIDs(w http.ResponseWriter, req *request) {
+	q, ok := parseSearch(w, req)
+	if !ok {
+		return
+	}
+	var ids []string
+	for _, sub := range s.matchingSubscriptions(q) {
+		ids = append(ids, sub.Id)
+	}
+	s.writeSearchIDs(w, ids)
+}
+
+func (s *Server) searchSubscriptions(w http.ResponseWriter, req *request) {
+	q, ok := parseSearch(w, req)
+	if !ok {
+		return
+	}
+	writeXML(w, http.StatusOK, &struct {
+		XMLName       xml.Name                  `xml:"subscriptions"`
+		Subscriptions []*braintree.Subscription `xml:"subscription"`
+	}{Subscriptions: s.matchingSubscriptions(q)})
+}
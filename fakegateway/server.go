@@ -0,0 +1,469 @@
+// Package fakegateway is an in-memory implementation of the Braintree XML gateway endpoints used by the
+// braintree APIClient, meant for local development and tests without network access :
+//
+//	gateway := fakegateway.New()
+//	srv := httptest.NewServer(gateway)
+//	defer srv.Close()
+//	client := braintree.New(srv.URL, "merchant", "public", "private")
+//
+// It mimics the sandbox : amounts between 2000.00 and 3000.99 are declined by the processor with the
+// matching response code, as are the authorizations adjusted to them, the card 4000111111111511 is rejected
+// by the fraud checks, the card 4000111111111115 fails the verifications, the card 4023898493988028 opens a
+// dispute and transactions can be settled with the Sandbox* client methods.
+package fakegateway
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/badu/braintree"
+)
+
+const (
+	DefaultMerchantAccountID = "fake_merchant_account"
+	DefaultCurrency          = "USD"
+	DefaultPageSize          = 50
+)
+
+// Server is the fake gateway, an http.Handler to be served with httptest.NewServer or http.ListenAndServe
+type Server struct {
+	// MerchantID, PublicKey and PrivateKey are checked against the requests when not empty
+	MerchantID string
+	PublicKey  string
+	PrivateKey string
+	// Now returns the current time, it can be replaced to control the timestamps
+	Now func() time.Time
+	// PageSize is the number of records per page of the searches, DefaultPageSize when not set
+	PageSize int
+	// SearchLimit truncates the ids found by the searches, as the gateway does past 50 000 results, when set
+	SearchLimit int
+
+	mu            sync.Mutex
+	seq           int
+	customers     map[string]*braintree.Customer
+	cards         map[string]*braintree.CreditCard
+	transactions  map[string]*braintree.Tx
+	txEvents      map[string]map[braintree.Status]time.Time
+	subscriptions map[string]*braintree.Subscription
+	disputes      map[string]*braintree.Dispute
+	verifications map[string]*braintree.CreditCardVerification
+	nonces        map[string]string // nonce => payment method token
+	lineItems     map[string][]*braintree.LineItem
+	plans         []*braintree.Plan
+	addOns        []braintree.AddOn
+	discounts     []braintree.Discount
+	// creation order, used by searches (deleted entries are skipped)
+	customerIDs     []string
+	cardTokens      []string
+	txIDs           []string
+	subscriptionIDs []string
+	verificationIDs []string
+}
+
+func New() *Server {
+	return &Server{
+		Now:           time.Now,
+		customers:     map[string]*braintree.Customer{},
+		cards:         map[string]*braintree.CreditCard{},
+		transactions:  map[string]*braintree.Tx{},
+		txEvents:      map[string]map[braintree.Status]time.Time{},
+		subscriptions: map[string]*braintree.Subscription{},
+		disputes:      map[string]*braintree.Dispute{},
+		verifications: map[string]*braintree.CreditCardVerification{},
+		nonces:        map[string]string{},
+		lineItems:     map[string][]*braintree.LineItem{},
+	}
+}
+
+// AddPlan registers a plan, which can be used for subscriptions
+func (s *Server) AddPlan(plan *braintree.Plan) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if plan.CurrencyISOCode == "" {
+		plan.CurrencyISOCode = DefaultCurrency
+	}
+	s.plans = append(s.plans, plan)
+}
+
+func (s *Server) AddAddOn(addOn braintree.AddOn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	addOn.Kind = braintree.ModificationKindAddOn
+	s.addOns = append(s.addOns, addOn)
+}
+
+func (s *Server) AddDiscount(discount braintree.Discount) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	discount.Kind = braintree.ModificationKindDiscount
+	s.discounts = append(s.discounts, discount)
+}
+
+// Transaction returns a copy of the stored transaction, for assertions
+func (s *Server) Transaction(id string) (braintree.Tx, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tx, ok := s.transactions[id]
+	if !ok {
+		return braintree.Tx{}, false
+	}
+	return *tx, true
+}
+
+type request struct {
+	method   string
+	segments []string
+	query    url.Values
+	body     []byte
+}
+
+func (r *request) is(method string, segments ...string) bool {
+	if r.method != method || len(r.segments) != len(segments) {
+		return false
+	}
+	for i, segment := range segments {
+		if segment != "*" && segment != r.segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var segments []string
+	for _, segment := range strings.Split(r.URL.Path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	if len(segments) < 3 || segments[0] != "merchants" || (s.MerchantID != "" && segments[1] != s.MerchantID) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if !s.authorized(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w.Header().Set(braintree.HdrRequestID, "req-"+s.newID())
+	req := &request{method: r.Method, segments: segments[2:], query: r.URL.Query(), body: body}
+	switch req.segments[0] {
+	case "customers":
+		s.serveCustomers(w, req)
+	case "payment_methods":
+		s.servePaymentMethods(w, req)
+	case "payment_method_nonces":
+		s.serveNonces(w, req)
+	case "transactions":
+		s.serveTransactions(w, req)
+	case "subscriptions":
+		s.serveSubscriptions(w, req)
+	case "plans":
+		s.servePlans(w, req)
+	case "add_ons":
+		s.serveAddOns(w, req)
+	case "discounts":
+		s.serveDiscounts(w, req)
+	case "disputes":
+		s.serveDisputes(w, req)
+	case "verifications":
+		s.serveVerifications(w, req)
+	case "client_token":
+		s.serveClientToken(w, req)
+	case "settlement_batch_summary":
+		s.serveSettlementBatchSummary(w, req)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if s.PublicKey == "" && s.PrivateKey == "" {
+		return true
+	}
+	expected := "Basic " + base64.StdEncoding.EncodeToString([]byte(s.PublicKey+":"+s.PrivateKey))
+	return r.Header.Get(braintree.HdrAuthorization) == expected
+}
+
+func (s *Server) now() time.Time {
+	if s.Now == nil {
+		return time.Now().UTC().Truncate(time.Second)
+	}
+	return s.Now().UTC().Truncate(time.Second)
+}
+
+// newID generates ids looking like the gateway ones : short, lowercase and alphanumeric
+func (s *Server) newID() string {
+	s.seq++
+	id := strconv.FormatInt(int64(s.seq)*7919+46656, 36)
+	for len(id) < 6 {
+		id = "0" + id
+	}
+	return id
+}
+
+func writeXML(w http.ResponseWriter, status int, v interface{}) {
+	body, err := xml.Marshal(v)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set(braintree.HdrContentType, braintree.HdrApplicationXML)
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(xml.Header))
+	_, _ = w.Write(body)
+}
+
+func notFound(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNotFound)
+}
+
+func decode(w http.ResponseWriter, req *request, v interface{}) bool {
+	if len(bytes.TrimSpace(req.body)) == 0 {
+		return true
+	}
+	if err := xml.Unmarshal(req.body, v); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// fieldError is a validation error on an attribute of the object found at path (e.g. transaction, credit-card)
+type fieldError struct {
+	path      []string
+	code      braintree.ErrorCode
+	attribute string
+	message   string
+}
+
+func newError(code braintree.ErrorCode, attribute, message string, path ...string) fieldError {
+	return fieldError{path: path, code: code, attribute: attribute, message: message}
+}
+
+type errorNode struct {
+	errors   []fieldError
+	names    []string
+	children map[string]*errorNode
+}
+
+func (n *errorNode) child(name string) *errorNode {
+	if n.children == nil {
+		n.children = map[string]*errorNode{}
+	}
+	c, ok := n.children[name]
+	if !ok {
+		c = &errorNode{}
+		n.children[name] = c
+		n.names = append(n.names, name)
+	}
+	return c
+}
+
+func (n *errorNode) write(b *bytes.Buffer) {
+	b.WriteString(`<errors type="array">`)
+	for _, e := range n.errors {
+		b.WriteString("<error><code>")
+		_ = xml.EscapeText(b, []byte(e.code))
+		b.WriteString(`</code><attribute type="symbol">`)
+		_ = xml.EscapeText(b, []byte(e.attribute))
+		b.WriteString("</attribute><message>")
+		_ = xml.EscapeText(b, []byte(e.message))
+		b.WriteString("</message></error>")
+	}
+	b.WriteString("</errors>")
+	for _, name := range n.names {
+		b.WriteString("<" + name + ">")
+		n.children[name].write(b)
+		b.WriteString("</" + name + ">")
+	}
+}
+
+// writeErrors writes an api-error-response with the validation errors and, optionally, the transaction or the
+// verification which failed
+func writeErrors(w http.ResponseWriter, message string, errors []fieldError, failed interface{}) {
+	root := &errorNode{}
+	var messages []string
+	for _, e := range errors {
+		n := root
+		for _, name := range e.path {
+			n = n.child(name)
+		}
+		n.errors = append(n.errors, e)
+		messages = append(messages, e.message)
+	}
+	if message == "" {
+		message = strings.Join(messages, "\n")
+	}
+
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString("<api-error-response><errors>")
+	root.write(&b)
+	b.WriteString("</errors><message>")
+	_ = xml.EscapeText(&b, []byte(message))
+	b.WriteString("</message>")
+	if failed != nil {
+		body, err := xml.Marshal(failed)
+		if err == nil {
+			b.Write(body)
+		}
+	}
+	b.WriteString("</api-error-response>")
+
+	w.Header().Set(braintree.HdrContentType, braintree.HdrApplicationXML)
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	_, _ = w.Write(b.Bytes())
+}
+
+func (s *Server) serveClientToken(w http.ResponseWriter, req *request) {
+	if !req.is(http.MethodPost, "client_token") {
+		notFound(w)
+		return
+	}
+	var token struct {
+		XMLName    xml.Name `xml:"client-token"`
+		CustomerID string   `xml:"customer-id"`
+		Version    int      `xml:"version"`
+	}
+	if !decode(w, req, &token) {
+		return
+	}
+	if token.CustomerID != "" {
+		if _, ok := s.customers[token.CustomerID]; !ok {
+			writeErrors(w, "", []fieldError{newError(braintree.CodeClientTokenCustomerDoesNotExist, "customer_id", "Customer specified by customer_id does not exist", "client-token")}, nil)
+			return
+		}
+	}
+	payload := fmt.Sprintf(`{"version":%d,"environment":"development","merchantId":%q,"customerId":%q,"issuedAt":%q}`,
+		token.Version, s.MerchantID, token.CustomerID, s.now().Format(time.RFC3339))
+	writeXML(w, http.StatusCreated, struct {
+		XMLName xml.Name `xml:"client-token"`
+		Value   string   `xml:"value"`
+	}{Value: base64.StdEncoding.EncodeToString([]byte(payload))})
+}
+
+func (s *Server) serveSettlementBatchSummary(w http.ResponseWriter, req *request) {
+	if !req.is(http.MethodPost, "settlement_batch_summary") {
+		notFound(w)
+		return
+	}
+	var summary struct {
+		XMLName xml.Name `xml:"settlement_batch_summary"`
+		Date    string   `xml:"settlement_date"`
+	}
+	if !decode(w, req, &summary) {
+		return
+	}
+	date, err := time.Parse(braintree.DateFormat, summary.Date)
+	if err != nil {
+		writeErrors(w, "", []fieldError{newError(braintree.CodeSettlementBatchSummarySettlementDateIsInvalid, "settlement_date", "Settlement Date is invalid", "settlement-batch-summary")}, nil)
+		return
+	}
+
+	type group struct {
+		count  int
+		amount *braintree.Decimal
+	}
+	groups := map[[3]string]*group{}
+	for id, tx := range s.transactions {
+		settledAt, ok := s.txEvents[id][braintree.StatusSettled]
+		if !ok || settledAt.Format(braintree.DateFormat) != date.Format(braintree.DateFormat) {
+			continue
+		}
+		cardType := ""
+		if tx.CreditCard != nil {
+			cardType = tx.CreditCard.CardType
+		}
+		key := [3]string{cardType, tx.MerchantAccountId, string(tx.Type)}
+		g, ok := groups[key]
+		if !ok {
+			g = &group{amount: braintree.NewDecimal(0, 2)}
+			groups[key] = g
+		}
+		g.count++
+		g.amount = addDecimals(g.amount, tx.Amount)
+	}
+
+	keys := make([][3]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return strings.Join(keys[i][:], "|") < strings.Join(keys[j][:], "|")
+	})
+	result := braintree.SettlementBatchSummary{}
+	for _, key := range keys {
+		result.Records.Type = append(result.Records.Type, braintree.Record{
+			CardType:          key[0],
+			MerchantAccountId: key[1],
+			Kind:              key[2],
+			Count:             groups[key].count,
+			AmountSettled:     groups[key].amount,
+		})
+	}
+	writeXML(w, http.StatusOK, &result)
+}
+
+type searchIDs struct {
+	XMLName  xml.Name `xml:"search-results"`
+	PageSize int      `xml:"page-size"`
+	IDs      struct {
+		Type  string   `xml:"type,attr"`
+		Items []string `xml:"item"`
+	} `xml:"ids"`
+}
+
+func (s *Server) writeSearchIDs(w http.ResponseWriter, ids []string) {
+	result := searchIDs{PageSize: s.PageSize}
+	if result.PageSize <= 0 {
+		result.PageSize = DefaultPageSize
+	}
+	if s.SearchLimit > 0 && len(ids) > s.SearchLimit {
+		ids = ids[:s.SearchLimit]
+	}
+	result.IDs.Type = "array"
+	result.IDs.Items = ids
+	writeXML(w, http.StatusOK, &result)
+}
+
+// scale2 returns the amount in cents, the gateway working with two decimals
+func scale2(d *braintree.Decimal) int64 {
+	if d == nil {
+		return 0
+	}
+	unscaled := d.Unscaled
+	for scale := d.Scale; scale < 2; scale++ {
+		unscaled *= 10
+	}
+	for scale := d.Scale; scale > 2; scale-- {
+		unscaled /= 10
+	}
+	return unscaled
+}
+
+func addDecimals(a, b *braintree.Decimal) *braintree.Decimal {
+	return braintree.NewDecimal(scale2(a)+scale2(b), 2)
+}
+
+func subDecimals(a, b *braintree.Decimal) *braintree.Decimal {
+	return braintree.NewDecimal(scale2(a)-scale2(b), 2)
+}
@@ -0,0 +1,390 @@
+package fakegateway
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/badu/braintree"
+)
+
+type subscriptionRequest struct {
+	XMLName               xml.Name                    `xml:"subscription"`
+	Id                    string                      `xml:"id"`
+	PaymentMethodToken    string                      `xml:"paymentMethodToken"`
+	PaymentMethodNonce    string                      `xml:"paymentMethodNonce"`
+	PlanId                string                      `xml:"planId"`
+	MerchantAccountId     string                      `xml:"merchant-account-id"`
+	FirstBillingDate      string                      `xml:"first-billing-date"`
+	TrialDuration         string                      `xml:"trial-duration"`
+	TrialDurationUnit     string                      `xml:"trial-duration-unit"`
+	Price                 *braintree.Decimal          `xml:"price"`
+	TrialPeriod           *bool                       `xml:"trial-period"`
+	NeverExpires          *bool                       `xml:"never-expires"`
+	NumberOfBillingCycles *int                        `xml:"number-of-billing-cycles"`
+	BillingDayOfMonth     *int                        `xml:"billing-day-of-month"`
+	Options               *braintree.SubscriptionOpts `xml:"options"`
+	Descriptor            *braintree.Descriptor       `xml:"descriptor"`
+}
+
+func (s *Server) plan(id string) *braintree.Plan {
+	for _, plan := range s.plans {
+		if plan.Id == id {
+			return plan
+		}
+	}
+	return nil
+}
+
+func (s *Server) servePlans(w http.ResponseWriter, req *request) {
+	if !req.is(http.MethodGet, "plans") {
+		notFound(w)
+		return
+	}
+	writeXML(w, http.StatusOK, &braintree.Plans{Plan: s.plans})
+}
+
+func (s *Server) serveAddOns(w http.ResponseWriter, req *request) {
+	if !req.is(http.MethodGet, "add_ons") {
+		notFound(w)
+		return
+	}
+	writeXML(w, http.StatusOK, &braintree.AddOnList{AddOns: s.addOns})
+}
+
+func (s *Server) serveDiscounts(w http.ResponseWriter, req *request) {
+	if !req.is(http.MethodGet, "discounts") {
+		notFound(w)
+		return
+	}
+	writeXML(w, http.StatusOK, &braintree.DiscountList{Discounts: s.discounts})
+}
+
+func (s *Server) serveSubscriptions(w http.ResponseWriter, req *request) {
+	switch {
+	case req.is(http.MethodPost, "subscriptions"):
+		s.createSubscription(w, req)
+	case req.is(http.MethodPost, "subscriptions", "advanced_search_ids"):
+		s.searchSubscriptionIDs(w, req)
+	case req.is(http.MethodPost, "subscriptions", "advanced_search"):
+		s.searchSubscriptions(w, req)
+	case req.is(http.MethodGet, "subscriptions", "*"):
+		sub, ok := s.subscriptions[req.segments[1]]
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeXML(w, http.StatusOK, sub)
+	case req.is(http.MethodPut, "subscriptions", "*"):
+		s.updateSubscription(w, req)
+	case req.is(http.MethodPut, "subscriptions", "*", "cancel"):
+		sub, ok := s.subscriptions[req.segments[1]]
+		if !ok {
+			notFound(w)
+			return
+		}
+		if sub.Status == braintree.SubscriptionStatusCanceled {
+			writeErrors(w, "", []fieldError{newError(braintree.CodeSubscriptionStatusIsCanceled, "status", "Subscription has already been canceled.", "subscription")}, nil)
+			return
+		}
+		s.setSubscriptionStatus(sub, braintree.SubscriptionStatusCanceled)
+		writeXML(w, http.StatusOK, sub)
+	default:
+		notFound(w)
+	}
+}
+
+func (s *Server) setSubscriptionStatus(sub *braintree.Subscription, status braintree.SubscriptionStatus) {
+	now := s.now()
+	sub.Status = status
+	sub.UpdatedAt = &now
+	sub.StatusEvents = append([]*braintree.SubscriptionStatusEvent{{
+		Timestamp:          now,
+		Status:             status,
+		CurrencyISOCode:    DefaultCurrency,
+		PlanID:             sub.PlanId,
+		SubscriptionSource: "api",
+		Balance:            sub.Balance,
+		Price:              sub.Price,
+	}}, sub.StatusEvents...)
+}
+
+// subscriptionToken resolves the payment method of the subscription, which has to be vaulted
+func (s *Server) subscriptionToken(in *subscriptionRequest) (string, []fieldError) {
+	token := in.PaymentMethodToken
+	if in.PaymentMethodNonce != "" {
+		vaulted, _, ok := s.resolveNonce(in.PaymentMethodNonce)
+		if !ok || vaulted == "" {
+			return "", []fieldError{newError(braintree.CodeSubscriptionPaymentMethodNonceIsInvalid, "payment_method_nonce", "Payment method nonce is invalid.", "subscription")}
+		}
+		token = vaulted
+	}
+	if _, ok := s.cards[token]; !ok {
+		return "", []fieldError{newError(braintree.CodeSubscriptionPaymentMethodTokenIsInvalid, "payment_method_token", "Payment method token is invalid.", "subscription")}
+	}
+	return token, nil
+}
+
+func (s *Server) createSubscription(w http.ResponseWriter, req *request) {
+	var in subscriptionRequest
+	if !decode(w, req, &in) {
+		return
+	}
+	var errors []fieldError
+	if in.Id == "" {
+		in.Id = s.newID()
+	} else if _, ok := s.subscriptions[in.Id]; ok {
+		errors = append(errors, newError(braintree.CodeSubscriptionIdIsInUse, "id", "ID has already been taken.", "subscription"))
+	}
+	plan := s.plan(in.PlanId)
+	if plan == nil {
+		errors = append(errors, newError(braintree.CodeSubscriptionPlanIdIsInvalid, "plan_id", "Plan ID is invalid.", "subscription"))
+	}
+	token, tokenErrors := s.subscriptionToken(&in)
+	errors = append(errors, tokenErrors...)
+	if in.Price != nil {
+		if amountErrors := validateAmount(in.Price); len(amountErrors) > 0 {
+			errors = append(errors, newError(braintree.CodeSubscriptionPriceIsInvalid, "price", "Price is an invalid format.", "subscription"))
+		}
+	}
+	var firstBilling time.Time
+	if in.FirstBillingDate != "" {
+		var err error
+		firstBilling, err = time.Parse(braintree.DateFormat, in.FirstBillingDate)
+		if err != nil {
+			errors = append(errors, newError(braintree.CodeSubscriptionFirstBillingDateIsInvalid, "first_billing_date", "First Billing Date format is invalid.", "subscription"))
+		} else if firstBilling.Before(s.today()) {
+			errors = append(errors, newError(braintree.CodeSubscriptionFirstBillingDateCannotBeInThePast, "first_billing_date", "First Billing Date cannot be in the past.", "subscription"))
+		}
+	}
+	if len(errors) > 0 {
+		writeErrors(w, "", errors, nil)
+		return
+	}
+
+	now := s.now()
+	sub := &braintree.Subscription{
+		Id:                    in.Id,
+		PlanId:                plan.Id,
+		PaymentMethodToken:    token,
+		MerchantAccountId:     in.MerchantAccountId,
+		Price:                 plan.Price,
+		NumberOfBillingCycles: plan.NumberOfBillingCycles,
+		TrialPeriod:           plan.TrialPeriod,
+		TrialDurationUnit:     plan.TrialDurationUnit,
+		Descriptor:            in.Descriptor,
+		Balance:               braintree.NewDecimal(0, 2),
+		AddOns:                &braintree.AddOnList{AddOns: plan.AddOns.AddOns},
+		Discounts:             &braintree.DiscountList{Discounts: plan.Discounts.Discounts},
+		Transactions:          &braintree.Transactions{},
+		CreatedAt:             &now,
+		UpdatedAt:             &now,
+	}
+	if sub.MerchantAccountId == "" {
+		sub.MerchantAccountId = DefaultMerchantAccountID
+	}
+	if plan.TrialDuration != nil {
+		sub.TrialDuration = *plan.TrialDuration
+	}
+	if in.Price != nil {
+		sub.Price = in.Price
+	}
+	if in.NumberOfBillingCycles != nil {
+		sub.NumberOfBillingCycles = in.NumberOfBillingCycles
+	}
+	if in.NeverExpires != nil && *in.NeverExpires {
+		sub.NumberOfBillingCycles = nil
+	}
+	sub.NeverExpires = sub.NumberOfBillingCycles == nil
+	if in.TrialPeriod != nil {
+		sub.TrialPeriod = *in.TrialPeriod
+		sub.TrialDuration, _ = strconv.Atoi(in.TrialDuration)
+		sub.TrialDurationUnit = in.TrialDurationUnit
+	}
+
+	start := s.today()
+	switch {
+	case sub.TrialPeriod:
+		if sub.TrialDurationUnit == braintree.Day {
+			start = start.AddDate(0, 0, sub.TrialDuration)
+		} else {
+			start = start.AddDate(0, sub.TrialDuration, 0)
+		}
+	case !firstBilling.IsZero():
+		start = firstBilling
+	case in.BillingDayOfMonth != nil && *in.BillingDayOfMonth != start.Day() && (in.Options == nil || !in.Options.StartImmediately):
+		for start.Day() != *in.BillingDayOfMonth {
+			start = start.AddDate(0, 0, 1)
+		}
+	}
+	sub.FirstBillingDate = &braintree.Date{Time: start}
+	sub.BillingDayOfMonth = start.Day()
+	sub.NextBillAmount, sub.NextBillingPeriodAmount = sub.Price, sub.Price
+
+	if sub.TrialPeriod || start.After(s.today()) {
+		sub.NextBillingDate = sub.FirstBillingDate
+		status := braintree.SubscriptionStatusPending
+		if sub.TrialPeriod {
+			status = braintree.SubscriptionStatusActive
+		}
+		s.setSubscriptionStatus(sub, status)
+	} else {
+		frequency := 1
+		if plan.BillingFrequency != nil {
+			frequency = *plan.BillingFrequency
+		}
+		end := start.AddDate(0, frequency, -1)
+		tx, ok := s.charge(w, sub, sub.Price, start, end)
+		if !ok {
+			return
+		}
+		sub.Transactions.Transaction = []*braintree.Tx{tx}
+		sub.CurrentBillingCycle = 1
+		sub.BillingPeriodStartDate = &braintree.Date{Time: start}
+		sub.BillingPeriodEndDate = &braintree.Date{Time: end}
+		sub.PaidThroughDate = sub.BillingPeriodEndDate
+		sub.NextBillingDate = &braintree.Date{Time: end.AddDate(0, 0, 1)}
+		s.setSubscriptionStatus(sub, braintree.SubscriptionStatusActive)
+	}
+
+	s.subscriptions[sub.Id] = sub
+	s.subscriptionIDs = append(s.subscriptionIDs, sub.Id)
+	writeXML(w, http.StatusCreated, sub)
+}
+
+func (s *Server) today() time.Time {
+	now := s.now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// charge bills the subscription with its vaulted card, writing the error response when it was declined
+func (s *Server) charge(w http.ResponseWriter, sub *braintree.Subscription, amount *braintree.Decimal, start, end time.Time) (*braintree.Tx, bool) {
+	card := s.cards[sub.PaymentMethodToken]
+	now := s.now()
+	tx := &braintree.Tx{
+		Id:                    s.newID(),
+		Type:                  braintree.TxTypeSale,
+		Amount:                amount,
+		MerchantAccountId:     sub.MerchantAccountId,
+		CurrencyISOCode:       DefaultCurrency,
+		PaymentInstrumentType: braintree.CreditCardType,
+		PaymentMethodToken:    sub.PaymentMethodToken,
+		PlanId:                sub.PlanId,
+		SubscriptionId:        sub.Id,
+		CreditCard:            txCard(card, true),
+		Customer:              txCustomer(s.customers[card.CustomerId]),
+		CVVResponseCode:       braintree.CVVResponseCodeNotProvided,
+		Descriptor:            sub.Descriptor,
+		SubscriptionDetails: &braintree.SubscriptionDetail{
+			BillingPeriodStartDate: &braintree.Date{Time: start},
+			BillingPeriodEndDate:   &braintree.Date{Time: end},
+		},
+		CreatedAt: &now,
+		UpdatedAt: &now,
+	}
+	s.store(tx, nil)
+	if !s.authorize(w, tx, true) {
+		return nil, false
+	}
+	return tx, true
+}
+
+func (s *Server) updateSubscription(w http.ResponseWriter, req *request) {
+	sub, ok := s.subscriptions[req.segments[1]]
+	if !ok {
+		notFound(w)
+		return
+	}
+	var in subscriptionRequest
+	if !decode(w, req, &in) {
+		return
+	}
+
+	var errors []fieldError
+	if sub.Status == braintree.SubscriptionStatusCanceled || sub.Status == braintree.SubscriptionStatusExpired {
+		errors = append(errors, newError(braintree.CodeSubscriptionCannotEditCanceled, "base", "Cannot edit a canceled subscription.", "subscription"))
+	}
+	if in.PlanId != "" && s.plan(in.PlanId) == nil {
+		errors = append(errors, newError(braintree.CodeSubscriptionPlanIdIsInvalid, "plan_id", "Plan ID is invalid.", "subscription"))
+	}
+	token := sub.PaymentMethodToken
+	if in.PaymentMethodToken != "" || in.PaymentMethodNonce != "" {
+		var tokenErrors []fieldError
+		token, tokenErrors = s.subscriptionToken(&in)
+		errors = append(errors, tokenErrors...)
+	}
+	if in.Price != nil && len(validateAmount(in.Price)) > 0 {
+		errors = append(errors, newError(braintree.CodeSubscriptionPriceIsInvalid, "price", "Price is an invalid format.", "subscription"))
+	}
+	if in.NumberOfBillingCycles != nil && *in.NumberOfBillingCycles < 1 {
+		errors = append(errors, newError(braintree.CodeSubscriptionNumberOfBillingCyclesMustBePositive, "number_of_billing_cycles", "Number Of Billing Cycles must be greater than zero.", "subscription"))
+	}
+	if len(errors) > 0 {
+		writeErrors(w, "", errors, nil)
+		return
+	}
+
+	if in.PlanId != "" {
+		sub.PlanId = in.PlanId
+		if in.Price == nil {
+			sub.Price = s.plan(in.PlanId).Price
+		}
+	}
+	if in.Price != nil {
+		sub.Price = in.Price
+	}
+	sub.NextBillAmount, sub.NextBillingPeriodAmount = sub.Price, sub.Price
+	sub.PaymentMethodToken = token
+	if in.MerchantAccountId != "" {
+		sub.MerchantAccountId = in.MerchantAccountId
+	}
+	if in.NumberOfBillingCycles != nil {
+		sub.NumberOfBillingCycles = in.NumberOfBillingCycles
+	}
+	if in.NeverExpires != nil && *in.NeverExpires {
+		sub.NumberOfBillingCycles = nil
+	}
+	sub.NeverExpires = sub.NumberOfBillingCycles == nil
+	if in.Descriptor != nil {
+		sub.Descriptor = in.Descriptor
+	}
+	now := s.now()
+	sub.UpdatedAt = &now
+	writeXML(w, http.StatusOK, sub)
+}
+
+// retryCharge charges the balance (or the given amount) of an active or past due subscription
+func (s *Server) retryCharge(w http.ResponseWriter, in *txRequest) {
+	sub, ok := s.subscriptions[in.SubscriptionID]
+	if !ok {
+		writeErrors(w, "", []fieldError{newError(braintree.CodeTransactionSubscriptionIdIsInvalid, "subscription_id", "Subscription ID is invalid.", "transaction")}, nil)
+		return
+	}
+	if sub.Status != braintree.SubscriptionStatusActive && sub.Status != braintree.SubscriptionStatusPastDue {
+		writeErrors(w, "", []fieldError{newError(braintree.CodeTransactionSubscriptionStatusMustBePastDue, "subscription_status", "Subscription status must be Past Due in order to retry.", "transaction")}, nil)
+		return
+	}
+	amount := in.Amount
+	if amount == nil {
+		amount = sub.Price
+		if scale2(sub.Balance) > 0 {
+			amount = sub.Balance
+		}
+	}
+	var start, end time.Time
+	if sub.BillingPeriodStartDate != nil && sub.BillingPeriodEndDate != nil {
+		start, end = sub.BillingPeriodStartDate.Time, sub.BillingPeriodEndDate.Time
+	}
+	tx, ok := s.charge(w, sub, amount, start, end)
+	if !ok {
+		return
+	}
+	sub.Transactions.Transaction = append([]*braintree.Tx{tx}, sub.Transactions.Transaction...)
+	if sub.Status == braintree.SubscriptionStatusPastDue {
+		sub.Balance = braintree.NewDecimal(0, 2)
+		sub.DaysPastDue = 0
+		s.setSubscriptionStatus(sub, braintree.SubscriptionStatusActive)
+	}
+	writeXML(w, http.StatusCreated, tx)
+}
@@ -0,0 +1,775 @@
+package fakegateway
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/badu/braintree"
+)
+
+const (
+	fraudCard   = "4000111511" // bin and last 4 of 4000111111111511
+	disputeCard = "4023898028" // bin and last 4 of 4023898493988028
+)
+
+type txRequest struct {
+	XMLName             xml.Name                   `xml:"transaction"`
+	Type                braintree.TxType           `xml:"type"`
+	Amount              *braintree.Decimal         `xml:"amount"`
+	TaxAmount           *braintree.Decimal         `xml:"tax-amount"`
+	OrderId             string                     `xml:"order-id"`
+	CustomerID          string                     `xml:"customer-id"`
+	PaymentMethodToken  string                     `xml:"payment-method-token"`
+	PaymentMethodNonce  string                     `xml:"payment-method-nonce"`
+	MerchantAccountId   string                     `xml:"merchant-account-id"`
+	SubscriptionID      string                     `xml:"subscription-id"`
+	Channel             string                     `xml:"channel"`
+	PurchaseOrderNumber string                     `xml:"purchase-order-number"`
+	TaxExempt           bool                       `xml:"tax-exempt"`
+	CustomFields        braintree.CustomFields     `xml:"custom-fields"`
+	CreditCard          *braintree.CreditCard      `xml:"credit-card"`
+	Customer            *braintree.CustomerRequest `xml:"customer"`
+	BillingAddress      *braintree.Address         `xml:"billing"`
+	ShippingAddress     *braintree.Address         `xml:"shipping"`
+	Descriptor          *braintree.Descriptor      `xml:"descriptor"`
+	LineItems           []*braintree.LineItem      `xml:"line-items>item"`
+	Options             struct {
+		SubmitForSettlement   bool `xml:"submit-for-settlement"`
+		StoreInVault          bool `xml:"store-in-vault"`
+		StoreInVaultOnSuccess bool `xml:"store-in-vault-on-success"`
+		HoldInEscrow          bool `xml:"hold-in-escrow"`
+	} `xml:"options"`
+}
+
+type lineItemsResponse struct {
+	XMLName   xml.Name              `xml:"line-items"`
+	Type      string                `xml:"type,attr"`
+	LineItems []*braintree.LineItem `xml:"line-item"`
+}
+
+func (s *Server) serveTransactions(w http.ResponseWriter, req *request) {
+	switch {
+	case req.is(http.MethodPost, "transactions"):
+		s.sale(w, req)
+	case req.is(http.MethodPost, "transactions", "advanced_search_ids"):
+		s.searchTransactionIDs(w, req)
+	case req.is(http.MethodPost, "transactions", "advanced_search"):
+		s.searchTransactions(w, req)
+	case req.is(http.MethodGet, "transactions", "*"):
+		tx, ok := s.transactions[req.segments[1]]
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeXML(w, http.StatusOK, tx)
+	case req.is(http.MethodGet, "transactions", "*", "line_items"):
+		if _, ok := s.transactions[req.segments[1]]; !ok {
+			notFound(w)
+			return
+		}
+		writeXML(w, http.StatusOK, &lineItemsResponse{Type: "array", LineItems: s.lineItems[req.segments[1]]})
+	case req.is(http.MethodPost, "transactions", "*", "refund"):
+		s.refund(w, req)
+	case req.is(http.MethodPost, "transactions", "*", "clone"):
+		s.clone(w, req)
+	case req.is(http.MethodPost, "transactions", "*", "submit_for_partial_settlement"):
+		s.submitForPartialSettlement(w, req)
+	case req.is(http.MethodPut, "transactions", "*", "*"):
+		tx, ok := s.transactions[req.segments[1]]
+		if !ok {
+			notFound(w)
+			return
+		}
+		s.transition(w, req, tx)
+	default:
+		notFound(w)
+	}
+}
+
+func validateDescriptor(descriptor *braintree.Descriptor) []fieldError {
+	if descriptor == nil {
+		return nil
+	}
+	var errors []fieldError
+	if !braintree.ValidDescriptorName(descriptor.Name) {
+		errors = append(errors, newError(braintree.CodeDescriptorNameFormatIsInvalid, "name", "Descriptor name format is invalid.", "transaction", "descriptor"))
+	}
+	if !braintree.ValidDescriptorPhone(descriptor.Phone) {
+		errors = append(errors, newError(braintree.CodeDescriptorPhoneFormatIsInvalid, "phone", "Descriptor phone format is invalid.", "transaction", "descriptor"))
+	}
+	if !braintree.ValidDescriptorURL(descriptor.URL) {
+		errors = append(errors, newError(braintree.CodeDescriptorURLFormatIsInvalid, "url", "Descriptor url format is invalid.", "transaction", "descriptor"))
+	}
+	return errors
+}
+
+func validateAmount(amount *braintree.Decimal, path ...string) []fieldError {
+	switch {
+	case amount == nil:
+		return []fieldError{newError(braintree.CodeTransactionAmountIsRequired, "amount", "Amount is required.", path...)}
+	case amount.Scale > 2:
+		return []fieldError{newError(braintree.CodeTransactionAmountIsInvalid, "amount", "Amount is an invalid format.", path...)}
+	case amount.Unscaled <= 0:
+		return []fieldError{newError(braintree.CodeTransactionAmountMustBeGreaterThanZero, "amount", "Amount must be greater than zero.", path...)}
+	}
+	return nil
+}
+
+// pan identifies the card by its bin and last 4, the number not being stored
+func pan(card *braintree.CreditCard) string {
+	if card == nil {
+		return ""
+	}
+	return card.Bin + card.Last4
+}
+
+// txCard is the card as it appears on a transaction
+func txCard(card *braintree.CreditCard, vaulted bool) *braintree.CreditCard {
+	result := *card
+	result.XMLName = xml.Name{Local: "credit-card"}
+	result.Default = false
+	result.Subscriptions = nil
+	result.BillingAddress = nil
+	result.CreatedAt, result.UpdatedAt = nil, nil
+	if !vaulted {
+		result.Token = ""
+		result.CustomerId = ""
+	}
+	return &result
+}
+
+// txCustomer is the customer as it appears on a transaction, without its payment methods
+func txCustomer(customer *braintree.Customer) *braintree.Customer {
+	result := *customer
+	result.CreditCards = nil
+	result.Addresses = nil
+	result.CreatedAt, result.UpdatedAt = nil, nil
+	return &result
+}
+
+func address(in *braintree.Address, name string) *braintree.Address {
+	if in == nil {
+		return nil
+	}
+	result := *in
+	result.XMLName = xml.Name{Local: name}
+	return &result
+}
+
+// paymentSource resolves the card to be charged, returning the vaulted one when there is one
+func (s *Server) paymentSource(in *txRequest) (*braintree.CreditCard, *braintree.CreditCard, []fieldError) {
+	switch {
+	case in.PaymentMethodToken != "":
+		card, ok := s.cards[in.PaymentMethodToken]
+		if !ok {
+			return nil, nil, []fieldError{newError(braintree.CodeTransactionPaymentMethodTokenIsInvalid, "payment_method_token", "Payment method token is invalid.", "transaction")}
+		}
+		return card, nil, nil
+	case in.PaymentMethodNonce != "":
+		token, card, ok := s.resolveNonce(in.PaymentMethodNonce)
+		if !ok {
+			return nil, nil, []fieldError{newError(braintree.CodeTransactionPaymentMethodNonceUnknown, "payment_method_nonce", "Unknown or expired payment_method_nonce.", "transaction")}
+		}
+		if token != "" {
+			return s.cards[token], nil, nil
+		}
+		return nil, card, nil
+	case in.CreditCard != nil:
+		if errors := validateCard(in.CreditCard, "transaction", "credit-card"); len(errors) > 0 {
+			return nil, nil, errors
+		}
+		return nil, in.CreditCard, nil
+	case in.CustomerID != "":
+		if _, ok := s.customers[in.CustomerID]; !ok {
+			return nil, nil, []fieldError{newError(braintree.CodeTransactionCustomerIdIsInvalid, "customer_id", "Customer ID is invalid.", "transaction")}
+		}
+		for _, card := range s.customerCards(in.CustomerID) {
+			if card.Default {
+				return card, nil, nil
+			}
+		}
+	}
+	return nil, nil, []fieldError{newError(braintree.CodeTransactionCreditCardIsRequired, "base", "Cannot determine payment method.", "transaction")}
+}
+
+func (s *Server) sale(w http.ResponseWriter, req *request) {
+	var in txRequest
+	if !decode(w, req, &in) {
+		return
+	}
+	if in.SubscriptionID != "" {
+		s.retryCharge(w, &in)
+		return
+	}
+
+	var errors []fieldError
+	switch in.Type {
+	case braintree.TxTypeSale, braintree.TxTypeCredit:
+	case "":
+		errors = append(errors, newError(braintree.CodeTransactionTypeIsRequired, "type", "Transaction type is required.", "transaction"))
+	default:
+		errors = append(errors, newError(braintree.CodeTransactionTypeIsInvalid, "type", "Transaction type is invalid.", "transaction"))
+	}
+	errors = append(errors, validateAmount(in.Amount, "transaction")...)
+	vaulted, card, sourceErrors := s.paymentSource(&in)
+	errors = append(errors, sourceErrors...)
+	if len(errors) > 0 {
+		writeErrors(w, "", errors, nil)
+		return
+	}
+
+	now := s.now()
+	tx := &braintree.Tx{
+		Id:                    s.newID(),
+		Type:                  in.Type,
+		Amount:                in.Amount,
+		TaxAmount:             in.TaxAmount,
+		TaxExempt:             in.TaxExempt,
+		OrderId:               in.OrderId,
+		Channel:               in.Channel,
+		PurchaseOrderNumber:   in.PurchaseOrderNumber,
+		CustomFields:          in.CustomFields,
+		MerchantAccountId:     in.MerchantAccountId,
+		CurrencyISOCode:       DefaultCurrency,
+		PaymentInstrumentType: braintree.CreditCardType,
+		BillingAddress:        address(in.BillingAddress, "billing"),
+		ShippingAddress:       address(in.ShippingAddress, "shipping"),
+		Descriptor:            in.Descriptor,
+		CreatedAt:             &now,
+		UpdatedAt:             &now,
+	}
+	if tx.MerchantAccountId == "" {
+		tx.MerchantAccountId = DefaultMerchantAccountID
+	}
+
+	customerID := in.CustomerID
+	if vaulted != nil {
+		customerID = vaulted.CustomerId
+	}
+	if customerID != "" {
+		tx.Customer = txCustomer(s.customers[customerID])
+	} else if in.Customer != nil {
+		tx.Customer = &braintree.Customer{
+			Id:        in.Customer.ID,
+			FirstName: in.Customer.FirstName,
+			LastName:  in.Customer.LastName,
+			Company:   in.Customer.Company,
+			Email:     in.Customer.Email,
+			Phone:     in.Customer.Phone,
+			Fax:       in.Customer.Fax,
+			Website:   in.Customer.Website,
+		}
+	}
+
+	if vaulted != nil {
+		tx.CreditCard = txCard(vaulted, true)
+		tx.PaymentMethodToken = vaulted.Token
+	} else {
+		tx.CreditCard = txCard(s.newCard(card), false)
+	}
+	if card != nil && card.CVV != "" {
+		tx.CVVResponseCode = braintree.CVVResponseCodeMatches
+	} else {
+		tx.CVVResponseCode = braintree.CVVResponseCodeNotProvided
+	}
+
+	s.store(tx, in.LineItems)
+	if !s.authorize(w, tx, in.Options.SubmitForSettlement || in.Type == braintree.TxTypeCredit) {
+		return
+	}
+
+	if vaulted == nil && (in.Options.StoreInVault || in.Options.StoreInVaultOnSuccess) {
+		if customerID == "" {
+			customerID = s.vaultCustomer(tx.Customer)
+		}
+		stored := s.newCard(card)
+		s.vault(customerID, stored, false)
+		tx.CreditCard = txCard(stored, true)
+		tx.PaymentMethodToken = stored.Token
+		tx.Customer = txCustomer(s.customers[customerID])
+	}
+	if in.Options.HoldInEscrow {
+		tx.EscrowStatus = braintree.EscrowHoldPending
+	}
+	if pan(tx.CreditCard) == disputeCard {
+		s.openDispute(tx, braintree.FraudDisputeReason)
+	}
+	writeXML(w, http.StatusCreated, tx)
+}
+
+// vaultCustomer stores the customer sent along a transaction, returning its id
+func (s *Server) vaultCustomer(details *braintree.Customer) string {
+	now := s.now()
+	customer := &braintree.Customer{CreatedAt: &now, UpdatedAt: &now}
+	if details != nil {
+		customer = txCustomer(details)
+		customer.CreatedAt, customer.UpdatedAt = &now, &now
+	}
+	if customer.Id == "" {
+		customer.Id = s.newID()
+	}
+	if _, ok := s.customers[customer.Id]; !ok {
+		s.customerIDs = append(s.customerIDs, customer.Id)
+	}
+	s.customers[customer.Id] = customer
+	return customer.Id
+}
+
+func (s *Server) store(tx *braintree.Tx, lineItems []*braintree.LineItem) {
+	s.transactions[tx.Id] = tx
+	s.txIDs = append(s.txIDs, tx.Id)
+	s.lineItems[tx.Id] = lineItems
+}
+
+func (s *Server) setStatus(tx *braintree.Tx, status braintree.Status) {
+	now := s.now()
+	tx.Status = status
+	tx.UpdatedAt = &now
+	if s.txEvents[tx.Id] == nil {
+		s.txEvents[tx.Id] = map[braintree.Status]time.Time{}
+	}
+	s.txEvents[tx.Id][status] = now
+}
+
+// authorize runs the stored transaction through the fraud checks and the processor, writing the error
+// response and returning false when it was not authorized
+func (s *Server) authorize(w http.ResponseWriter, tx *braintree.Tx, submit bool) bool {
+	if pan(tx.CreditCard) == fraudCard {
+		s.setStatus(tx, braintree.StatusGatewayRejected)
+		tx.GatewayRejectionReason = braintree.FraudReason
+		writeErrors(w, "Gateway Rejected: "+string(braintree.FraudReason), nil, tx)
+		return false
+	}
+
+	cents := scale2(tx.Amount)
+	if tx.Type == braintree.TxTypeSale && cents >= 200000 && cents < 300100 {
+		code := braintree.ResponseCode(cents / 100)
+		text := code.Text()
+		if text == "" {
+			text = "Processor Declined"
+		}
+		s.setStatus(tx, braintree.StatusProcessorDeclined)
+		tx.ProcessorResponseCode = code
+		tx.ProcessorResponseText = text
+		tx.ProcessorResponseType = code.Type()
+		writeErrors(w, text, nil, tx)
+		return false
+	}
+
+	s.setStatus(tx, braintree.StatusAuthorized)
+	expires := s.now().AddDate(0, 0, 7)
+	tx.AuthorizationExpiresAt = &expires
+	tx.ProcessorResponseCode = 1000
+	tx.ProcessorResponseText = tx.ProcessorResponseCode.Text()
+	tx.ProcessorResponseType = braintree.ResponseTypeApproved
+	tx.ProcessorAuthorizationCode = strings.ToUpper(s.newID())
+	tx.AVSPostalCodeResponseCode = braintree.AVSResponseCodeMatches
+	tx.AVSStreetAddressResponseCode = braintree.AVSResponseCodeMatches
+	if submit {
+		s.setStatus(tx, braintree.StatusSubmittedForSettlement)
+	}
+	return true
+}
+
+// adjustAuthorization records the adjustment of the authorization to the amount, declining the increases to
+// the amounts declined on sales, writing the error response and returning false when it was declined
+func (s *Server) adjustAuthorization(w http.ResponseWriter, tx *braintree.Tx, amount *braintree.Decimal) bool {
+	now := s.now()
+	adjustment := &braintree.AuthorizationAdjustment{Amount: amount, Timestamp: &now}
+	tx.AuthorizationAdjustments = append(tx.AuthorizationAdjustments, adjustment)
+	tx.UpdatedAt = &now
+
+	cents := scale2(amount)
+	if cents > scale2(tx.Amount) && cents >= 200000 && cents < 300100 {
+		code := braintree.ResponseCode(cents / 100)
+		adjustment.ProcessorResponseCode = code
+		adjustment.ProcessorResponseText = code.Text()
+		if adjustment.ProcessorResponseText == "" {
+			adjustment.ProcessorResponseText = "Processor Declined"
+		}
+		adjustment.ProcessorResponseType = code.Type()
+		writeErrors(w, adjustment.ProcessorResponseText, nil, tx)
+		return false
+	}
+	adjustment.Success = true
+	adjustment.ProcessorResponseCode = 1000
+	adjustment.ProcessorResponseText = adjustment.ProcessorResponseCode.Text()
+	adjustment.ProcessorResponseType = braintree.ResponseTypeApproved
+	tx.Amount = amount
+	return true
+}
+
+func (s *Server) transition(w http.ResponseWriter, req *request, tx *braintree.Tx) {
+	fail := func(code braintree.ErrorCode, attribute, message string) {
+		writeErrors(w, "", []fieldError{newError(code, attribute, message, "transaction")}, nil)
+	}
+
+	switch req.segments[2] {
+	case "submit_for_settlement":
+		var in txRequest
+		if !decode(w, req, &in) {
+			return
+		}
+		if tx.Status != braintree.StatusAuthorized {
+			fail(braintree.CodeTransactionCannotSubmitForSettlement, "base", "Cannot submit for settlement unless status is authorized.")
+			return
+		}
+		if in.Amount != nil {
+			if errors := validateAmount(in.Amount, "transaction"); len(errors) > 0 {
+				writeErrors(w, "", errors, nil)
+				return
+			}
+			if scale2(in.Amount) > scale2(tx.Amount) {
+				fail(braintree.CodeTransactionSettlementAmountIsTooLarge, "amount", "Settlement amount is too large.")
+				return
+			}
+			tx.Amount = in.Amount
+		}
+		s.setStatus(tx, braintree.StatusSubmittedForSettlement)
+	case "update_details":
+		var in txRequest
+		if !decode(w, req, &in) {
+			return
+		}
+		if tx.Status != braintree.StatusSubmittedForSettlement {
+			fail(braintree.CodeTransactionCannotUpdateDetailsUnlessSubmitted, "base", "Transaction details can only be updated while submitted for settlement.")
+			return
+		}
+		if in.Amount != nil {
+			if errors := validateAmount(in.Amount, "transaction"); len(errors) > 0 {
+				writeErrors(w, "", errors, nil)
+				return
+			}
+			if scale2(in.Amount) > scale2(tx.Amount) {
+				fail(braintree.CodeTransactionSettlementAmountIsTooLarge, "amount", "Settlement amount is too large.")
+				return
+			}
+		}
+		if errors := validateDescriptor(in.Descriptor); len(errors) > 0 {
+			writeErrors(w, "", errors, nil)
+			return
+		}
+		if in.Amount != nil {
+			tx.Amount = in.Amount
+		}
+		if in.OrderId != "" {
+			tx.OrderId = in.OrderId
+		}
+		if in.Descriptor != nil {
+			tx.Descriptor = in.Descriptor
+		}
+		now := s.now()
+		tx.UpdatedAt = &now
+	case "adjust_authorization":
+		var in txRequest
+		if !decode(w, req, &in) {
+			return
+		}
+		if tx.Status != braintree.StatusAuthorized {
+			fail(braintree.CodeTransactionMustBeInStateAuthorized, "base", "Transaction must be in state authorized.")
+			return
+		}
+		if in.Amount != nil && in.Amount.Sign() == 0 {
+			fail(braintree.CodeTransactionAdjustmentAmountMustBeGreaterThanZero, "amount", "Adjustment amount must be greater than zero.")
+			return
+		}
+		if errors := validateAmount(in.Amount, "transaction"); len(errors) > 0 {
+			writeErrors(w, "", errors, nil)
+			return
+		}
+		cents := scale2(in.Amount)
+		switch {
+		case cents == scale2(tx.Amount):
+			fail(braintree.CodeTransactionNoNetAmountToPerformAuthAdjustment, "amount", "There is no net amount to perform an authorization adjustment.")
+			return
+		case cents < s.partiallySettled(tx):
+			fail(braintree.CodeTransactionSettlementAmountIsTooLarge, "amount", "Settlement amount is too large.")
+			return
+		}
+		if !s.adjustAuthorization(w, tx, in.Amount) {
+			return
+		}
+	case "void":
+		if tx.Status != braintree.StatusAuthorized && tx.Status != braintree.StatusSubmittedForSettlement {
+			fail(braintree.CodeTransactionCannotBeVoided, "base", "Transaction can only be voided if status is authorized or submitted_for_settlement.")
+			return
+		}
+		s.setStatus(tx, braintree.StatusVoided)
+	case "hold_in_escrow":
+		switch {
+		case tx.EscrowStatus != "":
+			fail(braintree.CodeTransactionCannotHoldInEscrow, "base", "Transaction could not be held in escrow.")
+			return
+		case tx.Status == braintree.StatusAuthorized || tx.Status == braintree.StatusSubmittedForSettlement:
+			tx.EscrowStatus = braintree.EscrowHoldPending
+		case tx.Status == braintree.StatusSettled:
+			tx.EscrowStatus = braintree.EscrowHeld
+		default:
+			fail(braintree.CodeTransactionCannotHoldInEscrow, "base", "Transaction could not be held in escrow.")
+			return
+		}
+	case "release_from_escrow":
+		if tx.EscrowStatus != braintree.EscrowHeld {
+			fail(braintree.CodeTransactionCannotReleaseFromEscrow, "base", "Cannot release a transaction that is not escrowed.")
+			return
+		}
+		tx.EscrowStatus = braintree.EscrowReleasePending
+	case "cancel_release":
+		if tx.EscrowStatus != braintree.EscrowReleasePending {
+			fail(braintree.CodeTransactionCannotCancelRelease, "base", "Release can only be cancelled if the transaction is submitted for release.")
+			return
+		}
+		tx.EscrowStatus = braintree.EscrowHeld
+	case "settle", "settlement_confirm", "settlement_decline", string(braintree.StatusSettlementPending):
+		switch tx.Status {
+		case braintree.StatusSubmittedForSettlement, braintree.StatusSettling, braintree.StatusSettlementPending, braintree.StatusSettled:
+		default:
+			fail(braintree.CodeTransactionCannotSettleUnlessSubmitted, "base", "Cannot settle a transaction unless it is submitted for settlement.")
+			return
+		}
+		status := map[string]braintree.Status{
+			"settle":             braintree.StatusSettled,
+			"settlement_confirm": braintree.StatusSettlementConfirmed,
+			"settlement_decline": braintree.StatusSettlementDeclined,
+			"settlement_pending": braintree.StatusSettlementPending,
+		}[req.segments[2]]
+		s.setStatus(tx, status)
+		if status == braintree.StatusSettled {
+			tx.SettlementBatchId = s.now().Format(braintree.DateFormat) + "_" + tx.MerchantAccountId
+			if tx.EscrowStatus == braintree.EscrowHoldPending {
+				tx.EscrowStatus = braintree.EscrowHeld
+			}
+		}
+	default:
+		notFound(w)
+		return
+	}
+	writeXML(w, http.StatusOK, tx)
+}
+
+// refunded returns the amount already refunded, voided refunds excluded
+func (s *Server) refunded(tx *braintree.Tx) int64 {
+	var total int64
+	if tx.RefundIds == nil {
+		return 0
+	}
+	for _, id := range *tx.RefundIds {
+		if refund, ok := s.transactions[id]; ok && refund.Status != braintree.StatusVoided {
+			total += scale2(refund.Amount)
+		}
+	}
+	return total
+}
+
+func (s *Server) refund(w http.ResponseWriter, req *request) {
+	tx, ok := s.transactions[req.segments[1]]
+	if !ok {
+		notFound(w)
+		return
+	}
+	var in txRequest
+	if !decode(w, req, &in) {
+		return
+	}
+	fail := func(code braintree.ErrorCode, attribute, message string) {
+		writeErrors(w, "", []fieldError{newError(code, attribute, message, "transaction")}, nil)
+	}
+	switch {
+	case tx.Type == braintree.TxTypeCredit:
+		fail(braintree.CodeTransactionCannotRefundCredit, "base", "Cannot refund credit")
+		return
+	case tx.Status != braintree.StatusSettled && tx.Status != braintree.StatusSettling:
+		fail(braintree.CodeTransactionCannotRefundUnlessSettled, "base", "Cannot refund transaction unless it is settled.")
+		return
+	case tx.EscrowStatus == braintree.EscrowHeld || tx.EscrowStatus == braintree.EscrowReleasePending:
+		fail(braintree.CodeTransactionCannotRefundHeldInEscrow, "base", "Cannot refund a transaction that is held in escrow.")
+		return
+	}
+	remaining := scale2(tx.Amount) - s.refunded(tx)
+	if remaining <= 0 {
+		fail(braintree.CodeTransactionHasAlreadyBeenRefunded, "base", "Transaction has already been fully refunded.")
+		return
+	}
+	amount := braintree.NewDecimal(remaining, 2)
+	if in.Amount != nil {
+		if errors := validateAmount(in.Amount, "transaction"); len(errors) > 0 {
+			writeErrors(w, "", errors, nil)
+			return
+		}
+		if scale2(in.Amount) > remaining {
+			fail(braintree.CodeTransactionRefundAmountIsTooLarge, "amount", "Refund amount is too large.")
+			return
+		}
+		amount = in.Amount
+	}
+
+	now := s.now()
+	parentID := tx.Id
+	refund := &braintree.Tx{
+		Id:                    s.newID(),
+		Type:                  braintree.TxTypeCredit,
+		Amount:                amount,
+		OrderId:               in.OrderId,
+		MerchantAccountId:     tx.MerchantAccountId,
+		CurrencyISOCode:       tx.CurrencyISOCode,
+		PaymentInstrumentType: tx.PaymentInstrumentType,
+		PaymentMethodToken:    tx.PaymentMethodToken,
+		CreditCard:            tx.CreditCard,
+		Customer:              tx.Customer,
+		BillingAddress:        tx.BillingAddress,
+		RefundedTransactionId: &parentID,
+		CreatedAt:             &now,
+		UpdatedAt:             &now,
+	}
+	if refund.OrderId == "" {
+		refund.OrderId = tx.OrderId
+	}
+	s.store(refund, nil)
+	s.setStatus(refund, braintree.StatusSubmittedForSettlement)
+
+	refundIDs := []string{}
+	if tx.RefundIds != nil {
+		refundIDs = append(refundIDs, *tx.RefundIds...)
+	}
+	refundIDs = append(refundIDs, refund.Id)
+	tx.RefundIds = &refundIDs
+	tx.RefundId = refund.Id
+	tx.UpdatedAt = &now
+	writeXML(w, http.StatusCreated, refund)
+}
+
+// partiallySettled returns the amount already submitted for partial settlement, the failed and voided
+// settlements excluded
+func (s *Server) partiallySettled(tx *braintree.Tx) int64 {
+	var total int64
+	if tx.PartialSettlementTransactionIds == nil {
+		return 0
+	}
+	for _, id := range *tx.PartialSettlementTransactionIds {
+		settlement, ok := s.transactions[id]
+		if !ok {
+			continue
+		}
+		switch settlement.Status {
+		case braintree.StatusVoided, braintree.StatusFailed, braintree.StatusSettlementDeclined:
+			continue
+		}
+		total += scale2(settlement.Amount)
+	}
+	return total
+}
+
+// submitForPartialSettlement captures a part of the authorization as a new sale, leaving the authorization
+// authorized for the next captures
+func (s *Server) submitForPartialSettlement(w http.ResponseWriter, req *request) {
+	tx, ok := s.transactions[req.segments[1]]
+	if !ok {
+		notFound(w)
+		return
+	}
+	var in txRequest
+	if !decode(w, req, &in) {
+		return
+	}
+	fail := func(code braintree.ErrorCode, attribute, message string) {
+		writeErrors(w, "", []fieldError{newError(code, attribute, message, "transaction")}, nil)
+	}
+	if tx.Status != braintree.StatusAuthorized {
+		fail(braintree.CodeTransactionCannotSubmitForPartialSettlement, "base", "Cannot submit for partial settlement.")
+		return
+	}
+	if errors := validateAmount(in.Amount, "transaction"); len(errors) > 0 {
+		writeErrors(w, "", errors, nil)
+		return
+	}
+	if s.partiallySettled(tx)+scale2(in.Amount) > scale2(tx.Amount) {
+		fail(braintree.CodeTransactionSettlementAmountIsTooLarge, "amount", "Settlement amount is too large.")
+		return
+	}
+
+	now := s.now()
+	parentID := tx.Id
+	settlement := &braintree.Tx{
+		Id:                         s.newID(),
+		Type:                       braintree.TxTypeSale,
+		Amount:                     in.Amount,
+		OrderId:                    in.OrderId,
+		MerchantAccountId:          tx.MerchantAccountId,
+		CurrencyISOCode:            tx.CurrencyISOCode,
+		PaymentInstrumentType:      tx.PaymentInstrumentType,
+		PaymentMethodToken:         tx.PaymentMethodToken,
+		CreditCard:                 tx.CreditCard,
+		Customer:                   tx.Customer,
+		BillingAddress:             tx.BillingAddress,
+		ShippingAddress:            tx.ShippingAddress,
+		Descriptor:                 in.Descriptor,
+		ProcessorResponseCode:      tx.ProcessorResponseCode,
+		ProcessorResponseText:      tx.ProcessorResponseText,
+		ProcessorResponseType:      tx.ProcessorResponseType,
+		ProcessorAuthorizationCode: tx.ProcessorAuthorizationCode,
+		AuthorizedTransactionId:    &parentID,
+		CreatedAt:                  &now,
+		UpdatedAt:                  &now,
+	}
+	if settlement.OrderId == "" {
+		settlement.OrderId = tx.OrderId
+	}
+	if settlement.Descriptor == nil {
+		settlement.Descriptor = tx.Descriptor
+	}
+	s.store(settlement, nil)
+	s.setStatus(settlement, braintree.StatusSubmittedForSettlement)
+
+	settlementIDs := []string{}
+	if tx.PartialSettlementTransactionIds != nil {
+		settlementIDs = append(settlementIDs, *tx.PartialSettlementTransactionIds...)
+	}
+	settlementIDs = append(settlementIDs, settlement.Id)
+	tx.PartialSettlementTransactionIds = &settlementIDs
+	tx.UpdatedAt = &now
+	writeXML(w, http.StatusCreated, settlement)
+}
+
+func (s *Server) clone(w http.ResponseWriter, req *request) {
+	source, ok := s.transactions[req.segments[1]]
+	if !ok {
+		notFound(w)
+		return
+	}
+	var in braintree.TxCloneRequest
+	if !decode(w, req, &in) {
+		return
+	}
+	if errors := validateAmount(in.Amount, "transaction-clone"); len(errors) > 0 {
+		writeErrors(w, "", errors, nil)
+		return
+	}
+
+	now := s.now()
+	tx := &braintree.Tx{
+		Id:                    s.newID(),
+		Type:                  source.Type,
+		Amount:                in.Amount,
+		Channel:               in.Channel,
+		OrderId:               source.OrderId,
+		MerchantAccountId:     source.MerchantAccountId,
+		CurrencyISOCode:       source.CurrencyISOCode,
+		PaymentInstrumentType: source.PaymentInstrumentType,
+		PaymentMethodToken:    source.PaymentMethodToken,
+		CreditCard:            source.CreditCard,
+		Customer:              source.Customer,
+		BillingAddress:        source.BillingAddress,
+		ShippingAddress:       source.ShippingAddress,
+		CVVResponseCode:       braintree.CVVResponseCodeNotProvided,
+		CreatedAt:             &now,
+		UpdatedAt:             &now,
+	}
+	s.store(tx, nil)
+	submit := tx.Type == braintree.TxTypeCredit || (in.Options != nil && in.Options.SubmitForSettlement)
+	if !s.authorize(w, tx, submit) {
+		return
+	}
+	writeXML(w, http.StatusCreated, tx)
+}
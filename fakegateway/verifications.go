@@ -0,0 +1,209 @@
+package fakegateway
+
+import (
+	"encoding/xml"
+	"net/http"
+
+	"github.com/badu/braintree"
+)
+
+const declinedCard = "4000111115" // bin and last 4 of 4000111111111115
+
+// verify runs the verification of the card, built with newCard, and stores it. As for the transactions,
+// the fraud card is rejected and the amounts between 2000.00 and 3000.99 are declined, as is the card
+// the sandbox declines on verifications
+func (s *Server) verify(card *braintree.CreditCard, amount *braintree.Decimal, merchantAccountID string) *braintree.CreditCardVerification {
+	if amount == nil {
+		amount = braintree.NewDecimal(0, 2)
+	}
+	if merchantAccountID == "" {
+		merchantAccountID = DefaultMerchantAccountID
+	}
+	now := s.now()
+	v := &braintree.CreditCardVerification{
+		Id:                s.newID(),
+		Amount:            amount,
+		CurrencyISOCode:   DefaultCurrency,
+		MerchantAccountId: merchantAccountID,
+		CreditCard:        txCard(card, card.CustomerId != ""),
+		BillingAddress:    address(card.BillingAddress, "billing"),
+		CreatedAt:         &now,
+	}
+	s.verifications[v.Id] = v
+	s.verificationIDs = append(s.verificationIDs, v.Id)
+
+	cents := scale2(amount)
+	switch {
+	case pan(card) == fraudCard:
+		v.Status = braintree.VerificationStatusGatewayRejected
+		v.GatewayRejectionReason = braintree.FraudReason
+	case pan(card) == declinedCard || (cents >= 200000 && cents < 300100):
+		code := braintree.ResponseCode(2000)
+		if cents >= 200000 {
+			code = braintree.ResponseCode(cents / 100)
+		}
+		v.Status = braintree.VerificationStatusProcessorDeclined
+		v.ProcessorResponseCode = code
+		v.ProcessorResponseText = code.Text()
+		if v.ProcessorResponseText == "" {
+			v.ProcessorResponseText = "Processor Declined"
+		}
+		v.ProcessorResponseType = code.Type()
+	default:
+		v.Status = braintree.VerificationStatusVerified
+		v.ProcessorResponseCode = 1000
+		v.ProcessorResponseText = v.ProcessorResponseCode.Text()
+		v.ProcessorResponseType = braintree.ResponseTypeApproved
+		v.AVSPostalCodeResponseCode = braintree.AVSResponseCodeMatches
+		v.AVSStreetAddressResponseCode = braintree.AVSResponseCodeMatches
+	}
+	v.CVVResponseCode = braintree.CVVResponseCodeNotProvided
+	return v
+}
+
+// verifyOnVault verifies the card about to be vaulted when asked, writing the error response and returning
+// false when the verification failed
+func (s *Server) verifyOnVault(w http.ResponseWriter, card *braintree.CreditCard, verifyCard *bool, merchantAccountID string) bool {
+	if verifyCard == nil || !*verifyCard {
+		return true
+	}
+	v := s.verify(card, nil, merchantAccountID)
+	if v.Verified() {
+		return true
+	}
+	writeVerificationError(w, v)
+	return false
+}
+
+func writeVerificationError(w http.ResponseWriter, v *braintree.CreditCardVerification) {
+	message := v.ProcessorResponseText
+	if v.Status == braintree.VerificationStatusGatewayRejected {
+		message = "Gateway Rejected: " + string(v.GatewayRejectionReason)
+	}
+	writeErrors(w, message, nil, v)
+}
+
+func (s *Server) serveVerifications(w http.ResponseWriter, req *request) {
+	switch {
+	case req.is(http.MethodPost, "verifications"):
+		s.createVerification(w, req)
+	case req.is(http.MethodPost, "verifications", "advanced_search_ids"):
+		s.searchVerificationIDs(w, req)
+	case req.is(http.MethodPost, "verifications", "advanced_search"):
+		s.searchVerifications(w, req)
+	case req.is(http.MethodGet, "verifications", "*"):
+		v, ok := s.verifications[req.segments[1]]
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeXML(w, http.StatusOK, v)
+	default:
+		notFound(w)
+	}
+}
+
+func (s *Server) createVerification(w http.ResponseWriter, req *request) {
+	var in braintree.CreditCardVerificationRequest
+	if !decode(w, req, &in) {
+		return
+	}
+
+	var card *braintree.CreditCard
+	switch {
+	case in.PaymentMethodNonce != "":
+		token, fromNonce, ok := s.resolveNonce(in.PaymentMethodNonce)
+		if !ok {
+			writeErrors(w, "", []fieldError{newError(braintree.CodeCreditCardPaymentMethodNonceUnknown, "payment_method_nonce", "Unknown or expired payment_method_nonce.", "verification")}, nil)
+			return
+		}
+		if token != "" {
+			card = s.cards[token]
+		} else {
+			card = s.newCard(fromNonce)
+		}
+	case in.CreditCard != nil:
+		if errors := validateCard(in.CreditCard, "verification", "credit-card"); len(errors) > 0 {
+			writeErrors(w, "", errors, nil)
+			return
+		}
+		card = s.newCard(in.CreditCard)
+	default:
+		writeErrors(w, "", []fieldError{newError(braintree.CodeCreditCardNumberIsRequired, "number", "Credit card number is required.", "verification", "credit-card")}, nil)
+		return
+	}
+
+	var amount *braintree.Decimal
+	var merchantAccountID string
+	if in.Options != nil {
+		amount, merchantAccountID = in.Options.Amount, in.Options.MerchantAccountId
+	}
+	if amount != nil && (amount.Scale > 2 || amount.Unscaled < 0) {
+		writeErrors(w, "", []fieldError{newError(braintree.CodeTransactionAmountIsInvalid, "amount", "Amount is an invalid format.", "verification", "options")}, nil)
+		return
+	}
+
+	v := s.verify(card, amount, merchantAccountID)
+	if !v.Verified() {
+		writeVerificationError(w, v)
+		return
+	}
+	writeXML(w, http.StatusCreated, v)
+}
+
+func (s *Server) verificationFields(v *braintree.CreditCardVerification) map[string][]string {
+	fields := map[string][]string{
+		"id":         {v.Id},
+		"ids":        {v.Id},
+		"status":     {string(v.Status)},
+		"created-at": formatTime(v.CreatedAt),
+	}
+	if card := v.CreditCard; card != nil {
+		fields["credit-card-number"] = []string{cardNumber(card)}
+		fields["credit-card-cardholder-name"] = nonEmpty(card.CardholderName)
+		fields["credit-card-expiration-date"] = nonEmpty(card.ExpirationDate)
+		fields["credit-card-card-type"] = nonEmpty(card.CardType)
+		fields["payment-method-token"] = nonEmpty(card.Token)
+		if customer := s.customers[card.CustomerId]; customer != nil {
+			fields["customer-id"] = []string{customer.Id}
+			fields["customer-email"] = nonEmpty(customer.Email)
+		}
+	}
+	if v.BillingAddress != nil {
+		fields["billing-address-details-postal-code"] = nonEmpty(v.BillingAddress.PostalCode)
+	}
+	return fields
+}
+
+func (s *Server) matchingVerifications(q query) []*braintree.CreditCardVerification {
+	var result []*braintree.CreditCardVerification
+	for _, id := range s.verificationIDs {
+		if v, ok := s.verifications[id]; ok && q.match(s.verificationFields(v)) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func (s *Server) searchVerificationIDs(w http.ResponseWriter, req *request) {
+	q, ok := parseSearch(w, req)
+	if !ok {
+		return
+	}
+	var ids []string
+	for _, v := range s.matchingVerifications(q) {
+		ids = append(ids, v.Id)
+	}
+	s.writeSearchIDs(w, ids)
+}
+
+func (s *Server) searchVerifications(w http.ResponseWriter, req *request) {
+	q, ok := parseSearch(w, req)
+	if !ok {
+		return
+	}
+	writeXML(w, http.StatusOK, &struct {
+		XMLName       xml.Name                            `xml:"credit-card-verifications"`
+		Verifications []*braintree.CreditCardVerification `xml:"verification"`
+	}{Verifications: s.matchingVerifications(q)})
+}
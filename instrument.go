@@ -0,0 +1,105 @@
+package braintree
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"time"
+)
+
+// Span describes a gateway call, from the moment it was started
+type Span struct {
+	Operation  string // the APIClient method which performed the call, e.g. Pay, Refund, FindCustomer
+	MerchantID string
+	Method     string
+	Path       string
+	Start      time.Time
+}
+
+// CallMetrics describes the outcome of a gateway call
+type CallMetrics struct {
+	Span
+	Duration              time.Duration
+	StatusCode            int // zero when no response was received
+	Attempts              int
+	ValidationCodes       []string
+	ProcessorResponseType ResponseType
+	Err                   error
+}
+
+func (m *CallMetrics) Retries() int {
+	if m.Attempts < 1 {
+		return 0
+	}
+	return m.Attempts - 1
+}
+
+// Instrumentation receives a callback when every gateway call starts and ends. The context returned by
+// StartSpan is used for the call and passed to EndSpan, so tracers can carry their span in it.
+type Instrumentation interface {
+	StartSpan(ctx context.Context, span *Span) context.Context
+	EndSpan(ctx context.Context, metrics *CallMetrics)
+}
+
+func newCallMetrics(span *Span, response *Response, attempts int, err error) *CallMetrics {
+	metrics := &CallMetrics{
+		Span:     *span,
+		Duration: time.Since(span.Start),
+		Attempts: attempts,
+		Err:      err,
+	}
+	if response != nil && response.Response != nil {
+		metrics.StatusCode = response.StatusCode
+		metrics.ProcessorResponseType = processorResponseType(response.Body)
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		for _, validationError := range apiErr.All() {
+			metrics.ValidationCodes = append(metrics.ValidationCodes, validationError.Code)
+		}
+	}
+	return metrics
+}
+
+// processorResponseType looks for the processor response type of a transaction or a verification, either
+// in its own response or attached to an api error response. The other responses, such as the search results,
+// are left at their root element, and the tokens are only read until the response type was found
+func processorResponseType(body []byte) ResponseType {
+	if len(body) == 0 {
+		return ""
+	}
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	var path []string
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			if _, ok := token.(xml.EndElement); ok && len(path) > 0 {
+				path = path[:len(path)-1]
+			}
+			continue
+		}
+		name := start.Name.Local
+		switch {
+		case len(path) == 0 && (name == "transaction" || name == "verification" || name == "api-error-response"),
+			len(path) == 1 && path[0] == "api-error-response" && (name == "transaction" || name == "verification"):
+			path = append(path, name)
+		case len(path) == 0:
+			return ""
+		case name == "processor-response-type" && path[len(path)-1] != "api-error-response":
+			var value ResponseType
+			if err := decoder.DecodeElement(&value, &start); err != nil {
+				return ""
+			}
+			return value
+		default:
+			if err := decoder.Skip(); err != nil {
+				return ""
+			}
+		}
+	}
+}
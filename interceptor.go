@@ -0,0 +1,66 @@
+package braintree
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Call describes a single attempt of a gateway call, as seen by the interceptors
+type Call struct {
+	Method  string
+	Path    string
+	URL     string
+	Version int
+	Attempt int
+	Header  http.Header
+	Payload []byte
+}
+
+// RoundTrip performs a call against the gateway, returning the response with its body already decoded
+type RoundTrip func(ctx context.Context, call *Call) (*Response, error)
+
+// Interceptor wraps a RoundTrip. Interceptors can inspect or alter the call before passing it to next,
+// inspect or replace the response after, or skip next entirely (e.g. fault injection).
+type Interceptor func(next RoundTrip) RoundTrip
+
+// chain builds the RoundTrip for the client's interceptors, the first interceptor being the outermost one
+func (c *APIClient) chain(final RoundTrip) RoundTrip {
+	result := final
+	for i := len(c.Interceptors) - 1; i >= 0; i-- {
+		result = c.Interceptors[i](result)
+	}
+	return result
+}
+
+// LoggingInterceptor logs every call with its outcome and duration
+func LoggingInterceptor(logger Logger) Interceptor {
+	return func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, call *Call) (*Response, error) {
+			start := time.Now()
+			response, err := next(ctx, call)
+			elapsed := time.Since(start)
+			if logger == nil {
+				return response, err
+			}
+			if err != nil {
+				logger.Warn("braintree call failed", "method", call.Method, "path", call.Path, "version", call.Version, "attempt", call.Attempt, "elapsed", elapsed, "error", err)
+				return response, err
+			}
+			logger.Info("braintree call", "method", call.Method, "path", call.Path, "version", call.Version, "attempt", call.Attempt, "status", response.StatusCode, "elapsed", elapsed)
+			return response, err
+		}
+	}
+}
+
+// TimingInterceptor reports the duration of every call to the observe function
+func TimingInterceptor(observe func(call *Call, response *Response, elapsed time.Duration, err error)) Interceptor {
+	return func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, call *Call) (*Response, error) {
+			start := time.Now()
+			response, err := next(ctx, call)
+			observe(call, response, time.Since(start), err)
+			return response, err
+		}
+	}
+}
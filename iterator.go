@@ -0,0 +1,279 @@
+package braintree
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// pageIterator fetches the ids matching a search once, then the records page by page. Up to Concurrency
+// pages are requested ahead of the one being read, and handed out in order. Each typed iterator embeds it,
+// and only asserts the type of the current record.
+type pageIterator struct {
+	// Concurrency is the number of pages fetched ahead, one when not set. Set it before the first call to Next
+	Concurrency int
+
+	searchIDs func(ctx context.Context) (*SearchResult, error)
+	fetch     func(ctx context.Context, ids []string) (interface{}, error)
+
+	result   *SearchResult
+	launched int
+	pending  []pendingPage
+	err      error
+	// ctxErr is the error of the context of the last call to Next, which a later call may resume from
+	ctxErr error
+
+	records reflect.Value
+	read    int
+	current interface{}
+}
+
+// pendingPage is a page being fetched, under the context of the call which launched it
+type pendingPage struct {
+	page int
+	ctx  context.Context
+	done chan fetchedPage
+}
+
+type fetchedPage struct {
+	records interface{}
+	err     error
+}
+
+// Next advances to the next record, returning false at the end of the results, on error or when the context
+// is done. The records fetched ahead are kept when the context is done, and a later call may resume from them
+func (it *pageIterator) Next(ctx context.Context) bool {
+	it.current = nil
+	if it.stopped(ctx) {
+		return false
+	}
+	for !it.records.IsValid() || it.read >= it.records.Len() {
+		records, ok := it.next(ctx)
+		if !ok {
+			return false
+		}
+		it.records, it.read = reflect.ValueOf(records), 0
+	}
+	it.current = it.records.Index(it.read).Interface()
+	it.read++
+	return true
+}
+
+func (it *pageIterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.ctxErr
+}
+
+// Total returns the number of ids matching the search, known after the first call to Next
+func (it *pageIterator) Total() int {
+	if it.result == nil {
+		return 0
+	}
+	return len(it.result.IDs)
+}
+
+// stopped reports whether the iteration ended on an error or the context of the call is done
+func (it *pageIterator) stopped(ctx context.Context) bool {
+	it.ctxErr = ctx.Err()
+	return it.err != nil || it.ctxErr != nil
+}
+
+// next returns the records of the next page, or false when there are no more pages or an error occurred
+func (it *pageIterator) next(ctx context.Context) (interface{}, bool) {
+	if it.stopped(ctx) {
+		return nil, false
+	}
+	if it.result == nil {
+		result, err := it.searchIDs(ctx)
+		if err != nil {
+			if it.ctxErr = ctx.Err(); it.ctxErr == nil {
+				it.err = err
+			}
+			return nil, false
+		}
+		it.result = result
+	}
+
+	concurrency := it.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	for len(it.pending) < concurrency && it.launched < it.result.PageCount {
+		it.pending = append(it.pending, it.launch(ctx, it.launched))
+		it.launched++
+	}
+
+	for len(it.pending) > 0 {
+		pending := &it.pending[0]
+		select {
+		case page := <-pending.done:
+			if page.err == nil {
+				it.pending = it.pending[1:]
+				return page.records, true
+			}
+			if pending.ctx != ctx && pending.ctx.Err() != nil && ctx.Err() == nil {
+				// fetched ahead by an earlier call, whose context is done since : fetch it again under this one
+				*pending = it.launch(ctx, pending.page)
+				continue
+			}
+			if it.ctxErr = ctx.Err(); it.ctxErr == nil {
+				it.err = page.err
+			} else {
+				*pending = pendingPage{page: pending.page, ctx: ctx, done: failedPage(page.err)}
+			}
+			return nil, false
+		case <-ctx.Done():
+			it.ctxErr = ctx.Err()
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+func (it *pageIterator) launch(ctx context.Context, page int) pendingPage {
+	start := page * it.result.PageSize
+	end := start + it.result.PageSize
+	if end > len(it.result.IDs) {
+		end = len(it.result.IDs)
+	}
+	ids := it.result.IDs[start:end]
+	// buffered, so that abandoned prefetches do not leak their goroutine
+	done := make(chan fetchedPage, 1)
+	go func() {
+		records, err := it.fetch(ctx, ids)
+		done <- fetchedPage{records: records, err: err}
+	}()
+	return pendingPage{page: page, ctx: ctx, done: done}
+}
+
+// failedPage hands out again the error of a page, so that the next call fetches it again
+func failedPage(err error) chan fetchedPage {
+	done := make(chan fetchedPage, 1)
+	done <- fetchedPage{err: err}
+	return done
+}
+
+// pagedQuery returns the query restricted to the ids of a page
+func pagedQuery(query *Search, ids []string) *Search {
+	pageQuery := &Search{}
+	if query != nil {
+		pageQuery = query.ShallowCopy()
+	}
+	pageQuery.AddMultiField("ids").Items = ids
+	return pageQuery
+}
+
+// TxIterator streams the transactions matching a search. The ids are searched on the first call to Next,
+// the transactions are fetched page by page afterwards.
+//
+//	it := c.IterateTxs(query)
+//	for it.Next(ctx) {
+//		tx := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type TxIterator struct {
+	pageIterator
+}
+
+func (c *APIClient) IterateTxs(query *Search) *TxIterator {
+	it := &TxIterator{}
+	it.searchIDs = func(ctx context.Context) (*SearchResult, error) {
+		return c.SearchTxs(ctx, query)
+	}
+	it.fetch = func(ctx context.Context, ids []string) (interface{}, error) {
+		return c.FetchTx(ctx, pagedQuery(query, ids))
+	}
+	return it
+}
+
+func (it *TxIterator) Value() *Tx {
+	tx, _ := it.current.(*Tx)
+	return tx
+}
+
+// CustomerIterator streams the customers matching a search, see TxIterator
+type CustomerIterator struct {
+	pageIterator
+}
+
+func (c *APIClient) IterateCustomers(query *Search) *CustomerIterator {
+	it := &CustomerIterator{}
+	it.searchIDs = func(ctx context.Context) (*SearchResult, error) {
+		return c.SearchCustomersByIDs(ctx, query)
+	}
+	it.fetch = func(ctx context.Context, ids []string) (interface{}, error) {
+		return c.fetchCustomers(ctx, "IterateCustomers", pagedQuery(query, ids))
+	}
+	return it
+}
+
+func (it *CustomerIterator) Value() *Customer {
+	customer, _ := it.current.(*Customer)
+	return customer
+}
+
+// SubscriptionIterator streams the subscriptions matching a search, see TxIterator
+type SubscriptionIterator struct {
+	pageIterator
+}
+
+func (c *APIClient) IterateSubscriptions(query *Search) *SubscriptionIterator {
+	it := &SubscriptionIterator{}
+	it.searchIDs = func(ctx context.Context) (*SearchResult, error) {
+		return c.SearchSubscriptions(ctx, query)
+	}
+	it.fetch = func(ctx context.Context, ids []string) (interface{}, error) {
+		return c.FetchSubscriptions(ctx, pagedQuery(query, ids))
+	}
+	return it
+}
+
+func (it *SubscriptionIterator) Value() *Subscription {
+	subscription, _ := it.current.(*Subscription)
+	return subscription
+}
+
+// CreditCardIterator streams the credit cards expiring between two months, see TxIterator
+type CreditCardIterator struct {
+	pageIterator
+}
+
+func (c *APIClient) IterateExpiringBetween(fromDate, toDate time.Time) *CreditCardIterator {
+	it := &CreditCardIterator{}
+	it.searchIDs = func(ctx context.Context) (*SearchResult, error) {
+		return c.ExpiringBetween(ctx, fromDate, toDate)
+	}
+	it.fetch = func(ctx context.Context, ids []string) (interface{}, error) {
+		return c.fetchExpiringBetween(ctx, "IterateExpiringBetween", fromDate, toDate, pagedQuery(nil, ids))
+	}
+	return it
+}
+
+func (it *CreditCardIterator) Value() *CreditCard {
+	card, _ := it.current.(*CreditCard)
+	return card
+}
+
+// VerificationIterator streams the credit card verifications matching a search, see TxIterator
+type VerificationIterator struct {
+	pageIterator
+}
+
+func (c *APIClient) IterateVerifications(query *Search) *VerificationIterator {
+	it := &VerificationIterator{}
+	it.searchIDs = func(ctx context.Context) (*SearchResult, error) {
+		return c.SearchVerifications(ctx, query)
+	}
+	it.fetch = func(ctx context.Context, ids []string) (interface{}, error) {
+		return c.FetchVerifications(ctx, pagedQuery(query, ids))
+	}
+	return it
+}
+
+func (it *VerificationIterator) Value() *CreditCardVerification {
+	verification, _ := it.current.(*CreditCardVerification)
+	return verification
+}
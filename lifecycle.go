@@ -0,0 +1,226 @@
+package braintree
+
+import (
+	"context"
+	"fmt"
+)
+
+// TxOperation is an operation changing a transaction once it is created
+type TxOperation string
+
+const (
+	OpSubmitForSettlement        TxOperation = "submit_for_settlement"
+	OpSubmitForPartialSettlement TxOperation = "submit_for_partial_settlement"
+	OpAdjustAuthorization        TxOperation = "adjust_authorization"
+	OpUpdateDetails              TxOperation = "update_details"
+	OpVoid                       TxOperation = "void"
+	OpRefund                     TxOperation = "refund"
+	OpHoldInEscrow               TxOperation = "hold_in_escrow"
+	OpReleaseFromEscrow          TxOperation = "release_from_escrow"
+	OpCancelRelease              TxOperation = "cancel_release"
+)
+
+// statusTransitions are the statuses a transaction moves to from each status, the final statuses having none
+var statusTransitions = map[Status][]Status{
+	StatusAuthorizing:            {StatusAuthorized, StatusProcessorDeclined, StatusGatewayRejected, StatusFailed},
+	StatusAuthorized:             {StatusSubmittedForSettlement, StatusVoided, StatusAuthorizationExpired},
+	StatusSubmittedForSettlement: {StatusSettling, StatusSettlementPending, StatusSettled, StatusSettlementDeclined, StatusVoided},
+	StatusSettling:               {StatusSettlementConfirmed, StatusSettlementPending, StatusSettled, StatusSettlementDeclined},
+	StatusSettlementConfirmed:    {StatusSettled, StatusSettlementDeclined},
+	StatusSettlementPending:      {StatusSettled, StatusSettlementDeclined},
+}
+
+// statusOperations are the statuses in which each operation is allowed
+var statusOperations = map[TxOperation][]Status{
+	OpSubmitForSettlement:        {StatusAuthorized},
+	OpSubmitForPartialSettlement: {StatusAuthorized},
+	OpAdjustAuthorization:        {StatusAuthorized},
+	OpUpdateDetails:              {StatusSubmittedForSettlement},
+	OpVoid:                       {StatusAuthorized, StatusSubmittedForSettlement},
+	OpRefund:                     {StatusSettled, StatusSettling},
+	OpHoldInEscrow:               {StatusAuthorized, StatusSubmittedForSettlement, StatusSettled},
+	OpReleaseFromEscrow:          {StatusSettled, StatusSettling},
+	OpCancelRelease:              {StatusSettled, StatusSettling},
+}
+
+func containsStatus(statuses []Status, status Status) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Transitions returns the statuses a transaction in this status may move to
+func (s Status) Transitions() []Status {
+	return append([]Status(nil), statusTransitions[s]...)
+}
+
+// CanTransitionTo reports whether a transaction in this status may move to the next one
+func (s Status) CanTransitionTo(next Status) bool {
+	return containsStatus(statusTransitions[s], next)
+}
+
+// Final reports whether a transaction in this status will not change anymore. The unknown statuses are not final
+func (s Status) Final() bool {
+	switch s {
+	case StatusSettled, StatusVoided, StatusFailed, StatusGatewayRejected, StatusProcessorDeclined,
+		StatusSettlementDeclined, StatusAuthorizationExpired:
+		return true
+	}
+	return false
+}
+
+// Allows reports whether the operation is allowed on a transaction in this status. The transaction may
+// still refuse it, e.g. a credit can't be refunded, see Tx.Can
+func (s Status) Allows(op TxOperation) bool {
+	return containsStatus(statusOperations[op], s)
+}
+
+// Can reports whether the operation is allowed on the transaction, given its status, type and escrow status
+func (tx *Tx) Can(op TxOperation) bool {
+	if !tx.Status.Allows(op) {
+		return false
+	}
+	switch op {
+	case OpRefund:
+		return tx.Type != TxTypeCredit && tx.EscrowStatus != EscrowHeld && tx.EscrowStatus != EscrowReleasePending
+	case OpHoldInEscrow:
+		return tx.EscrowStatus == ""
+	case OpReleaseFromEscrow:
+		return tx.EscrowStatus == EscrowHeld
+	case OpCancelRelease:
+		return tx.EscrowStatus == EscrowReleasePending
+	}
+	return true
+}
+
+func (tx *Tx) CanSubmitForSettlement() bool {
+	return tx.Can(OpSubmitForSettlement)
+}
+
+func (tx *Tx) CanVoid() bool {
+	return tx.Can(OpVoid)
+}
+
+func (tx *Tx) CanRefund() bool {
+	return tx.Can(OpRefund)
+}
+
+func (tx *Tx) CanReleaseFromEscrow() bool {
+	return tx.Can(OpReleaseFromEscrow)
+}
+
+// RefundableAmount returns the amount which can still be refunded : zero when the transaction can't be
+// refunded, otherwise its amount less the refunds given, the voided and failed ones excluded. Since the
+// transaction only carries the ids of its refunds, it returns nil when one of them is missing from refunds
+// (see APIClient.RefundableAmount, which fetches them), as well as when the transaction has no amount
+func (tx *Tx) RefundableAmount(refunds ...*Tx) *Decimal {
+	if tx.Amount == nil {
+		return nil
+	}
+	if !tx.CanRefund() {
+		return NewDecimal(0, tx.Amount.Scale)
+	}
+	byID := make(map[string]*Tx, len(refunds))
+	for _, refund := range refunds {
+		byID[refund.Id] = refund
+	}
+	remaining := tx.Amount
+	if tx.RefundIds == nil {
+		return remaining
+	}
+	for _, id := range *tx.RefundIds {
+		refund, ok := byID[id]
+		if !ok {
+			return nil
+		}
+		switch refund.Status {
+		case StatusVoided, StatusFailed, StatusGatewayRejected, StatusProcessorDeclined, StatusSettlementDeclined:
+			continue
+		}
+		if refund.Amount == nil {
+			return nil
+		}
+		var err error
+		if remaining, err = remaining.Sub(refund.Amount); err != nil {
+			return nil
+		}
+	}
+	if remaining.Sign() < 0 {
+		return NewDecimal(0, remaining.Scale)
+	}
+	return remaining
+}
+
+// RefundableAmount returns the amount of the transaction which can still be refunded, fetching its refunds
+func (c *APIClient) RefundableAmount(ctx context.Context, id string) (*Decimal, error) {
+	tx, err := c.FindTransaction(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return c.refundableAmount(ctx, tx)
+}
+
+func (c *APIClient) refundableAmount(ctx context.Context, tx *Tx) (*Decimal, error) {
+	if tx.Amount == nil {
+		return nil, fmt.Errorf("braintree: transaction %s has no amount", tx.Id)
+	}
+	var refunds []*Tx
+	if tx.CanRefund() && tx.RefundIds != nil && len(*tx.RefundIds) > 0 {
+		query := &Search{}
+		query.AddMultiField("ids").Items = *tx.RefundIds
+		var err error
+		if refunds, err = c.FetchTx(ctx, query); err != nil {
+			return nil, err
+		}
+	}
+	if amount := tx.RefundableAmount(refunds...); amount != nil {
+		return amount, nil
+	}
+	return nil, fmt.Errorf("braintree: the refunds of transaction %s could not be fetched", tx.Id)
+}
+
+// guard refuses, when the client Guards the transactions, the operations the transaction does not allow
+// in its current status, fetching it first. The settlements and refunds are refused as well when the amount
+// exceeds what is left to settle or refund
+func (c *APIClient) guard(ctx context.Context, id string, op TxOperation, amount *Decimal) error {
+	if !c.Guards {
+		return nil
+	}
+	tx, err := c.FindTransaction(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !tx.Can(op) {
+		escrow := ""
+		if tx.EscrowStatus != "" {
+			escrow = ", escrow " + string(tx.EscrowStatus)
+		}
+		return fmt.Errorf("%w : cannot %s transaction %s (%s %s%s)", ErrOperationNotAllowed, op, id, tx.Type, tx.Status, escrow)
+	}
+	var limit *Decimal
+	switch op {
+	case OpRefund:
+		if limit, err = c.refundableAmount(ctx, tx); err != nil {
+			return err
+		}
+		if amount == nil && limit.Sign() == 0 {
+			return fmt.Errorf("%w : transaction %s is already fully refunded", ErrOperationNotAllowed, id)
+		}
+	case OpSubmitForSettlement:
+		if limit, err = c.remainingAuthorization(ctx, tx); err != nil {
+			return err
+		}
+		if limit, err = c.partialSettlements.deduct(tx, limit); err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+	if amount != nil && amount.Cmp(limit) > 0 {
+		return fmt.Errorf("%w : cannot %s %s of transaction %s, %s allowed", ErrOperationNotAllowed, op, amount, id, limit)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package braintree
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+)
+
+// Logger is a leveled, structured logger. The key / value pairs follow the log/slog convention,
+// so a *slog.Logger can be used directly.
+type Logger interface {
+	Debug(msg string, keyvals ...interface{})
+	Info(msg string, keyvals ...interface{})
+	Warn(msg string, keyvals ...interface{})
+	Error(msg string, keyvals ...interface{})
+}
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	}
+	return fmt.Sprintf("LEVEL(%d)", int(l))
+}
+
+// StdLogger adapts a *log.Logger to the Logger interface, writing lines like `INFO msg key=value`
+type StdLogger struct {
+	Logger *log.Logger
+	Level  Level
+}
+
+func NewStdLogger(logger *log.Logger, level Level) *StdLogger {
+	return &StdLogger{Logger: logger, Level: level}
+}
+
+func (l *StdLogger) Debug(msg string, keyvals ...interface{}) {
+	l.log(LevelDebug, msg, keyvals)
+}
+
+func (l *StdLogger) Info(msg string, keyvals ...interface{}) {
+	l.log(LevelInfo, msg, keyvals)
+}
+
+func (l *StdLogger) Warn(msg string, keyvals ...interface{}) {
+	l.log(LevelWarn, msg, keyvals)
+}
+
+func (l *StdLogger) Error(msg string, keyvals ...interface{}) {
+	l.log(LevelError, msg, keyvals)
+}
+
+func (l *StdLogger) log(level Level, msg string, keyvals []interface{}) {
+	if l == nil || l.Logger == nil || level < l.Level {
+		return
+	}
+	var b bytes.Buffer
+	b.WriteString(level.String())
+	b.WriteString(" ")
+	b.WriteString(msg)
+	for i := 0; i < len(keyvals); i += 2 {
+		b.WriteString(" ")
+		if i+1 == len(keyvals) {
+			fmt.Fprintf(&b, "!BADKEY=%v", keyvals[i])
+			break
+		}
+		fmt.Fprintf(&b, "%v=", keyvals[i])
+		switch v := keyvals[i+1].(type) {
+		case string:
+			if bytes.ContainsAny([]byte(v), " \t\n\"=") {
+				fmt.Fprintf(&b, "%q", v)
+			} else {
+				b.WriteString(v)
+			}
+		case error:
+			fmt.Fprintf(&b, "%q", v.Error())
+		default:
+			fmt.Fprintf(&b, "%v", v)
+		}
+	}
+	l.Logger.Print(b.String())
+}
+
+func (c *APIClient) logDebug(msg string, keyvals ...interface{}) {
+	if c.Logger != nil {
+		c.Logger.Debug(msg, keyvals...)
+	}
+}
+
+func (c *APIClient) logWarn(msg string, keyvals ...interface{}) {
+	if c.Logger != nil {
+		c.Logger.Warn(msg, keyvals...)
+	}
+}
+
+func (c *APIClient) logError(msg string, keyvals ...interface{}) {
+	if c.Logger != nil {
+		c.Logger.Error(msg, keyvals...)
+	}
+}
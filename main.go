@@ -8,7 +8,6 @@ import (
 	"encoding/xml"
 	"errors"
 	"fmt"
-	"log"
 	"net"
 	"net/http"
 	"strings"
@@ -133,49 +132,148 @@ func NewWithAccessToken(accessToken string) (*APIClient, error) {
 
 type APIClient struct {
 	Key    *Secret
-	Logger *log.Logger
+	Logger Logger
 	Client *http.Client
+	Retry  *RetryPolicy
+	// Currencies are the currencies of the merchant accounts by id, the default account having the empty id.
+	// Pay checks the precision of the amounts of the accounts found here, see Money.Validate
+	Currencies map[string]string
+	// Guards makes Void, Refund, SubmitForSettlement and ReleaseFromEscrow fetch the transaction first, and
+	// refuse with ErrOperationNotAllowed the operations its status does not allow (see Tx.Can) without
+	// calling the gateway for them
+	Guards bool
+
+	Interceptors    []Interceptor
+	Instrumentation Instrumentation
+
+	partialSettlements partialSettlements
 }
 
-func (c *APIClient) do(ctx context.Context, method, path string, payload interface{}) (*Response, error) {
-	return c.call(ctx, method, path, payload, apiVersion3)
+// do calls the gateway on behalf of operation, the APIClient method reported to the Instrumentation
+func (c *APIClient) do(ctx context.Context, operation, method, path string, payload interface{}) (*Response, error) {
+	return c.call(ctx, operation, method, path, payload, apiVersion3)
 }
 
-func (c *APIClient) call(ctx context.Context, method, path string, payload interface{}, v apiVersion) (*Response, error) {
+func (c *APIClient) call(ctx context.Context, operation, method, path string, payload interface{}, v apiVersion) (*Response, error) {
+	if c.Instrumentation == nil {
+		response, _, err := c.invoke(ctx, method, path, payload, v)
+		if err != nil {
+			return nil, err
+		}
+		return response, nil
+	}
+
+	span := &Span{
+		Operation:  operation,
+		MerchantID: c.Key.MerchId,
+		Method:     method,
+		Path:       path,
+		Start:      time.Now(),
+	}
+	ctx = c.Instrumentation.StartSpan(ctx, span)
+	response, attempts, err := c.invoke(ctx, method, path, payload, v)
+	c.Instrumentation.EndSpan(ctx, newCallMetrics(span, response, attempts, err))
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// invoke performs the call, returning the last response received (if any) even when it carries an error
+func (c *APIClient) invoke(ctx context.Context, method, path string, payload interface{}, v apiVersion) (*Response, int, error) {
 	var buf bytes.Buffer
 	if payload != nil {
 		xmlBody, err := xml.Marshal(payload)
 		if err != nil {
-			c.Logger.Printf("xml Marshal error : %v\n", err)
-			return nil, err
+			c.logError("xml marshal error", "path", path, "error", err)
+			return nil, 0, err
 		}
 		_, err = buf.Write(xmlBody)
 		if err != nil {
-			c.Logger.Printf("write error : %v\n", err)
-			return nil, err
+			c.logError("write error", "path", path, "error", err)
+			return nil, 0, err
 		}
 	}
 
 	url := c.Key.URL + "/merchants/" + c.Key.MerchId + "/" + path
 
 	if c.Logger != nil {
-		c.Logger.Printf("---\n%q on %s payload:\n%s\n---\n", method, url, buf.String())
+		c.logDebug("braintree request", "method", method, "url", url, "payload", string(RedactXML(buf.Bytes())))
 	}
 
-	req, err := http.NewRequest(method, url, &buf)
+	maxAttempts := 1
+	if isRetryable(ctx, method) {
+		maxAttempts = c.Retry.attempts()
+	}
+
+	roundTrip := c.chain(c.send)
+
+	var (
+		result *Response
+		err    error
+	)
+	attempt := 1
+	for ; ; attempt++ {
+		call := &Call{
+			Method:  method,
+			Path:    path,
+			URL:     url,
+			Version: int(v),
+			Attempt: attempt,
+			Header:  c.header(v),
+			Payload: buf.Bytes(),
+		}
+		result, err = roundTrip(ctx, call)
+		if attempt >= maxAttempts || ctx.Err() != nil {
+			break
+		}
+		var wait time.Duration
+		if err != nil && !isTransientError(err) {
+			break
+		}
+		if err == nil {
+			if !isRetryableStatus(result.StatusCode) {
+				break
+			}
+			wait = retryAfter(result.Response)
+		}
+		if !sleep(ctx, c.Retry.backoff(attempt+1, wait)) {
+			break
+		}
+		c.logWarn("retrying braintree request", "method", method, "url", url, "attempt", attempt+1, "max_attempts", maxAttempts)
+	}
+	if err == nil {
+		err = result.apiError()
+		if err != nil {
+			c.logDebug("braintree error", "method", method, "url", url, "error", err)
+		}
+	}
+	if err != nil && attempt > 1 {
+		return result, attempt, &RetryError{Attempts: attempt, Err: err}
+	}
+	return result, attempt, err
+}
+
+func (c *APIClient) header(v apiVersion) http.Header {
+	header := http.Header{}
+	header.Set(HdrUserAgent, "Braintree-API-Client")
+	header.Set(HdrContentType, HdrApplicationXML)
+	header.Set(HdrAccept, HdrApplicationXML)
+	header.Set(HdrAcceptEncoding, "gzip")
+	header.Set("X-ApiVersion", fmt.Sprintf("%d", v))
+	header.Set(HdrAuthorization, c.Key.AuthorizationHeader())
+	return header
+}
+
+func (c *APIClient) send(ctx context.Context, call *Call) (*Response, error) {
+	req, err := http.NewRequest(call.Method, call.URL, bytes.NewReader(call.Payload))
 	if err != nil {
-		c.Logger.Printf("request building error : %v", err)
+		c.logError("request building error", "url", call.URL, "error", err)
 		return nil, err
 	}
 
 	req = req.WithContext(ctx)
-
-	req.Header.Set(HdrUserAgent, "Braintree-API-Client")
-	req.Header.Set(HdrContentType, HdrApplicationXML)
-	req.Header.Set(HdrAccept, HdrApplicationXML)
-	req.Header.Set(HdrAcceptEncoding, "gzip")
-	req.Header.Set("X-ApiVersion", fmt.Sprintf("%d", v))
-	req.Header.Set(HdrAuthorization, c.Key.AuthorizationHeader())
+	req.Header = call.Header
 
 	httpClient := c.Client
 	if httpClient == nil {
@@ -199,13 +297,7 @@ func (c *APIClient) call(ctx context.Context, method, path string, payload inter
 	}
 
 	if c.Logger != nil {
-		c.Logger.Printf("Braintree Response: <\n%s", string(result.Body))
-	}
-
-	err = result.apiError()
-	if err != nil {
-		c.Logger.Printf("xml Unmarshal error : %v\n", err)
-		return nil, err
+		c.logDebug("braintree response", "status", result.StatusCode, "body", string(RedactXML(result.Body)))
 	}
 	return result, nil
 }
@@ -7,39 +7,39 @@ import (
 )
 
 type MerchantAccount struct {
-	XMLName                 string                         `xml:"merchant-account,omitempty"`
-	Id                      string                         `xml:"id,omitempty"`
-	MasterMerchantAccountId string                         `xml:"master-merchant-account-id,omitempty"`
-	TOSAccepted             bool                           `xml:"tos_accepted,omitempty"`
-	Individual              *MerchantAccountPerson         `xml:"individual,omitempty"`
-	Business                *MerchantAccountBusiness       `xml:"business,omitempty"`
-	FundingOptions          *MerchantAccountFundingOptions `xml:"funding,omitempty"`
-	Status                  string                         `xml:"status,omitempty"`
+	XMLName                 string                         `xml:"merchant-account,omitempty" json:"-"`
+	Id                      string                         `xml:"id,omitempty" json:"id,omitempty"`
+	MasterMerchantAccountId string                         `xml:"master-merchant-account-id,omitempty" json:"master_merchant_account_id,omitempty"`
+	TOSAccepted             bool                           `xml:"tos_accepted,omitempty" json:"tos_accepted,omitempty"`
+	Individual              *MerchantAccountPerson         `xml:"individual,omitempty" json:"individual,omitempty"`
+	Business                *MerchantAccountBusiness       `xml:"business,omitempty" json:"business,omitempty"`
+	FundingOptions          *MerchantAccountFundingOptions `xml:"funding,omitempty" json:"funding,omitempty"`
+	Status                  string                         `xml:"status,omitempty" json:"status,omitempty"`
 }
 
 type MerchantAccountPerson struct {
-	FirstName   string   `xml:"first-name,omitempty"`
-	LastName    string   `xml:"last-name,omitempty"`
-	Email       string   `xml:"email,omitempty"`
-	Phone       string   `xml:"phone,omitempty"`
-	DateOfBirth string   `xml:"date-of-birth,omitempty"`
-	SSN         string   `xml:"ssn,omitempty"`
-	Address     *Address `xml:"address,omitempty"`
+	FirstName   string   `xml:"first-name,omitempty" json:"first_name,omitempty"`
+	LastName    string   `xml:"last-name,omitempty" json:"last_name,omitempty"`
+	Email       string   `xml:"email,omitempty" json:"email,omitempty"`
+	Phone       string   `xml:"phone,omitempty" json:"phone,omitempty"`
+	DateOfBirth string   `xml:"date-of-birth,omitempty" json:"date_of_birth,omitempty"`
+	SSN         string   `xml:"ssn,omitempty" json:"-"`
+	Address     *Address `xml:"address,omitempty" json:"address,omitempty"`
 }
 
 type MerchantAccountBusiness struct {
-	LegalName string   `xml:"legal-name,omitempty"`
-	DbaName   string   `xml:"dba-name,omitempty"`
-	TaxId     string   `xml:"tax-id,omitempty"`
-	Address   *Address `xml:"address,omitempty"`
+	LegalName string   `xml:"legal-name,omitempty" json:"legal_name,omitempty"`
+	DbaName   string   `xml:"dba-name,omitempty" json:"dba_name,omitempty"`
+	TaxId     string   `xml:"tax-id,omitempty" json:"-"`
+	Address   *Address `xml:"address,omitempty" json:"address,omitempty"`
 }
 
 type MerchantAccountFundingOptions struct {
-	Destination   string `xml:"destination,omitempty"`
-	Email         string `xml:"email,omitempty"`
-	MobilePhone   string `xml:"mobile-phone,omitempty"`
-	AccountNumber string `xml:"account-number,omitempty"`
-	RoutingNumber string `xml:"routing-number,omitempty"`
+	Destination   string `xml:"destination,omitempty" json:"destination,omitempty"`
+	Email         string `xml:"email,omitempty" json:"email,omitempty"`
+	MobilePhone   string `xml:"mobile-phone,omitempty" json:"mobile_phone,omitempty"`
+	AccountNumber string `xml:"account-number,omitempty" json:"-"`
+	RoutingNumber string `xml:"routing-number,omitempty" json:"-"`
 }
 
 const (
@@ -56,7 +56,7 @@ const (
 
 func (c *APIClient) CreateMerchantAccount(ctx context.Context, account *MerchantAccount) (*MerchantAccount, error) {
 	cleanAddress(account)
-	response, err := c.do(ctx, http.MethodPost, merchantAccounts+"/"+createViaAPI, account)
+	response, err := c.do(ctx, "CreateMerchantAccount", http.MethodPost, merchantAccounts+"/"+createViaAPI, account)
 	if err != nil {
 		return nil, err
 	}
@@ -72,7 +72,7 @@ func (c *APIClient) CreateMerchantAccount(ctx context.Context, account *Merchant
 }
 
 func (c *APIClient) FindMerchantAccount(ctx context.Context, id string) (*MerchantAccount, error) {
-	response, err := c.do(ctx, http.MethodGet, merchantAccounts+"/"+id, nil)
+	response, err := c.do(ctx, "FindMerchantAccount", http.MethodGet, merchantAccounts+"/"+id, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -89,7 +89,7 @@ func (c *APIClient) FindMerchantAccount(ctx context.Context, id string) (*Mercha
 
 func (c *APIClient) UpdateMerchantAccount(ctx context.Context, account *MerchantAccount) (*MerchantAccount, error) {
 	cleanAddress(account)
-	response, err := c.do(ctx, http.MethodPut, merchantAccounts+"/"+account.Id+"/"+updateViaAPI, account)
+	response, err := c.do(ctx, "UpdateMerchantAccount", http.MethodPut, merchantAccounts+"/"+account.Id+"/"+updateViaAPI, account)
 	if err != nil {
 		return nil, err
 	}
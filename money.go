@@ -0,0 +1,214 @@
+package braintree
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrUnknownCurrency = errors.New("braintree: unknown currency")
+
+// currencyExponents are the number of decimals of the minor units of the ISO 4217 currencies
+var currencyExponents = map[string]int{
+	"AED": 2, "AFN": 2, "ALL": 2, "AMD": 2, "ANG": 2, "AOA": 2, "ARS": 2, "AUD": 2, "AWG": 2, "AZN": 2,
+	"BAM": 2, "BBD": 2, "BDT": 2, "BGN": 2, "BHD": 3, "BIF": 0, "BMD": 2, "BND": 2, "BOB": 2, "BRL": 2,
+	"BSD": 2, "BTN": 2, "BWP": 2, "BYN": 2, "BZD": 2, "CAD": 2, "CDF": 2, "CHF": 2, "CLF": 4, "CLP": 0,
+	"CNY": 2, "COP": 2, "CRC": 2, "CUP": 2, "CVE": 2, "CZK": 2, "DJF": 0, "DKK": 2, "DOP": 2, "DZD": 2,
+	"EGP": 2, "ERN": 2, "ETB": 2, "EUR": 2, "FJD": 2, "FKP": 2, "GBP": 2, "GEL": 2, "GHS": 2, "GIP": 2,
+	"GMD": 2, "GNF": 0, "GTQ": 2, "GYD": 2, "HKD": 2, "HNL": 2, "HTG": 2, "HUF": 2, "IDR": 2, "ILS": 2,
+	"INR": 2, "IQD": 3, "IRR": 2, "ISK": 0, "JMD": 2, "JOD": 3, "JPY": 0, "KES": 2, "KGS": 2, "KHR": 2,
+	"KMF": 0, "KPW": 2, "KRW": 0, "KWD": 3, "KYD": 2, "KZT": 2, "LAK": 2, "LBP": 2, "LKR": 2, "LRD": 2,
+	"LSL": 2, "LYD": 3, "MAD": 2, "MDL": 2, "MGA": 2, "MKD": 2, "MMK": 2, "MNT": 2, "MOP": 2, "MRU": 2,
+	"MUR": 2, "MVR": 2, "MWK": 2, "MXN": 2, "MYR": 2, "MZN": 2, "NAD": 2, "NGN": 2, "NIO": 2, "NOK": 2,
+	"NPR": 2, "NZD": 2, "OMR": 3, "PAB": 2, "PEN": 2, "PGK": 2, "PHP": 2, "PKR": 2, "PLN": 2, "PYG": 0,
+	"QAR": 2, "RON": 2, "RSD": 2, "RUB": 2, "RWF": 0, "SAR": 2, "SBD": 2, "SCR": 2, "SDG": 2, "SEK": 2,
+	"SGD": 2, "SHP": 2, "SLE": 2, "SOS": 2, "SRD": 2, "SSP": 2, "STN": 2, "SVC": 2, "SYP": 2, "SZL": 2,
+	"THB": 2, "TJS": 2, "TMT": 2, "TND": 3, "TOP": 2, "TRY": 2, "TTD": 2, "TWD": 2, "TZS": 2, "UAH": 2,
+	"UGX": 0, "USD": 2, "UYI": 0, "UYU": 2, "UYW": 4, "UZS": 2, "VES": 2, "VND": 0, "VUV": 0, "WST": 2,
+	"XAF": 0, "XCD": 2, "XOF": 0, "XPF": 0, "YER": 2, "ZAR": 2, "ZMW": 2, "ZWL": 2,
+}
+
+// CurrencyExponent returns the number of decimals of the minor unit of the ISO 4217 currency, e.g. 2 for USD
+func CurrencyExponent(currency string) (int, bool) {
+	exponent, ok := currencyExponents[strings.ToUpper(currency)]
+	return exponent, ok
+}
+
+// Money is an amount in a currency, identified by its ISO 4217 code
+type Money struct {
+	Amount   *Decimal `json:"amount"`
+	Currency string   `json:"currency"`
+}
+
+// Validate checks that the currency is known and that the amount has no more decimals than its minor unit,
+// the trailing zeros apart : 10.50 USD and 100.0 JPY are valid, 10.5 JPY is not
+func (m Money) Validate() error {
+	exponent, ok := CurrencyExponent(m.Currency)
+	if !ok {
+		return fmt.Errorf("%w : %q", ErrUnknownCurrency, m.Currency)
+	}
+	if m.Amount == nil {
+		return fmt.Errorf("no amount of %s", m.Currency)
+	}
+	if m.Amount.Scale > exponent {
+		if _, err := m.Amount.Rescale(exponent); err != nil {
+			return fmt.Errorf("%w : %s %s has more than %d decimals", ErrDecimalPrecision, m.Amount, m.Currency, exponent)
+		}
+	}
+	return nil
+}
+
+// String returns the amount and the currency code, e.g. "10.50 USD"
+func (m Money) String() string {
+	if m.Amount == nil {
+		return m.Currency
+	}
+	return m.Amount.String() + " " + m.Currency
+}
+
+// moneyFormat is the way a language writes amounts
+type moneyFormat struct {
+	decimal, group string
+	symbolAfter    bool
+	symbolSpace    bool
+}
+
+var moneyFormats = map[string]moneyFormat{
+	"en": {decimal: ".", group: ","},
+	"ja": {decimal: ".", group: ","},
+	"zh": {decimal: ".", group: ","},
+	"ko": {decimal: ".", group: ","},
+	"de": {decimal: ",", group: ".", symbolAfter: true, symbolSpace: true},
+	"es": {decimal: ",", group: ".", symbolAfter: true, symbolSpace: true},
+	"it": {decimal: ",", group: ".", symbolAfter: true, symbolSpace: true},
+	"fr": {decimal: ",", group: "\u202f", symbolAfter: true, symbolSpace: true},
+	"sv": {decimal: ",", group: "\u00a0", symbolAfter: true, symbolSpace: true},
+	"pl": {decimal: ",", group: "\u00a0", symbolAfter: true, symbolSpace: true},
+	"nl": {decimal: ",", group: ".", symbolSpace: true},
+	"pt": {decimal: ",", group: ".", symbolSpace: true},
+}
+
+var currencySymbols = map[string]string{
+	"USD": "$", "EUR": "€", "GBP": "£", "JPY": "¥", "CNY": "¥", "INR": "₹", "KRW": "₩", "BRL": "R$",
+	"CAD": "CA$", "AUD": "A$", "CHF": "CHF", "SEK": "kr", "PLN": "zł", "MXN": "MX$", "ILS": "₪",
+}
+
+// Format writes the amount, rounded half up to the minor unit of the currency, the way the language of the
+// locale ("en-US", "de_DE", "fr") does : $1,234.50, 1.234,50 €. The unknown languages are written in English,
+// the currencies without a well known symbol by their code. The spaces are no-break spaces, as in CLDR, so
+// that the amounts are not wrapped.
+func (m Money) Format(locale string) string {
+	language := strings.ToLower(locale)
+	if i := strings.IndexAny(language, "-_"); i >= 0 {
+		language = language[:i]
+	}
+	format, ok := moneyFormats[language]
+	if !ok {
+		format = moneyFormats["en"]
+	}
+	currency := strings.ToUpper(m.Currency)
+	symbol, ok := currencySymbols[currency]
+	if !ok {
+		symbol = currency
+		format.symbolSpace = true
+	}
+	if m.Amount == nil {
+		return symbol
+	}
+
+	amount := m.Amount
+	if exponent, ok := CurrencyExponent(currency); ok {
+		if rounded, err := amount.Round(exponent, RoundHalfUp); err == nil {
+			amount = rounded
+		}
+	}
+	digits := amount.String()
+	negative := strings.HasPrefix(digits, "-")
+	digits = strings.TrimPrefix(digits, "-")
+	integer, fraction := digits, ""
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		integer, fraction = digits[:i], digits[i+1:]
+	}
+
+	var b strings.Builder
+	if negative {
+		b.WriteString("-")
+	}
+	if !format.symbolAfter {
+		b.WriteString(symbol)
+		if format.symbolSpace {
+			b.WriteString("\u00a0")
+		}
+	}
+	for i := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteString(format.group)
+		}
+		b.WriteByte(integer[i])
+	}
+	if fraction != "" {
+		b.WriteString(format.decimal)
+		b.WriteString(fraction)
+	}
+	if format.symbolAfter {
+		if format.symbolSpace {
+			b.WriteString("\u00a0")
+		}
+		b.WriteString(symbol)
+	}
+	return b.String()
+}
+
+// ValidateAmount checks the amount against the currency of the merchant account, see Money.Validate
+func (r *TxRequest) ValidateAmount(currency string) error {
+	return Money{Amount: r.Amount, Currency: currency}.Validate()
+}
+
+func (tx *Tx) AmountMoney() Money {
+	return Money{Amount: tx.Amount, Currency: tx.CurrencyISOCode}
+}
+
+func (tx *Tx) TaxAmountMoney() Money {
+	return Money{Amount: tx.TaxAmount, Currency: tx.CurrencyISOCode}
+}
+
+func (d *Dispute) AmountDisputedMoney() Money {
+	return Money{Amount: d.AmountDisputed, Currency: d.CurrencyISOCode}
+}
+
+func (d *Dispute) AmountWonMoney() Money {
+	return Money{Amount: d.AmountWon, Currency: d.CurrencyISOCode}
+}
+
+func (p *Plan) PriceMoney() Money {
+	return Money{Amount: p.Price, Currency: p.CurrencyISOCode}
+}
+
+// Currency returns the currency of the subscription, as found in its status history or its transactions
+func (s *Subscription) Currency() string {
+	for i := len(s.StatusEvents) - 1; i >= 0; i-- {
+		if s.StatusEvents[i] != nil && s.StatusEvents[i].CurrencyISOCode != "" {
+			return s.StatusEvents[i].CurrencyISOCode
+		}
+	}
+	if s.Transactions != nil {
+		for _, tx := range s.Transactions.Transaction {
+			if tx.CurrencyISOCode != "" {
+				return tx.CurrencyISOCode
+			}
+		}
+	}
+	return ""
+}
+
+func (s *Subscription) PriceMoney() Money {
+	return Money{Amount: s.Price, Currency: s.Currency()}
+}
+
+func (s *Subscription) BalanceMoney() Money {
+	return Money{Amount: s.Balance, Currency: s.Currency()}
+}
+
+func (s *Subscription) NextBillAmountMoney() Money {
+	return Money{Amount: s.NextBillAmount, Currency: s.Currency()}
+}
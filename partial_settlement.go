@@ -0,0 +1,254 @@
+package braintree
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// PartialSettlementOpts are the details which may differ between the partial settlements of an authorization
+type PartialSettlementOpts struct {
+	OrderId    string
+	Descriptor *Descriptor
+}
+
+type partialSettlementRequest struct {
+	XMLName    string      `xml:"transaction"`
+	Amount     *Decimal    `xml:"amount"`
+	OrderId    string      `xml:"order-id,omitempty"`
+	Descriptor *Descriptor `xml:"descriptor,omitempty"`
+}
+
+// partialSettlements holds the amounts of the partial settlements this client has in flight, by authorization,
+// so that concurrent captures can't exceed together what is left on the authorization. Once captured, they
+// are held until the gateway lists them on their authorization, the gateway reads lagging behind the captures
+// it just made : what was captured is otherwise always read from the gateway.
+type partialSettlements struct {
+	mu       sync.Mutex
+	inFlight map[string]*Decimal
+	captured map[string]map[string]capturedSettlement // by authorization and partial settlement id
+}
+
+type capturedSettlement struct {
+	amount *Decimal
+	at     time.Time
+}
+
+// capturedRetention bounds how long a capture missing from its authorization is held, for the captures of
+// the authorizations which are not read again
+const capturedRetention = time.Hour
+
+// reserve adds the amount to the ones in flight, unless they would exceed together what is left on the
+// authorization according to the gateway, less the captures it does not list yet
+func (p *partialSettlements) reserve(auth *Tx, remaining, amount *Decimal) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	left, err := p.left(auth, remaining)
+	if err != nil {
+		return err
+	}
+	reserved := amount
+	if pending, ok := p.inFlight[auth.Id]; ok {
+		if reserved, err = pending.Add(amount); err != nil {
+			return err
+		}
+	}
+	if reserved.Cmp(left) > 0 {
+		return fmt.Errorf("%w : %s requested, %s left on %s", ErrSettlementAmountTooLarge, reserved, left, auth.Id)
+	}
+	if p.inFlight == nil {
+		p.inFlight = map[string]*Decimal{}
+	}
+	p.inFlight[auth.Id] = reserved
+	return nil
+}
+
+// release removes the amount from the ones in flight, once the gateway answered, holding it as captured
+// when the gateway created the partial settlement
+func (p *partialSettlements) release(authID string, amount *Decimal, settlement *Tx) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if pending, ok := p.inFlight[authID]; ok {
+		left, err := pending.Sub(amount)
+		if err != nil || left.Sign() <= 0 {
+			delete(p.inFlight, authID)
+		} else {
+			p.inFlight[authID] = left
+		}
+	}
+	now := time.Now()
+	for id, captures := range p.captured {
+		for settlementID, capture := range captures {
+			if now.Sub(capture.at) > capturedRetention {
+				delete(captures, settlementID)
+			}
+		}
+		if len(captures) == 0 {
+			delete(p.captured, id)
+		}
+	}
+	if settlement == nil || settlement.Id == "" {
+		return
+	}
+	if p.captured == nil {
+		p.captured = map[string]map[string]capturedSettlement{}
+	}
+	if p.captured[authID] == nil {
+		p.captured[authID] = map[string]capturedSettlement{}
+	}
+	p.captured[authID][settlement.Id] = capturedSettlement{amount: amount, at: now}
+}
+
+// left returns the remaining amount of the authorization less the captures the gateway does not list on it
+// yet, forgetting the ones it lists. It is called with the lock held
+func (p *partialSettlements) left(auth *Tx, remaining *Decimal) (*Decimal, error) {
+	captures := p.captured[auth.Id]
+	if len(captures) == 0 {
+		return remaining, nil
+	}
+	if auth.PartialSettlementTransactionIds != nil {
+		for _, id := range *auth.PartialSettlementTransactionIds {
+			delete(captures, id)
+		}
+	}
+	if len(captures) == 0 {
+		delete(p.captured, auth.Id)
+		return remaining, nil
+	}
+	left := remaining
+	for _, capture := range captures {
+		var err error
+		if left, err = left.Sub(capture.amount); err != nil {
+			return nil, err
+		}
+	}
+	if left.Sign() < 0 {
+		return NewDecimal(0, left.Scale), nil
+	}
+	return left, nil
+}
+
+// deduct returns the remaining amount of the authorization less the captures the gateway does not list on it yet
+func (p *partialSettlements) deduct(auth *Tx, remaining *Decimal) (*Decimal, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.left(auth, remaining)
+}
+
+// RemainingAuthorization returns the amount of the authorization which can still be submitted for partial
+// settlement : zero unless it is still authorized, otherwise its amount less its partial settlements, the
+// failed and voided ones excluded, as read from the gateway on every call. The partial settlements of this
+// client the gateway does not list yet are deducted as well
+func (c *APIClient) RemainingAuthorization(ctx context.Context, authID string) (*Decimal, error) {
+	auth, err := c.FindTransaction(ctx, authID)
+	if err != nil {
+		return nil, err
+	}
+	remaining, err := c.remainingAuthorization(ctx, auth)
+	if err != nil {
+		return nil, err
+	}
+	return c.partialSettlements.deduct(auth, remaining)
+}
+
+// remainingAuthorization returns the amount of the authorization the gateway did not settle yet
+func (c *APIClient) remainingAuthorization(ctx context.Context, auth *Tx) (*Decimal, error) {
+	authID := auth.Id
+	if auth.Amount == nil {
+		return nil, fmt.Errorf("braintree: transaction %s has no amount", authID)
+	}
+	if auth.Status != StatusAuthorized {
+		return NewDecimal(0, auth.Amount.Scale), nil
+	}
+	remaining := auth.Amount
+	if auth.PartialSettlementTransactionIds == nil || len(*auth.PartialSettlementTransactionIds) == 0 {
+		return remaining, nil
+	}
+	query := &Search{}
+	query.AddMultiField("ids").Items = *auth.PartialSettlementTransactionIds
+	settlements, err := c.FetchTx(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	// the search may not return yet the partial settlements which were just created
+	found := make(map[string]bool, len(settlements))
+	for _, settlement := range settlements {
+		found[settlement.Id] = true
+	}
+	for _, id := range *auth.PartialSettlementTransactionIds {
+		if found[id] {
+			continue
+		}
+		settlement, err := c.FindTransaction(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		settlements = append(settlements, settlement)
+	}
+	for _, settlement := range settlements {
+		switch settlement.Status {
+		case StatusVoided, StatusFailed, StatusGatewayRejected, StatusProcessorDeclined, StatusSettlementDeclined:
+			continue
+		}
+		if settlement.Amount == nil {
+			return nil, fmt.Errorf("braintree: partial settlement %s of %s has no amount", settlement.Id, authID)
+		}
+		if remaining, err = remaining.Sub(settlement.Amount); err != nil {
+			return nil, err
+		}
+	}
+	if remaining.Sign() < 0 {
+		return NewDecimal(0, remaining.Scale), nil
+	}
+	return remaining, nil
+}
+
+// SubmitForPartialSettlement captures a part of the authorization, as a new transaction submitted for
+// settlement whose AuthorizedTransactionId is the authorization. The captures exceeding what is left on
+// the authorization (see RemainingAuthorization), less the captures of this client still in flight, fail
+// with ErrSettlementAmountTooLarge without calling the gateway, and the ones of a transaction which is not
+// authorized with ErrOperationNotAllowed.
+func (c *APIClient) SubmitForPartialSettlement(ctx context.Context, authID string, amount *Decimal, opts *PartialSettlementOpts) (*Tx, error) {
+	if amount == nil || amount.Sign() <= 0 {
+		return nil, fmt.Errorf("braintree: the partial settlement amount of %s must be positive", authID)
+	}
+	auth, err := c.FindTransaction(ctx, authID)
+	if err != nil {
+		return nil, err
+	}
+	if auth.Status != StatusAuthorized {
+		return nil, fmt.Errorf("%w : cannot submit transaction %s for partial settlement (%s %s)", ErrOperationNotAllowed, authID, auth.Type, auth.Status)
+	}
+	remaining, err := c.remainingAuthorization(ctx, auth)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.partialSettlements.reserve(auth, remaining, amount); err != nil {
+		return nil, err
+	}
+
+	req := &partialSettlementRequest{Amount: amount}
+	if opts != nil {
+		req.OrderId, req.Descriptor = opts.OrderId, opts.Descriptor
+	}
+	response, err := c.do(ctx, "SubmitForPartialSettlement", http.MethodPost, transactionsPath+"/"+authID+"/submit_for_partial_settlement", req)
+	if err != nil {
+		c.partialSettlements.release(authID, amount, nil)
+		return nil, err
+	}
+	switch response.StatusCode {
+	case 201:
+		var result Tx
+		if err := xml.Unmarshal(response.Body, &result); err != nil {
+			c.partialSettlements.release(authID, amount, nil)
+			return nil, err
+		}
+		c.partialSettlements.release(authID, amount, &result)
+		return &result, nil
+	}
+	c.partialSettlements.release(authID, amount, nil)
+	return nil, &invalidResponseError{response}
+}
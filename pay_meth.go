@@ -7,31 +7,31 @@ import (
 )
 
 type PaymentMethod struct {
-	CustomerId string
-	Token      string
-	Default    bool
-	ImageURL   string
+	CustomerId string `json:"customer_id"`
+	Token      string `json:"token"`
+	Default    bool   `json:"default"`
+	ImageURL   string `json:"image_url"`
 }
 
 const paymentMethodsPath = "payment_methods"
 
 type PaymentMethodRequest struct {
-	XMLName            xml.Name                     `xml:"payment-method"`
-	CustomerId         string                       `xml:"customer-id,omitempty"`
-	Token              string                       `xml:"token,omitempty"`
-	PaymentMethodNonce string                       `xml:"payment-method-nonce,omitempty"`
-	Options            *PaymentMethodRequestOptions `xml:"options,omitempty"`
+	XMLName            xml.Name                     `xml:"payment-method" json:"-"`
+	CustomerId         string                       `xml:"customer-id,omitempty" json:"customer_id,omitempty"`
+	Token              string                       `xml:"token,omitempty" json:"token,omitempty"`
+	PaymentMethodNonce string                       `xml:"payment-method-nonce,omitempty" json:"payment_method_nonce,omitempty"`
+	Options            *PaymentMethodRequestOptions `xml:"options,omitempty" json:"options,omitempty"`
 }
 
 type PaymentMethodRequestOptions struct {
-	VerificationMerchantAccountId string `xml:"verification-merchant-account-id,omitempty"`
-	MakeDefault                   bool   `xml:"make-default,omitempty"`
-	FailOnDuplicatePaymentMethod  bool   `xml:"fail-on-duplicate-payment-method,omitempty"`
-	VerifyCard                    *bool  `xml:"verify-card,omitempty"`
+	VerificationMerchantAccountId string `xml:"verification-merchant-account-id,omitempty" json:"verification_merchant_account_id,omitempty"`
+	MakeDefault                   bool   `xml:"make-default,omitempty" json:"make_default,omitempty"`
+	FailOnDuplicatePaymentMethod  bool   `xml:"fail-on-duplicate-payment-method,omitempty" json:"fail_on_duplicate_payment_method,omitempty"`
+	VerifyCard                    *bool  `xml:"verify-card,omitempty" json:"verify_card,omitempty"`
 }
 
 func (c *APIClient) CreatePayMethod(ctx context.Context, paymentMethodRequest *PaymentMethodRequest) (*PaymentMethod, error) {
-	response, err := c.call(ctx, http.MethodPost, paymentMethodsPath, paymentMethodRequest, apiVersion4)
+	response, err := c.call(ctx, "CreatePayMethod", http.MethodPost, paymentMethodsPath, paymentMethodRequest, apiVersion4)
 	if err != nil {
 		return nil, err
 	}
@@ -43,7 +43,7 @@ func (c *APIClient) CreatePayMethod(ctx context.Context, paymentMethodRequest *P
 }
 
 func (c *APIClient) UpdatePayMethod(ctx context.Context, token string, method *PaymentMethodRequest) (*PaymentMethod, error) {
-	response, err := c.call(ctx, http.MethodPut, paymentMethodsPath+"/any/"+token, method, apiVersion4)
+	response, err := c.call(ctx, "UpdatePayMethod", http.MethodPut, paymentMethodsPath+"/any/"+token, method, apiVersion4)
 	if err != nil {
 		return nil, err
 	}
@@ -55,7 +55,7 @@ func (c *APIClient) UpdatePayMethod(ctx context.Context, token string, method *P
 }
 
 func (c *APIClient) FindPayMethod(ctx context.Context, token string) (*PaymentMethod, error) {
-	response, err := c.call(ctx, http.MethodGet, paymentMethodsPath+"/any/"+token, nil, apiVersion4)
+	response, err := c.call(ctx, "FindPayMethod", http.MethodGet, paymentMethodsPath+"/any/"+token, nil, apiVersion4)
 	if err != nil {
 		return nil, err
 	}
@@ -67,7 +67,7 @@ func (c *APIClient) FindPayMethod(ctx context.Context, token string) (*PaymentMe
 }
 
 func (c *APIClient) DeletePayMethod(ctx context.Context, token string) error {
-	response, err := c.call(ctx, http.MethodDelete, paymentMethodsPath+"/any/"+token, nil, apiVersion4)
+	response, err := c.call(ctx, "DeletePayMethod", http.MethodDelete, paymentMethodsPath+"/any/"+token, nil, apiVersion4)
 	if err != nil {
 		return err
 	}
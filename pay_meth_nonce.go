@@ -7,19 +7,19 @@ import (
 )
 
 type PaymentMethodNonce struct {
-	Type             string                     `xml:"type"`
-	Nonce            string                     `xml:"nonce"`
-	Details          *PaymentMethodNonceDetails `xml:"details"`
-	ThreeDSecureInfo *ThreeDSecureInfo          `xml:"three-d-secure-info"`
+	Type             string                     `xml:"type" json:"type"`
+	Nonce            string                     `xml:"nonce" json:"nonce"`
+	Details          *PaymentMethodNonceDetails `xml:"details" json:"details"`
+	ThreeDSecureInfo *ThreeDSecureInfo          `xml:"three-d-secure-info" json:"three_d_secure_info"`
 }
 
 type PaymentMethodNonceDetails struct {
-	CardType string `xml:"card-type"`
-	Last2    string `xml:"last-two"`
+	CardType string `xml:"card-type" json:"card_type"`
+	Last2    string `xml:"last-two" json:"last_two"`
 }
 
 func (c *APIClient) FindPaymentMethodNonce(ctx context.Context, nonce string) (*PaymentMethodNonce, error) {
-	response, err := c.call(ctx, http.MethodGet, "/payment_method_nonces/"+nonce, nil, apiVersion4)
+	response, err := c.call(ctx, "FindPaymentMethodNonce", http.MethodGet, "/payment_method_nonces/"+nonce, nil, apiVersion4)
 	if err != nil {
 		return nil, err
 	}
@@ -35,7 +35,7 @@ func (c *APIClient) FindPaymentMethodNonce(ctx context.Context, nonce string) (*
 }
 
 func (c *APIClient) CreatePaymentMethodNonce(ctx context.Context, token string) (*PaymentMethodNonce, error) {
-	response, err := c.call(ctx, http.MethodPost, paymentMethodsPath+"/"+token+"/nonces", nil, apiVersion4)
+	response, err := c.call(ctx, "CreatePaymentMethodNonce", http.MethodPost, paymentMethodsPath+"/"+token+"/nonces", nil, apiVersion4)
 	if err != nil {
 		return nil, err
 	}
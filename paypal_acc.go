@@ -8,20 +8,20 @@ import (
 )
 
 type PayPalAccount struct {
-	XMLName       xml.Name              `xml:"paypal-account"`
-	CustomerId    string                `xml:"customer-id,omitempty"`
-	Token         string                `xml:"token,omitempty"`
-	Email         string                `xml:"email,omitempty"`
-	ImageURL      string                `xml:"image-url,omitempty"`
-	Default       bool                  `xml:"default,omitempty"`
-	CreatedAt     *time.Time            `xml:"created-at,omitempty"`
-	UpdatedAt     *time.Time            `xml:"updated-at,omitempty"`
-	Subscriptions *Subscriptions        `xml:"subscriptions,omitempty"`
-	Options       *PayPalAccountOptions `xml:"options,omitempty"`
+	XMLName       xml.Name              `xml:"paypal-account" json:"-"`
+	CustomerId    string                `xml:"customer-id,omitempty" json:"customer_id,omitempty"`
+	Token         string                `xml:"token,omitempty" json:"token,omitempty"`
+	Email         string                `xml:"email,omitempty" json:"email,omitempty"`
+	ImageURL      string                `xml:"image-url,omitempty" json:"image_url,omitempty"`
+	Default       bool                  `xml:"default,omitempty" json:"default,omitempty"`
+	CreatedAt     *time.Time            `xml:"created-at,omitempty" json:"created_at,omitempty"`
+	UpdatedAt     *time.Time            `xml:"updated-at,omitempty" json:"updated_at,omitempty"`
+	Subscriptions *Subscriptions        `xml:"subscriptions,omitempty" json:"subscriptions,omitempty"`
+	Options       *PayPalAccountOptions `xml:"options,omitempty" json:"options,omitempty"`
 }
 
 type PayPalAccounts struct {
-	Accounts []*PayPalAccount `xml:"paypal-account"`
+	Accounts []*PayPalAccount `xml:"paypal-account" json:"paypal_account"`
 }
 
 func (a *PayPalAccounts) PaymentMethods() []PaymentMethod {
@@ -50,7 +50,7 @@ func (a *PayPalAccount) ToPaymentMethod() *PaymentMethod {
 }
 
 type PayPalAccountOptions struct {
-	MakeDefault bool `xml:"make-default,omitempty"`
+	MakeDefault bool `xml:"make-default,omitempty" json:"make_default,omitempty"`
 }
 
 func (a *PayPalAccount) AllSubscriptions() []*Subscription {
@@ -68,7 +68,7 @@ func (a *PayPalAccount) AllSubscriptions() []*Subscription {
 }
 
 func (c *APIClient) UpdatePaypalAccount(ctx context.Context, paypalAccount *PayPalAccount) (*PayPalAccount, error) {
-	response, err := c.call(ctx, http.MethodPut, paymentMethodsPath+"/paypal_account/"+paypalAccount.Token, paypalAccount, apiVersion4)
+	response, err := c.call(ctx, "UpdatePaypalAccount", http.MethodPut, paymentMethodsPath+"/paypal_account/"+paypalAccount.Token, paypalAccount, apiVersion4)
 	if err != nil {
 		return nil, err
 	}
@@ -84,7 +84,7 @@ func (c *APIClient) UpdatePaypalAccount(ctx context.Context, paypalAccount *PayP
 }
 
 func (c *APIClient) SearchPaypalAccount(ctx context.Context, token string) (*PayPalAccount, error) {
-	response, err := c.call(ctx, http.MethodGet, paymentMethodsPath+"/paypal_account/"+token, nil, apiVersion4)
+	response, err := c.call(ctx, "SearchPaypalAccount", http.MethodGet, paymentMethodsPath+"/paypal_account/"+token, nil, apiVersion4)
 	if err != nil {
 		return nil, err
 	}
@@ -100,7 +100,7 @@ func (c *APIClient) SearchPaypalAccount(ctx context.Context, token string) (*Pay
 }
 
 func (c *APIClient) DeletePaypalAccount(ctx context.Context, paypalAccount *PayPalAccount) error {
-	response, err := c.call(ctx, http.MethodDelete, paymentMethodsPath+"/paypal_account/"+paypalAccount.Token, nil, apiVersion4)
+	response, err := c.call(ctx, "DeletePaypalAccount", http.MethodDelete, paymentMethodsPath+"/paypal_account/"+paypalAccount.Token, nil, apiVersion4)
 	if err != nil {
 		return err
 	}
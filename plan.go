@@ -8,32 +8,32 @@ import (
 )
 
 type Plan struct {
-	XMLName               string       `xml:"plan"`
-	Id                    string       `xml:"id"`
-	MerchantId            string       `xml:"merchant-id"`
-	BillingDayOfMonth     *int         `xml:"billing-day-of-month"`
-	BillingFrequency      *int         `xml:"billing-frequency"`
-	CurrencyISOCode       string       `xml:"currency-iso-code"`
-	Description           string       `xml:"description"`
-	Name                  string       `xml:"name"`
-	NumberOfBillingCycles *int         `xml:"number-of-billing-cycles"`
-	Price                 *Decimal     `xml:"price"`
-	TrialDuration         *int         `xml:"trial-duration"`
-	TrialDurationUnit     string       `xml:"trial-duration-unit"`
-	TrialPeriod           bool         `xml:"trial-period"`
-	CreatedAt             *time.Time   `xml:"created-at"`
-	UpdatedAt             *time.Time   `xml:"updated-at"`
-	AddOns                AddOnList    `xml:"add-ons"`
-	Discounts             DiscountList `xml:"discounts"`
+	XMLName               string       `xml:"plan" json:"-"`
+	Id                    string       `xml:"id" json:"id"`
+	MerchantId            string       `xml:"merchant-id" json:"merchant_id"`
+	BillingDayOfMonth     *int         `xml:"billing-day-of-month" json:"billing_day_of_month"`
+	BillingFrequency      *int         `xml:"billing-frequency" json:"billing_frequency"`
+	CurrencyISOCode       string       `xml:"currency-iso-code" json:"currency_iso_code"`
+	Description           string       `xml:"description" json:"description"`
+	Name                  string       `xml:"name" json:"name"`
+	NumberOfBillingCycles *int         `xml:"number-of-billing-cycles" json:"number_of_billing_cycles"`
+	Price                 *Decimal     `xml:"price" json:"price"`
+	TrialDuration         *int         `xml:"trial-duration" json:"trial_duration"`
+	TrialDurationUnit     string       `xml:"trial-duration-unit" json:"trial_duration_unit"`
+	TrialPeriod           bool         `xml:"trial-period" json:"trial_period"`
+	CreatedAt             *time.Time   `xml:"created-at" json:"created_at"`
+	UpdatedAt             *time.Time   `xml:"updated-at" json:"updated_at"`
+	AddOns                AddOnList    `xml:"add-ons" json:"add_ons"`
+	Discounts             DiscountList `xml:"discounts" json:"discounts"`
 }
 
 type Plans struct {
-	XMLName string  `xml:"plans"`
-	Plan    []*Plan `xml:"plan"`
+	XMLName string  `xml:"plans" json:"-"`
+	Plan    []*Plan `xml:"plan" json:"plan"`
 }
 
 func (c *APIClient) ListPlans(ctx context.Context) ([]*Plan, error) {
-	response, err := c.do(ctx, http.MethodGet, "plans", nil)
+	response, err := c.do(ctx, "ListPlans", http.MethodGet, "plans", nil)
 	if err != nil {
 		return nil, err
 	}
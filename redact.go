@@ -0,0 +1,122 @@
+package braintree
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type redactMode int
+
+const (
+	redactDrop       redactMode = iota // the element is removed entirely
+	redactPAN                          // only the first six and last four digits are kept
+	redactLast4                        // only the last four characters are kept
+	redactEverything                   // every character is masked
+)
+
+// redactedElements are the XML elements which carry card holder or personal data
+var redactedElements = map[string]redactMode{
+	"cvv":             redactDrop,
+	"number":          redactPAN,
+	"expiration-date": redactEverything,
+	"ssn":             redactLast4,
+	"tax-id":          redactLast4,
+	"account-number":  redactLast4,
+	"routing-number":  redactLast4,
+}
+
+const redactMask = '*'
+
+// RedactXML returns a copy of the XML payload which can be logged safely : card numbers are masked
+// to their first six and last four digits, CVVs are dropped, expiration dates, social security numbers,
+// tax ids, account and routing numbers are masked. Input that cannot be parsed is redacted entirely.
+func RedactXML(data []byte) []byte {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return data
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var writer bytes.Buffer
+	encoder := xml.NewEncoder(&writer)
+
+	var (
+		current string
+		mode    redactMode
+		value   bytes.Buffer
+	)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return unparseable(data)
+		}
+
+		if current != "" {
+			switch t := token.(type) {
+			case xml.CharData:
+				value.Write(t)
+				continue
+			case xml.EndElement:
+				if t.Name.Local != current {
+					continue
+				}
+				if mode != redactDrop {
+					if err := encoder.EncodeToken(xml.CharData(redactValue(value.String(), mode))); err != nil {
+						return unparseable(data)
+					}
+					if err := encoder.EncodeToken(t); err != nil {
+						return unparseable(data)
+					}
+				}
+				current = ""
+				value.Reset()
+				continue
+			default:
+				// nested elements inside sensitive ones are not expected, so they are dropped
+				continue
+			}
+		}
+
+		if start, ok := token.(xml.StartElement); ok {
+			if m, found := redactedElements[start.Name.Local]; found {
+				current, mode = start.Name.Local, m
+				if mode == redactDrop {
+					continue
+				}
+			}
+		}
+
+		if err := encoder.EncodeToken(xml.CopyToken(token)); err != nil {
+			return unparseable(data)
+		}
+	}
+
+	if err := encoder.Flush(); err != nil {
+		return unparseable(data)
+	}
+	return writer.Bytes()
+}
+
+func unparseable(data []byte) []byte {
+	return []byte("[redacted " + strconv.Itoa(len(data)) + " bytes]")
+}
+
+func redactValue(value string, mode redactMode) string {
+	value = strings.TrimSpace(value)
+	switch mode {
+	case redactPAN:
+		if len(value) >= 13 {
+			return value[:6] + strings.Repeat(string(redactMask), len(value)-10) + value[len(value)-4:]
+		}
+	case redactLast4:
+		if len(value) > 4 {
+			return strings.Repeat(string(redactMask), len(value)-4) + value[len(value)-4:]
+		}
+	}
+	return strings.Repeat(string(redactMask), len(value))
+}
@@ -1,10 +1,9 @@
 package braintree
 
 import (
-	"bytes"
+	"encoding/json"
 	"encoding/xml"
 	"strconv"
-	"strings"
 	"time"
 )
 
@@ -39,11 +38,50 @@ type TextField struct {
 	Contains   string `xml:"contains,omitempty"`
 }
 
+// RangeField searches a number. The bounds of 0 are left out of Is, Min and Max : IsText, MinText and MaxText
+// hold the bounds as decimal text instead, keeping 0 and the precision of the amounts, and take precedence
 type RangeField struct {
 	XMLName xml.Name
-	Is      float64 `xml:"is,omitempty"`
-	Min     float64 `xml:"min,omitempty"`
-	Max     float64 `xml:"max,omitempty"`
+	Is      float64
+	Min     float64
+	Max     float64
+	IsText  string
+	MinText string
+	MaxText string
+}
+
+func (d RangeField) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start = start.Copy()
+	start.Name = d.XMLName
+
+	err := e.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+
+	is, min, max := d.bounds()
+	for _, bound := range []struct{ name, value string }{{"is", is}, {"min", min}, {"max", max}} {
+		if bound.value == "" {
+			continue
+		}
+		err = e.EncodeElement(bound.value, xml.StartElement{Name: xml.Name{Local: bound.name}})
+		if err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+// bounds returns the bounds as written to the gateway, empty for the ones left out
+func (d RangeField) bounds() (is, min, max string) {
+	bound := func(text string, value float64) string {
+		if text != "" || value == 0 {
+			return text
+		}
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+	return bound(d.IsText, d.Is), bound(d.MinText, d.Min), bound(d.MaxText, d.Max)
 }
 
 type TimeField struct {
@@ -98,6 +136,11 @@ type MultiField struct {
 	Items   []string `xml:"item"`
 }
 
+type KeyValueField struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
 func (s *Search) addField(fieldName string, field interface{}) {
 	if i, ok := s.fieldIndex[fieldName]; !ok {
 		s.fields = append(s.fields, field)
@@ -137,6 +180,12 @@ func (s *Search) AddMultiField(field string) *MultiField {
 	return f
 }
 
+func (s *Search) AddKeyValueField(field string) *KeyValueField {
+	f := &KeyValueField{XMLName: xml.Name{Local: field}}
+	s.addField(field, f)
+	return f
+}
+
 func (s *Search) ShallowCopy() *Search {
 	return &Search{
 		fields: func() []interface{} {
@@ -154,100 +203,100 @@ func (s *Search) ShallowCopy() *Search {
 	}
 }
 
-type Decimal struct {
-	Unscaled int64
-	Scale    int
-}
-
-func NewDecimal(unscaled int64, scale int) *Decimal {
-	return &Decimal{Unscaled: unscaled, Scale: scale}
-}
-
-func (d *Decimal) MarshalText() (text []byte, err error) {
-	b := new(bytes.Buffer)
-	if d.Scale <= 0 {
-		b.WriteString(strconv.FormatInt(d.Unscaled, 10))
-		b.WriteString(strings.Repeat("0", -d.Scale))
-	} else {
-		str := strconv.FormatInt(d.Unscaled, 10)
-		if len(str) <= d.Scale {
-			str = strings.Repeat("0", (d.Scale+1)-len(str)) + str
+// deepCopy returns a copy of the search which shares none of its fields
+func (s *Search) deepCopy() *Search {
+	result := s.ShallowCopy()
+	for i, field := range result.fields {
+		switch f := field.(type) {
+		case *TextField:
+			copied := *f
+			result.fields[i] = &copied
+		case *RangeField:
+			copied := *f
+			result.fields[i] = &copied
+		case *TimeField:
+			copied := *f
+			result.fields[i] = &copied
+		case *MultiField:
+			copied := *f
+			copied.Items = append([]string(nil), f.Items...)
+			result.fields[i] = &copied
+		case *KeyValueField:
+			copied := *f
+			result.fields[i] = &copied
 		}
-		b.WriteString(str[:len(str)-d.Scale])
-		b.WriteString(".")
-		b.WriteString(str[len(str)-d.Scale:])
 	}
-	return b.Bytes(), nil
+	return result
 }
 
-func (d *Decimal) UnmarshalText(text []byte) (err error) {
-	var (
-		str            = string(text)
-		unscaled int64 = 0
-		scale          = 0
-	)
+type Date struct {
+	time.Time
+}
 
-	if str == "" {
-		return nil
+func (d *Date) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	var v string
+	err := decoder.DecodeElement(&v, &start)
+	if err != nil {
+		return err
 	}
-
-	if i := strings.Index(str, "."); i != -1 {
-		scale = len(str) - i - 1
-		str = strings.Replace(str, ".", "", 1)
+	// the gateway sends the missing dates as empty elements, marked nil="true"
+	if v == "" {
+		*d = Date{}
+		return nil
 	}
 
-	if unscaled, err = strconv.ParseInt(str, 10, 64); err != nil {
+	parse, err := time.Parse(DateFormat, v)
+	if err != nil {
 		return err
 	}
 
-	d.Unscaled = unscaled
-	d.Scale = scale
-
+	*d = Date{Time: parse}
 	return nil
 }
 
-func (d *Decimal) Cmp(y *Decimal) int {
-	xUnscaled, yUnscaled := d.Unscaled, y.Unscaled
-	xScale, yScale := d.Scale, y.Scale
-
-	for ; xScale > yScale; xScale-- {
-		yUnscaled = yUnscaled * 10
-	}
-
-	for ; yScale > xScale; yScale-- {
-		xUnscaled = xUnscaled * 10
-	}
-
-	switch {
-	case xUnscaled < yUnscaled:
-		return -1
-	case xUnscaled > yUnscaled:
-		return 1
-	default:
-		return 0
+// dateOrNil returns nil for the zero date, which is what the empty elements decode to
+func dateOrNil(d *Date) *Date {
+	if d == nil || d.IsZero() {
+		return nil
 	}
+	return d
 }
 
-func (d *Decimal) String() string {
-	b, err := d.MarshalText()
-
-	if err != nil {
-		panic(err)
+// formatDate returns the date as the gateway writes it, empty when missing
+func formatDate(d *Date) string {
+	if d == nil {
+		return ""
 	}
+	return d.Format(DateFormat)
+}
 
-	return string(b)
+func (d *Date) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(d.Format(DateFormat), start)
 }
 
-type Date struct {
-	time.Time
+// MarshalJSON writes the date as a string, formatted as in xml, instead of the time of the embedded time.Time.
+// The zero date is written as null
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.Format(DateFormat))
 }
 
-func (d *Date) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+// UnmarshalJSON reads null and the empty string as the zero date
+func (d *Date) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*d = Date{}
+		return nil
+	}
 	var v string
-	err := decoder.DecodeElement(&v, &start)
-	if err != nil {
+	if err := json.Unmarshal(b, &v); err != nil {
 		return err
 	}
+	if v == "" {
+		*d = Date{}
+		return nil
+	}
 
 	parse, err := time.Parse(DateFormat, v)
 	if err != nil {
@@ -258,31 +307,27 @@ func (d *Date) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error
 	return nil
 }
 
-func (d *Date) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
-	return e.EncodeElement(d.Format(DateFormat), start)
-}
-
 type ModificationRequest struct {
-	Amount                *Decimal `xml:"amount,omitempty"`
-	NumberOfBillingCycles int      `xml:"number-of-billing-cycles,omitempty"`
-	Quantity              int      `xml:"quantity,omitempty"`
-	NeverExpires          bool     `xml:"never-expires,omitempty"`
+	Amount                *Decimal `xml:"amount,omitempty" json:"amount,omitempty"`
+	NumberOfBillingCycles int      `xml:"number-of-billing-cycles,omitempty" json:"number_of_billing_cycles,omitempty"`
+	Quantity              int      `xml:"quantity,omitempty" json:"quantity,omitempty"`
+	NeverExpires          bool     `xml:"never-expires,omitempty" json:"never_expires,omitempty"`
 }
 
 type AddModificationRequest struct {
 	ModificationRequest
-	InheritedFromID string `xml:"inherited-from-id,omitempty"`
+	InheritedFromID string `xml:"inherited-from-id,omitempty" json:"inherited_from_id,omitempty"`
 }
 
 type UpdateModificationRequest struct {
 	ModificationRequest
-	ExistingID string `xml:"existing-id,omitempty"`
+	ExistingID string `xml:"existing-id,omitempty" json:"existing_id,omitempty"`
 }
 
 type ModificationsRequest struct {
-	Add               []AddModificationRequest
-	Update            []UpdateModificationRequest
-	RemoveExistingIDs []string
+	Add               []AddModificationRequest    `json:"add"`
+	Update            []UpdateModificationRequest `json:"update"`
+	RemoveExistingIDs []string                    `json:"remove_existing_ids"`
 }
 
 func (m ModificationsRequest) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
@@ -96,10 +96,11 @@ func (r *Response) apiError() error {
 	err := xml.Unmarshal(r.Body, &b)
 	if err == nil && b.ErrorMessage != "" {
 		b.statusCode = r.StatusCode
+		b.response = r
 		return &b
 	}
 	if r.StatusCode > 299 {
-		return httpError(r.StatusCode)
+		return &HTTPError{response: r}
 	}
 	return nil
 }
@@ -107,18 +108,21 @@ func (r *Response) apiError() error {
 type APIError struct {
 	statusCode int
 	errors     ValidationErrors
+	response   *Response
 
 	ErrorMessage    string
 	MerchantAccount *MerchantAccount
 	Transaction     *Tx
+	Verification    *CreditCardVerification
 }
 
 func (e *APIError) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	var x struct {
-		Errors          ValidationErrors `xml:"errors"`
-		ErrorMessage    string           `xml:"message"`
-		MerchantAccount *MerchantAccount `xml:"merchant-account"`
-		Transaction     *Tx              `xml:"transaction"`
+		Errors          ValidationErrors        `xml:"errors"`
+		ErrorMessage    string                  `xml:"message"`
+		MerchantAccount *MerchantAccount        `xml:"merchant-account"`
+		Transaction     *Tx                     `xml:"transaction"`
+		Verification    *CreditCardVerification `xml:"verification"`
 	}
 	err := d.DecodeElement(&x, &start)
 	if err != nil {
@@ -128,6 +132,7 @@ func (e *APIError) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	e.ErrorMessage = x.ErrorMessage
 	e.MerchantAccount = x.MerchantAccount
 	e.Transaction = x.Transaction
+	e.Verification = x.Verification
 	return nil
 }
 
@@ -139,6 +144,34 @@ func (e *APIError) StatusCode() int {
 	return e.statusCode
 }
 
+func (e *APIError) Response() *Response {
+	return e.response
+}
+
+func (e *APIError) Path() string {
+	return requestPath(e.response)
+}
+
+func (e *APIError) RequestID() string {
+	return requestID(e.response)
+}
+
+// Is matches ErrProcessorDeclined and ErrGatewayRejected by the status of the transaction or of the
+// verification, and the other sentinels by the HTTP status code
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrProcessorDeclined:
+		return (e.Transaction != nil && e.Transaction.Status == StatusProcessorDeclined) ||
+			(e.Verification != nil && e.Verification.Status == VerificationStatusProcessorDeclined)
+	case ErrGatewayRejected:
+		return (e.Transaction != nil && e.Transaction.Status == StatusGatewayRejected) ||
+			(e.Verification != nil && e.Verification.Status == VerificationStatusGatewayRejected)
+	case nil:
+		return false
+	}
+	return target == statusError(e.statusCode)
+}
+
 func (e *APIError) All() []ValidationError {
 	return e.errors.AllDeep()
 }
@@ -147,6 +180,52 @@ func (e *APIError) For(name string) *ValidationErrors {
 	return e.errors.For(name)
 }
 
+// Has reports whether any of the validation errors, at any depth, carries the code
+func (e *APIError) Has(code ErrorCode) bool {
+	for _, err := range e.All() {
+		if ErrorCode(err.Code) == code {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldErrors groups the validation errors by the request field they are about, named after the xml tags
+// of the request structures, e.g. "transaction.credit-card.number" or "transaction.line-items.0.quantity".
+// Errors on the object itself (attribute "base") are keyed by the object, e.g. "transaction"
+func (e *APIError) FieldErrors() map[string][]ValidationError {
+	fields := make(map[string][]ValidationError)
+	e.errors.collectFields("", fields)
+	return fields
+}
+
+func (r *ValidationErrors) collectFields(prefix string, fields map[string][]ValidationError) {
+	if r == nil {
+		return
+	}
+	for _, err := range r.ValidationErrors {
+		name := prefix
+		if err.Attribute != "Base" {
+			name = joinFieldName(prefix, ErrorNameCamelToKebab(err.Attribute))
+		}
+		fields[name] = append(fields[name], err)
+	}
+	for child, sub := range r.Children {
+		name := ErrorNameCamelToKebab(child)
+		if strings.HasPrefix(child, "Index") {
+			name = strings.TrimPrefix(child, "Index")
+		}
+		sub.collectFields(joinFieldName(prefix, name), fields)
+	}
+}
+
+func joinFieldName(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
 type ValidationErrors struct {
 	Object           string
 	ValidationErrors []ValidationError
@@ -280,6 +359,21 @@ func ErrorNameSnakeToCamel(src string) string {
 	return camel.String()
 }
 
+func ErrorNameCamelToKebab(src string) string {
+	kebab := bytes.Buffer{}
+	for i := 0; i < len(src); i++ {
+		k := src[i]
+		if k >= 'A' && k <= 'Z' {
+			if i > 0 {
+				kebab.WriteByte('-')
+			}
+			k += 'a' - 'A'
+		}
+		kebab.WriteByte(k)
+	}
+	return kebab.String()
+}
+
 func ErrorNameKebabToCamel(src string) string {
 	if len(src) == 0 {
 		return ""
@@ -306,16 +400,35 @@ type IAPIError interface {
 	StatusCode() int
 }
 
-type httpError int
+// HTTPError is returned when the gateway answers with an error status code and no error message
+type HTTPError struct {
+	response *Response
+}
 
-func (e httpError) StatusCode() int {
-	return int(e)
+func (e *HTTPError) StatusCode() int {
+	return e.response.StatusCode
 }
 
-func (e httpError) Error() string {
+func (e *HTTPError) Error() string {
 	return fmt.Sprintf("%s (%d)", http.StatusText(e.StatusCode()), e.StatusCode())
 }
 
+func (e *HTTPError) Response() *Response {
+	return e.response
+}
+
+func (e *HTTPError) Path() string {
+	return requestPath(e.response)
+}
+
+func (e *HTTPError) RequestID() string {
+	return requestID(e.response)
+}
+
+func (e *HTTPError) Is(target error) bool {
+	return target != nil && target == statusError(e.StatusCode())
+}
+
 type invalidResponseError struct {
 	resp *Response
 }
@@ -333,6 +446,22 @@ func (e *invalidResponseError) Response() *Response {
 	return e.resp
 }
 
+func (e *invalidResponseError) StatusCode() int {
+	return e.resp.StatusCode
+}
+
+func (e *invalidResponseError) Path() string {
+	return requestPath(e.resp)
+}
+
+func (e *invalidResponseError) RequestID() string {
+	return requestID(e.resp)
+}
+
+func (e *invalidResponseError) Is(target error) bool {
+	return target != nil && target == statusError(e.resp.StatusCode)
+}
+
 // StripNilElements parses the xml input, removing any elements that
 // are decorated with the `nil="true"` attribute returning the XML
 // without those elements.
@@ -381,21 +510,21 @@ func IsTokenNil(src xml.Token) bool {
 }
 
 type VenmoAccount struct {
-	XMLName           xml.Name       `xml:"venmo-account"`
-	CustomerId        string         `xml:"customer-id"`
-	Token             string         `xml:"token"`
-	Username          string         `xml:"username"`
-	VenmoUserID       string         `xml:"venmo-user-id"`
-	SourceDescription string         `xml:"source-description"`
-	ImageURL          string         `xml:"image-url"`
-	CreatedAt         *time.Time     `xml:"created-at"`
-	UpdatedAt         *time.Time     `xml:"updated-at"`
-	Subscriptions     *Subscriptions `xml:"subscriptions"`
-	Default           bool           `xml:"default"`
+	XMLName           xml.Name       `xml:"venmo-account" json:"-"`
+	CustomerId        string         `xml:"customer-id" json:"customer_id"`
+	Token             string         `xml:"token" json:"token"`
+	Username          string         `xml:"username" json:"username"`
+	VenmoUserID       string         `xml:"venmo-user-id" json:"venmo_user_id"`
+	SourceDescription string         `xml:"source-description" json:"source_description"`
+	ImageURL          string         `xml:"image-url" json:"image_url"`
+	CreatedAt         *time.Time     `xml:"created-at" json:"created_at"`
+	UpdatedAt         *time.Time     `xml:"updated-at" json:"updated_at"`
+	Subscriptions     *Subscriptions `xml:"subscriptions" json:"subscriptions"`
+	Default           bool           `xml:"default" json:"default"`
 }
 
 type VenmoAccounts struct {
-	Accounts []*VenmoAccount `xml:"venmo-account"`
+	Accounts []*VenmoAccount `xml:"venmo-account" json:"venmo_account"`
 }
 
 func (v *VenmoAccounts) PaymentMethods() []PaymentMethod {
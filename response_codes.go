@@ -0,0 +1,233 @@
+package braintree
+
+// ResponseCategory groups the processor response codes by the reason of the decline
+type ResponseCategory string
+
+const (
+	ResponseCategoryUnknown           ResponseCategory = ""
+	ResponseCategoryApproved          ResponseCategory = "approved"
+	ResponseCategoryInsufficientFunds ResponseCategory = "insufficient_funds"
+	ResponseCategoryDoNotHonor        ResponseCategory = "do_not_honor"
+	ResponseCategoryExpiredCard       ResponseCategory = "expired_card"
+	ResponseCategoryInvalidCard       ResponseCategory = "invalid_card"
+	ResponseCategoryFraud             ResponseCategory = "fraud"
+	ResponseCategoryNotAllowed        ResponseCategory = "not_allowed"
+	ResponseCategoryIssuerUnavailable ResponseCategory = "issuer_unavailable"
+	ResponseCategoryMerchantSetup     ResponseCategory = "merchant_setup" // the merchant account or the request must be fixed
+)
+
+// ResponseAction is what should be done after a processor response
+type ResponseAction string
+
+const (
+	ResponseActionNone       ResponseAction = "none"
+	ResponseActionRetryLater ResponseAction = "retry_later"  // the same card may be charged again later
+	ResponseActionNewCard    ResponseAction = "ask_new_card" // ask the customer for another payment method
+	ResponseActionNeverRetry ResponseAction = "never_retry"  // charging the card again will fail, or is not allowed by the card networks
+)
+
+// customerMessages are safe to show to the paying customer : they never tell about suspected fraud
+var customerMessages = map[ResponseCategory]string{
+	ResponseCategoryInsufficientFunds: "Your card was declined due to insufficient funds or an exceeded limit. Please use a different payment method.",
+	ResponseCategoryDoNotHonor:        "Your card was declined. Please contact your bank or use a different payment method.",
+	ResponseCategoryExpiredCard:       "Your card has expired. Please use a different card.",
+	ResponseCategoryInvalidCard:       "The card details could not be verified. Please check them or use a different card.",
+	ResponseCategoryFraud:             "Your card was declined. Please use a different payment method.",
+	ResponseCategoryNotAllowed:        "This card cannot be used for this purchase. Please use a different payment method.",
+	ResponseCategoryIssuerUnavailable: "We could not reach your bank. Please try again in a few minutes.",
+	ResponseCategoryMerchantSetup:     "We could not process your payment. Please try again later or contact us.",
+}
+
+type responseCodeInfo struct {
+	text     string
+	category ResponseCategory
+	action   ResponseAction
+}
+
+var responseCodes = map[ResponseCode]responseCodeInfo{
+	1000: {"Approved", ResponseCategoryApproved, ResponseActionNone},
+	1001: {"Approved, check customer ID", ResponseCategoryApproved, ResponseActionNone},
+	1002: {"Processed", ResponseCategoryApproved, ResponseActionNone},
+
+	2000: {"Do Not Honor", ResponseCategoryDoNotHonor, ResponseActionNewCard},
+	2001: {"Insufficient Funds", ResponseCategoryInsufficientFunds, ResponseActionRetryLater},
+	2002: {"Limit Exceeded", ResponseCategoryInsufficientFunds, ResponseActionRetryLater},
+	2003: {"Cardholder's Activity Limit Exceeded", ResponseCategoryInsufficientFunds, ResponseActionRetryLater},
+	2004: {"Expired Card", ResponseCategoryExpiredCard, ResponseActionNewCard},
+	2005: {"Invalid Credit Card Number", ResponseCategoryInvalidCard, ResponseActionNewCard},
+	2006: {"Invalid Expiration Date", ResponseCategoryInvalidCard, ResponseActionNewCard},
+	2007: {"No Account", ResponseCategoryInvalidCard, ResponseActionNewCard},
+	2008: {"Card Account Length Error", ResponseCategoryInvalidCard, ResponseActionNewCard},
+	2009: {"No Such Issuer", ResponseCategoryInvalidCard, ResponseActionNewCard},
+	2010: {"Card Issuer Declined CVV", ResponseCategoryInvalidCard, ResponseActionNewCard},
+	2011: {"Voice Authorization Required", ResponseCategoryDoNotHonor, ResponseActionNeverRetry},
+	2012: {"Processor Declined - Possible Lost Card", ResponseCategoryFraud, ResponseActionNeverRetry},
+	2013: {"Processor Declined - Possible Stolen Card", ResponseCategoryFraud, ResponseActionNeverRetry},
+	2014: {"Processor Declined - Fraud Suspected", ResponseCategoryFraud, ResponseActionNeverRetry},
+	2015: {"Transaction Not Allowed", ResponseCategoryNotAllowed, ResponseActionNewCard},
+	2016: {"Duplicate Transaction", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2017: {"Cardholder Stopped Billing", ResponseCategoryDoNotHonor, ResponseActionNeverRetry},
+	2018: {"Cardholder Stopped All Billing", ResponseCategoryDoNotHonor, ResponseActionNeverRetry},
+	2019: {"Invalid Transaction", ResponseCategoryNotAllowed, ResponseActionNewCard},
+	2020: {"Violation", ResponseCategoryFraud, ResponseActionNeverRetry},
+	2021: {"Security Violation", ResponseCategoryFraud, ResponseActionNeverRetry},
+	2022: {"Declined - Updated Cardholder Available", ResponseCategoryExpiredCard, ResponseActionNewCard},
+	2023: {"Processor Does Not Support This Feature", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2024: {"Card Type Not Enabled", ResponseCategoryMerchantSetup, ResponseActionNewCard},
+	2025: {"Set Up Error - Merchant", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2026: {"Invalid Merchant ID", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2027: {"Set Up Error - Amount", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2028: {"Set Up Error - Hierarchy", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2029: {"Set Up Error - Card", ResponseCategoryMerchantSetup, ResponseActionNewCard},
+	2030: {"Set Up Error - Terminal", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2031: {"Encryption Error", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2032: {"Surcharge Not Permitted", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2033: {"Inconsistent Data", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2034: {"No Action Taken", ResponseCategoryDoNotHonor, ResponseActionRetryLater},
+	2035: {"Partial Approval For Amount In Group III Version", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2036: {"Authorization could not be found", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2037: {"Already Reversed", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2038: {"Processor Declined", ResponseCategoryDoNotHonor, ResponseActionNewCard},
+	2039: {"Invalid Authorization Code", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2040: {"Invalid Store", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2041: {"Declined - Call For Approval", ResponseCategoryDoNotHonor, ResponseActionNewCard},
+	2042: {"Invalid Client ID", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2043: {"Error - Do Not Retry, Call Issuer", ResponseCategoryDoNotHonor, ResponseActionNeverRetry},
+	2044: {"Declined - Call Issuer", ResponseCategoryDoNotHonor, ResponseActionNewCard},
+	2045: {"Invalid Merchant Number", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2046: {"Declined", ResponseCategoryDoNotHonor, ResponseActionNewCard},
+	2047: {"Call Issuer. Pick Up Card.", ResponseCategoryFraud, ResponseActionNeverRetry},
+	2048: {"Invalid Amount", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2049: {"Invalid SKU Number", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2050: {"Invalid Credit Plan", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2051: {"Credit Card Number does not match method of payment", ResponseCategoryInvalidCard, ResponseActionNewCard},
+	2053: {"Card reported as lost or stolen", ResponseCategoryFraud, ResponseActionNeverRetry},
+	2054: {"Reversal amount does not match authorization amount", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2055: {"Invalid Transaction Division Number", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2056: {"Transaction amount exceeds the transaction division limit", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2057: {"Issuer or Cardholder has put a restriction on the card", ResponseCategoryNotAllowed, ResponseActionNewCard},
+	2058: {"Merchant not Mastercard SecureCode enabled", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2059: {"Address Verification Failed", ResponseCategoryInvalidCard, ResponseActionNewCard},
+	2060: {"Address Verification and Card Security Code Failed", ResponseCategoryInvalidCard, ResponseActionNewCard},
+	2061: {"Invalid Transaction Data", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2062: {"Invalid Tax Amount", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2063: {"PayPal Business Account preference resulted in the transaction failing", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2064: {"Invalid Currency Code", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2065: {"Refund Time Limit Exceeded", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2066: {"PayPal Business Account Restricted", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2067: {"Authorization Expired", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2068: {"PayPal Business Account Locked or Closed", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2069: {"PayPal Blocking Duplicate Order IDs", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2070: {"PayPal Buyer Revoked Pre-Approved Payment Authorization", ResponseCategoryDoNotHonor, ResponseActionNeverRetry},
+	2071: {"PayPal Payee Account Invalid Or Does Not Have a Confirmed Email", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2072: {"PayPal Payee Email Incorrectly Formatted", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2073: {"PayPal Validation Error", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2074: {"Funding Instrument In The PayPal Account Was Declined By The Processor Or Bank", ResponseCategoryDoNotHonor, ResponseActionNewCard},
+	2075: {"Payer Account Is Locked Or Closed", ResponseCategoryDoNotHonor, ResponseActionNewCard},
+	2076: {"Payer Cannot Pay For This Transaction With PayPal", ResponseCategoryDoNotHonor, ResponseActionNewCard},
+	2077: {"Transaction Refused Due To PayPal Risk Model", ResponseCategoryFraud, ResponseActionNeverRetry},
+	2079: {"PayPal Merchant Account Configuration Error", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2081: {"PayPal pending payments are not supported", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2082: {"PayPal Domestic Transaction Required", ResponseCategoryNotAllowed, ResponseActionNewCard},
+	2083: {"PayPal Phone Number Required", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2084: {"PayPal Tax Info Required", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2085: {"PayPal Payee Blocked Transaction", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2086: {"PayPal Transaction Limit Exceeded", ResponseCategoryNotAllowed, ResponseActionRetryLater},
+	2087: {"PayPal reference transactions not enabled for your account", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2088: {"Currency not enabled for your PayPal seller account", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2089: {"PayPal payee email permission denied for this request", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2090: {"PayPal account not configured to refund more than settled amount", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2091: {"Currency of this transaction must match currency of your PayPal account", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2092: {"No Data Found - Try Another Verification Method", ResponseCategoryInvalidCard, ResponseActionNewCard},
+	2093: {"PayPal payment method is invalid", ResponseCategoryInvalidCard, ResponseActionNewCard},
+	2094: {"PayPal payment has already been completed", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2095: {"PayPal refund is not allowed after partial refund", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2096: {"PayPal buyer account can't be the same as the seller account", ResponseCategoryNotAllowed, ResponseActionNewCard},
+	2097: {"PayPal authorization amount limit exceeded", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+	2098: {"PayPal authorization count limit exceeded", ResponseCategoryMerchantSetup, ResponseActionNeverRetry},
+
+	3000: {"Processor Network Unavailable - Try Again", ResponseCategoryIssuerUnavailable, ResponseActionRetryLater},
+}
+
+// lookup returns the catalog entry of the code. Codes which are not in the catalog are classified by
+// their range : 1000-1999 are approvals, 2000-2999 declines and 3000-3999 processor outages
+func (rc ResponseCode) lookup() responseCodeInfo {
+	if info, ok := responseCodes[rc]; ok {
+		return info
+	}
+	switch {
+	case rc >= 1000 && rc < 2000:
+		return responseCodeInfo{category: ResponseCategoryApproved, action: ResponseActionNone}
+	case rc >= 2000 && rc < 3000:
+		return responseCodeInfo{category: ResponseCategoryDoNotHonor, action: ResponseActionNewCard}
+	case rc >= 3000 && rc < 4000:
+		return responseCodeInfo{category: ResponseCategoryIssuerUnavailable, action: ResponseActionRetryLater}
+	}
+	return responseCodeInfo{}
+}
+
+// Known reports whether the code is in the catalog
+func (rc ResponseCode) Known() bool {
+	_, ok := responseCodes[rc]
+	return ok
+}
+
+// Text returns the processor response text of the code, or an empty string for unknown codes
+func (rc ResponseCode) Text() string {
+	return responseCodes[rc].text
+}
+
+func (rc ResponseCode) Category() ResponseCategory {
+	return rc.lookup().category
+}
+
+// Action returns what should be done after the response, an empty action for codes outside the 1000-3999 range
+func (rc ResponseCode) Action() ResponseAction {
+	return rc.lookup().action
+}
+
+// CustomerMessage returns a message which can be shown to the customer, empty for approvals
+func (rc ResponseCode) CustomerMessage() string {
+	return customerMessages[rc.Category()]
+}
+
+// Type tells apart the declines which can succeed on a later attempt (soft) from the ones which will not (hard)
+func (rc ResponseCode) Type() ResponseType {
+	info := rc.lookup()
+	switch {
+	case info.category == ResponseCategoryApproved:
+		return ResponseTypeApproved
+	case info.action == ResponseActionNeverRetry, info.category == ResponseCategoryExpiredCard, info.category == ResponseCategoryInvalidCard:
+		return ResponseTypeHardDeclined
+	case info.category == ResponseCategoryUnknown:
+		return ""
+	}
+	return ResponseTypeSoftDeclined
+}
+
+// Retryable reports whether charging the same payment method again later may succeed
+func (rc ResponseCode) Retryable() bool {
+	return rc.Action() == ResponseActionRetryLater
+}
+
+func (t *Tx) ResponseCategory() ResponseCategory {
+	if t == nil {
+		return ResponseCategoryUnknown
+	}
+	return t.ProcessorResponseCode.Category()
+}
+
+func (t *Tx) ResponseAction() ResponseAction {
+	if t == nil {
+		return ""
+	}
+	return t.ProcessorResponseCode.Action()
+}
+
+// CustomerMessage returns the message to be shown to the customer for the processor response of the transaction
+func (t *Tx) CustomerMessage() string {
+	if t == nil {
+		return ""
+	}
+	return t.ProcessorResponseCode.CustomerMessage()
+}
@@ -0,0 +1,174 @@
+package braintree
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy describes how APIClient.call retries transient failures : timeouts, connections refused or
+// reset, and 429, 502, 503 or 504 responses. GETs and HEADs are always retried, the other verbs, which
+// change the state of the gateway, only when the caller allowed it (see WithUnsafeRetries).
+type RetryPolicy struct {
+	MaxAttempts int           // total number of attempts, including the first one
+	BaseDelay   time.Duration // delay before the second attempt, doubled on each subsequent one
+	MaxDelay    time.Duration // upper bound for a single delay, including the one requested by Retry-After, none if 0
+	Jitter      float64       // fraction of the delay which is randomized, between 0 and 1
+}
+
+var DefaultRetryPolicy = &RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   250 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+	Jitter:      0.2,
+}
+
+const HdrRetryAfter = "Retry-After"
+
+var (
+	jitterMu   sync.Mutex
+	jitterRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+func (p *RetryPolicy) attempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// backoff returns the delay to wait before the given attempt (starting with 2 for the first retry)
+func (p *RetryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	delay := p.BaseDelay
+	for i := 2; i < attempt; i++ {
+		if p.MaxDelay > 0 && delay >= p.MaxDelay || delay > math.MaxInt64/2 {
+			break
+		}
+		delay *= 2
+	}
+	if p.Jitter > 0 && delay > 0 {
+		jitterMu.Lock()
+		spread := float64(delay) * p.Jitter
+		delay = delay - time.Duration(spread) + time.Duration(jitterRand.Float64()*2*spread)
+		jitterMu.Unlock()
+	}
+	if retryAfter > delay {
+		delay = retryAfter
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// RetryError is returned when a call failed after more than one attempt
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("%v (after %d attempts)", e.Err, e.Attempts)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+func (e *RetryError) StatusCode() int {
+	if apiErr, ok := e.Err.(IAPIError); ok {
+		return apiErr.StatusCode()
+	}
+	return 0
+}
+
+type unsafeRetries struct{}
+
+// WithUnsafeRetries allows the calls made with the returned context to be retried even if they change the state
+// of the gateway, like Pay or Refund. The gateway does not deduplicate the requests : when the attempt which timed
+// out or failed actually reached it, the retry repeats the operation, charging or refunding twice. Only use it
+// for the calls which are safe to repeat
+func WithUnsafeRetries(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unsafeRetries{}, true)
+}
+
+// UnsafeRetries reports whether the context allows retrying the calls which change the state of the gateway
+func UnsafeRetries(ctx context.Context) bool {
+	allowed, _ := ctx.Value(unsafeRetries{}).(bool)
+	return allowed
+}
+
+func isRetryable(ctx context.Context, method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		return true
+	}
+	return UnsafeRetries(ctx)
+}
+
+// isTransientError reports whether the transport error is worth retrying : timeouts, and connections refused,
+// reset or closed before the response was read. The context errors are not
+func isTransientError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses the Retry-After header, which can be either a number of seconds or a http date
+func retryAfter(response *http.Response) time.Duration {
+	if response == nil {
+		return 0
+	}
+	value := strings.TrimSpace(response.Header.Get(HdrRetryAfter))
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// sleep waits for the delay to pass, returning false if the context is done before or the delay would exceed its deadline
+func sleep(ctx context.Context, delay time.Duration) bool {
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+		return false
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,257 @@
+package braintree
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SearchResultLimit is the number of ids past which the gateway truncates the results of a search
+const SearchResultLimit = 50000
+
+// ErrCheckpointMismatch is returned when the checkpoint stored under the key of a scan is the one of another
+// query or range : delete it to start over
+var ErrCheckpointMismatch = errors.New("braintree: checkpoint of another scan")
+
+// Checkpoint is the progress of a TxScanner : the window of creation times being read, its ids and the
+// number of their pages already handed out
+type Checkpoint struct {
+	QueryHash   string    `json:"query_hash"`
+	WindowStart time.Time `json:"window_start"`
+	WindowEnd   time.Time `json:"window_end"` // zero until the window is searched
+	IDs         []string  `json:"ids,omitempty"`
+	PageSize    int       `json:"page_size,omitempty"`
+	Page        int       `json:"page"`
+}
+
+// CheckpointStore persists the checkpoints of the scans by their key
+type CheckpointStore interface {
+	// Load returns nil, without error, when there is no checkpoint for the key
+	Load(ctx context.Context, key string) (*Checkpoint, error)
+	Save(ctx context.Context, key string, checkpoint *Checkpoint) error
+	Delete(ctx context.Context, key string) error
+}
+
+// FileCheckpointStore keeps each checkpoint in a JSON file of the directory, replaced atomically
+type FileCheckpointStore struct {
+	Dir string
+}
+
+func (s *FileCheckpointStore) path(key string) string {
+	return filepath.Join(s.Dir, url.PathEscape(key)+".json")
+}
+
+func (s *FileCheckpointStore) Load(_ context.Context, key string) (*Checkpoint, error) {
+	b, err := ioutil.ReadFile(s.path(key))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(b, &checkpoint); err != nil {
+		return nil, fmt.Errorf("checkpoint %s : %w", key, err)
+	}
+	return &checkpoint, nil
+}
+
+func (s *FileCheckpointStore) Save(_ context.Context, key string, checkpoint *Checkpoint) error {
+	b, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(s.Dir, url.PathEscape(key)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), s.path(key))
+}
+
+func (s *FileCheckpointStore) Delete(_ context.Context, key string) error {
+	err := os.Remove(s.path(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// TxScanner reads the transactions created in [From, To) and matching the query, resuming after a crash or an
+// error from its checkpoint. The range is searched in windows of creation time, a window being halved until
+// it has less than Limit results, so that no transaction is lost to the truncation of the results by the
+// gateway. The checkpoint is saved after each page, and deleted once the scan is done : the transactions of
+// the page being read when the scan stopped are handed out again when it resumes.
+//
+//	s := &TxScanner{Client: c, Store: &FileCheckpointStore{Dir: dir}, Key: "settled-2025", Query: query, From: from, To: to}
+//	err := s.Scan(ctx, func(tx *Tx) error {
+//		return export(tx)
+//	})
+type TxScanner struct {
+	Client *APIClient
+	Store  CheckpointStore
+	// Key is the name of the checkpoint of the scan in the store
+	Key string
+	// Query are the criteria of the transactions, its created-at criteria being replaced by the windows
+	Query    *Search
+	From, To time.Time
+	// Window is the length of the windows searched, a day when not set
+	Window time.Duration
+	// Limit is the number of ids at which a window is considered truncated, SearchResultLimit when not set
+	Limit int
+	// Concurrency is the number of pages fetched ahead, see TxIterator
+	Concurrency int
+}
+
+// Scan calls fn with each transaction, stopping on the first error it returns
+func (s *TxScanner) Scan(ctx context.Context, fn func(tx *Tx) error) error {
+	hash, err := s.queryHash()
+	if err != nil {
+		return err
+	}
+	checkpoint, err := s.Store.Load(ctx, s.Key)
+	if err != nil {
+		return err
+	}
+	if checkpoint == nil {
+		checkpoint = &Checkpoint{QueryHash: hash, WindowStart: s.From}
+	} else if checkpoint.QueryHash != hash {
+		return fmt.Errorf("%w : %s", ErrCheckpointMismatch, s.Key)
+	}
+
+	for checkpoint.WindowStart.Before(s.To) {
+		if checkpoint.WindowEnd.IsZero() {
+			if err := s.searchWindow(ctx, checkpoint); err != nil {
+				return err
+			}
+			if err := s.Store.Save(ctx, s.Key, checkpoint); err != nil {
+				return err
+			}
+		}
+		if err := s.readWindow(ctx, checkpoint, fn); err != nil {
+			return err
+		}
+		checkpoint.WindowStart, checkpoint.WindowEnd = checkpoint.WindowEnd, time.Time{}
+		checkpoint.IDs, checkpoint.PageSize, checkpoint.Page = nil, 0, 0
+		if err := s.Store.Save(ctx, s.Key, checkpoint); err != nil {
+			return err
+		}
+	}
+	return s.Store.Delete(ctx, s.Key)
+}
+
+func (s *TxScanner) queryHash() (string, error) {
+	query := s.Query
+	if query == nil {
+		query = &Search{}
+	}
+	b, err := xml.Marshal(query)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	h.Write(b)
+	fmt.Fprintf(h, "\n%s\n%s", s.From.UTC().Format(time.RFC3339Nano), s.To.UTC().Format(time.RFC3339Nano))
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// windowQuery restricts the query to the creation times in [start, end), the gateway searching by seconds
+func (s *TxScanner) windowQuery(start, end time.Time) *Search {
+	query := &Search{}
+	if s.Query != nil {
+		query = s.Query.ShallowCopy()
+	}
+	createdAt := query.AddTimeField("created-at")
+	createdAt.Min, createdAt.Max = start, end.Add(-time.Second)
+	return query
+}
+
+// searchWindow finds the ids of the window starting the checkpoint, halving it while the results are truncated
+func (s *TxScanner) searchWindow(ctx context.Context, checkpoint *Checkpoint) error {
+	window, limit := s.Window, s.Limit
+	if window <= 0 {
+		window = 24 * time.Hour
+	}
+	if limit <= 0 {
+		limit = SearchResultLimit
+	}
+	start := checkpoint.WindowStart
+	for {
+		end := start.Add(window)
+		if end.After(s.To) {
+			end = s.To
+		}
+		result, err := s.Client.SearchTxs(ctx, s.windowQuery(start, end))
+		if err != nil {
+			return err
+		}
+		if len(result.IDs) < limit {
+			checkpoint.WindowEnd, checkpoint.IDs, checkpoint.PageSize, checkpoint.Page = end, result.IDs, result.PageSize, 0
+			return nil
+		}
+		if end.Sub(start) <= time.Second {
+			return fmt.Errorf("scan %s : %d transactions or more created at %s", s.Key, limit, start.Format(time.RFC3339))
+		}
+		window = (end.Sub(start) / 2).Truncate(time.Second)
+		if window < time.Second {
+			window = time.Second
+		}
+	}
+}
+
+// readWindow hands out the transactions of the pages not read yet, saving the checkpoint after each page
+func (s *TxScanner) readWindow(ctx context.Context, checkpoint *Checkpoint, fn func(tx *Tx) error) error {
+	if len(checkpoint.IDs) == 0 {
+		return nil
+	}
+	query := s.windowQuery(checkpoint.WindowStart, checkpoint.WindowEnd)
+	pages := pageIterator{
+		fetch: func(ctx context.Context, ids []string) (interface{}, error) {
+			return s.Client.FetchTx(ctx, pagedQuery(query, ids))
+		},
+		Concurrency: s.Concurrency,
+		result: &SearchResult{
+			PageSize:  checkpoint.PageSize,
+			PageCount: (len(checkpoint.IDs) + checkpoint.PageSize - 1) / checkpoint.PageSize,
+			IDs:       checkpoint.IDs,
+		},
+		launched: checkpoint.Page,
+	}
+	for {
+		records, ok := pages.next(ctx)
+		if !ok {
+			return pages.Err()
+		}
+		for _, tx := range records.([]*Tx) {
+			if err := fn(tx); err != nil {
+				return err
+			}
+		}
+		checkpoint.Page++
+		if err := s.Store.Save(ctx, s.Key, checkpoint); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package braintree
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// InvalidSearchError lists the criteria of a search builder which the gateway would reject or ignore
+type InvalidSearchError struct {
+	Problems []string
+}
+
+func (e *InvalidSearchError) Error() string {
+	return "invalid search : " + strings.Join(e.Problems, "; ")
+}
+
+// criteria is the common part of the search builders : it adds the fields to the search and collects the
+// problems of the values given, to be reported all at once when the search is built
+type criteria struct {
+	search   Search
+	problems []string
+}
+
+func (c *criteria) problem(format string, args ...interface{}) {
+	c.problems = append(c.problems, fmt.Sprintf(format, args...))
+}
+
+// build returns a copy of the search, unless the values given had problems or the ones found by the builder
+// in their combination, which are not kept
+func (c *criteria) build(problems ...string) (*Search, error) {
+	if len(c.problems) > 0 || len(problems) > 0 {
+		return nil, &InvalidSearchError{Problems: append(append([]string(nil), c.problems...), problems...)}
+	}
+	// the builder may still change its fields afterwards
+	return c.search.deepCopy(), nil
+}
+
+// text returns the field, creating it when needed, so that several operators can be set on it
+func (c *criteria) text(name string) *TextField {
+	if i, ok := c.search.fieldIndex[name]; ok {
+		if f, ok := c.search.fields[i].(*TextField); ok {
+			return f
+		}
+	}
+	return c.search.AddTextField(name)
+}
+
+func (c *criteria) timeRange(name string, min, max time.Time) {
+	if min.IsZero() && max.IsZero() {
+		c.problem("%s : at least one of min and max is required", name)
+		return
+	}
+	if !min.IsZero() && !max.IsZero() && min.After(max) {
+		c.problem("%s : min %s is after max %s", name, min.Format(time.RFC3339), max.Format(time.RFC3339))
+		return
+	}
+	f := c.search.AddTimeField(name)
+	f.Min, f.Max = min, max
+}
+
+func (c *criteria) decimalRange(name string, min, max *Decimal) {
+	if min == nil && max == nil {
+		c.problem("%s : at least one of min and max is required", name)
+		return
+	}
+	if (min != nil && min.Unscaled < 0) || (max != nil && max.Unscaled < 0) {
+		c.problem("%s : cannot be negative", name)
+		return
+	}
+	if min != nil && max != nil && min.Cmp(max) > 0 {
+		c.problem("%s : min %s is greater than max %s", name, min, max)
+		return
+	}
+	f := c.search.AddRangeField(name)
+	if min != nil {
+		f.MinText = min.String()
+	}
+	if max != nil {
+		f.MaxText = max.String()
+	}
+}
+
+func (c *criteria) intRange(name string, min, max *int) {
+	if min == nil && max == nil {
+		c.problem("%s : at least one of min and max is required", name)
+		return
+	}
+	if (min != nil && *min < 0) || (max != nil && *max < 0) {
+		c.problem("%s : cannot be negative", name)
+		return
+	}
+	if min != nil && max != nil && *min > *max {
+		c.problem("%s : min %d is greater than max %d", name, *min, *max)
+		return
+	}
+	f := c.search.AddRangeField(name)
+	if min != nil {
+		f.MinText = strconv.Itoa(*min)
+	}
+	if max != nil {
+		f.MaxText = strconv.Itoa(*max)
+	}
+}
+
+// bin searches the card numbers starting with the 6 or 8 digits bank identification number
+func (c *criteria) bin(name, bin string) {
+	if !digits(bin) || (len(bin) != 6 && len(bin) != 8) {
+		c.problem("%s : bin %q must be 6 or 8 digits", name, bin)
+		return
+	}
+	c.text(name).StartsWith = bin
+}
+
+func (c *criteria) last4(name, last4 string) {
+	if !digits(last4) || len(last4) != 4 {
+		c.problem("%s : last 4 %q must be 4 digits", name, last4)
+		return
+	}
+	c.text(name).EndsWith = last4
+}
+
+// multi adds a multiple value field, checking the values against the allowed ones when given
+func (c *criteria) multi(name string, allowed []string, values []string) {
+	if len(values) == 0 {
+		c.problem("%s : at least one value is required", name)
+		return
+	}
+	for _, value := range values {
+		if value == "" {
+			c.problem("%s : empty value", name)
+			return
+		}
+		if allowed != nil && !contains(allowed, value) {
+			c.problem("%s : unknown value %q, expecting one of %s", name, value, strings.Join(allowed, ", "))
+			return
+		}
+	}
+	c.search.AddMultiField(name).Items = append([]string(nil), values...)
+}
+
+func (c *criteria) keyValue(name string, value bool) {
+	c.search.AddKeyValueField(name).Value = strconv.FormatBool(value)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func digits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,499 @@
+package braintree
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ParseError is returned by ParseSearch for a query it cannot read. Pos is the byte offset of the problem.
+type ParseError struct {
+	Query string
+	Pos   int
+	Msg   string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("invalid search query at %d : %s", e.Pos, e.Msg)
+}
+
+// ParseSearch reads a search written as criteria joined by "and", the field names being the ones of the
+// gateway with underscores or dashes :
+//
+//	status in (settled, settling) and amount between 10.00 and 50 and created_at >= 2026-01-01 and
+//	customer_email ends_with "@acme.com"
+//
+// The operators are = (or is), != (or is_not), starts_with, ends_with and contains for text, in for the
+// multiple value fields, and =, >=, <= and between for the numbers and dates, the bounds being inclusive.
+// Dates are written as 2006-01-02 or in RFC 3339, a date without a time covering the whole day : its max is
+// the last second of the day, and = searches the day between its first and last second. A number or a date
+// compared with = is searched as a range, true and false as a key value field : quote them to search them as
+// text. Several criteria on the same field are merged, like "amount >= 10 and amount <= 50".
+func ParseSearch(query string) (*Search, error) {
+	p := &queryParser{query: query}
+	p.read()
+	search := &Search{}
+	if p.token.kind == tokenEnd {
+		return search, nil
+	}
+	for {
+		if err := p.criterion(search); err != nil {
+			return nil, err
+		}
+		if p.token.kind == tokenEnd {
+			return search, nil
+		}
+		if !p.keyword("and") {
+			return nil, p.fail(p.token.pos, "expected and, found %s", p.token)
+		}
+		p.read()
+	}
+}
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOperator
+	tokenOpen
+	tokenClose
+	tokenComma
+	tokenInvalid
+)
+
+type queryToken struct {
+	kind  tokenKind
+	pos   int
+	text  string // the unquoted value of strings
+	input string // as written in the query
+}
+
+func (t queryToken) String() string {
+	switch t.kind {
+	case tokenEnd:
+		return "the end of the query"
+	case tokenInvalid:
+		return "the unterminated or invalid string " + t.input
+	}
+	return strconv.Quote(t.input)
+}
+
+type queryParser struct {
+	query string
+	pos   int
+	token queryToken
+}
+
+func (p *queryParser) fail(pos int, format string, args ...interface{}) error {
+	return &ParseError{Query: p.query, Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// read moves to the next token
+func (p *queryParser) read() {
+	for p.pos < len(p.query) && isQuerySpace(p.query[p.pos]) {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos == len(p.query) {
+		p.token = queryToken{kind: tokenEnd, pos: start}
+		return
+	}
+	kind := tokenWord
+	switch c := p.query[p.pos]; {
+	case c == '(':
+		kind = tokenOpen
+		p.pos++
+	case c == ')':
+		kind = tokenClose
+		p.pos++
+	case c == ',':
+		kind = tokenComma
+		p.pos++
+	case c == '"':
+		p.pos++
+		for p.pos < len(p.query) && p.query[p.pos] != '"' {
+			if p.query[p.pos] == '\\' {
+				p.pos++
+			}
+			p.pos++
+		}
+		if p.pos >= len(p.query) {
+			p.pos = len(p.query)
+			p.token = queryToken{kind: tokenInvalid, pos: start, input: p.query[start:]}
+			return
+		}
+		p.pos++
+		text, err := strconv.Unquote(p.query[start:p.pos])
+		if err != nil {
+			p.token = queryToken{kind: tokenInvalid, pos: start, input: p.query[start:p.pos]}
+			return
+		}
+		p.token = queryToken{kind: tokenString, pos: start, text: text, input: p.query[start:p.pos]}
+		return
+	case isQueryOperator(c):
+		kind = tokenOperator
+		for p.pos < len(p.query) && isQueryOperator(p.query[p.pos]) {
+			p.pos++
+		}
+	default:
+		for p.pos < len(p.query) && !isQuerySpace(p.query[p.pos]) && !isQueryDelimiter(p.query[p.pos]) {
+			p.pos++
+		}
+	}
+	input := p.query[start:p.pos]
+	p.token = queryToken{kind: kind, pos: start, text: input, input: input}
+}
+
+func isQuerySpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isQueryOperator(c byte) bool {
+	return c == '=' || c == '!' || c == '<' || c == '>'
+}
+
+func isQueryDelimiter(c byte) bool {
+	return c == '(' || c == ')' || c == ',' || c == '"' || isQueryOperator(c)
+}
+
+func (p *queryParser) keyword(keyword string) bool {
+	return p.token.kind == tokenWord && strings.EqualFold(p.token.text, keyword)
+}
+
+// criterion reads a field, an operator and its values, and adds them to the search
+func (p *queryParser) criterion(search *Search) error {
+	if p.token.kind != tokenWord {
+		return p.fail(p.token.pos, "expected a field name, found %s", p.token)
+	}
+	field := p.token
+	name := strings.Replace(strings.ToLower(field.text), "_", "-", -1)
+	p.read()
+
+	op := p.token
+	operator := strings.ToLower(op.text)
+	if op.kind != tokenOperator && op.kind != tokenWord {
+		return p.fail(op.pos, "expected an operator after %s, found %s", field, op)
+	}
+	p.read()
+
+	switch operator {
+	case "in":
+		return p.multi(search, field, name)
+	case "between":
+		min, err := p.bound()
+		if err != nil {
+			return err
+		}
+		if !p.keyword("and") {
+			return p.fail(p.token.pos, "expected and between the bounds, found %s", p.token)
+		}
+		p.read()
+		max, err := p.bound()
+		if err != nil {
+			return err
+		}
+		if min.isTime != max.isTime {
+			return p.fail(max.pos, "cannot mix a number and a date in the bounds of %s", field.text)
+		}
+		if min.isTime && min.time.After(max.time) || !min.isTime && min.number.Cmp(max.number) > 0 {
+			return p.fail(min.pos, "the min of %s is greater than its max", field.text)
+		}
+		if err := p.setBound(search, field, name, ">=", min); err != nil {
+			return err
+		}
+		return p.setBound(search, field, name, "<=", max)
+	case ">=", "<=":
+		value, err := p.bound()
+		if err != nil {
+			return err
+		}
+		return p.setBound(search, field, name, operator, value)
+	case ">", "<":
+		return p.fail(op.pos, "the bounds are inclusive, use %s=", operator)
+	case "=", "is":
+		if p.token.kind == tokenWord {
+			if p.keyword("true") || p.keyword("false") {
+				if _, exists := search.fieldIndex[name]; exists {
+					return p.fail(field.pos, "%s is already searched", field.text)
+				}
+				search.AddKeyValueField(name).Value = strings.ToLower(p.token.text)
+				p.read()
+				return nil
+			}
+			if value, ok := p.parseBound(p.token); ok {
+				p.read()
+				return p.setBound(search, field, name, "=", value)
+			}
+		}
+		return p.text(search, field, name, "=")
+	case "!=", "is_not", "starts_with", "ends_with", "contains":
+		return p.text(search, field, name, operator)
+	}
+	return p.fail(op.pos, "unknown operator %s", op)
+}
+
+func (p *queryParser) multi(search *Search, field queryToken, name string) error {
+	if p.token.kind != tokenOpen {
+		return p.fail(p.token.pos, "expected ( after in, found %s", p.token)
+	}
+	p.read()
+	var items []string
+	for {
+		if p.token.kind != tokenWord && p.token.kind != tokenString {
+			return p.fail(p.token.pos, "expected a value, found %s", p.token)
+		}
+		items = append(items, p.token.text)
+		p.read()
+		if p.token.kind == tokenClose {
+			p.read()
+			break
+		}
+		if p.token.kind != tokenComma {
+			return p.fail(p.token.pos, "expected , or ), found %s", p.token)
+		}
+		p.read()
+	}
+	if _, exists := search.fieldIndex[name]; exists {
+		return p.fail(field.pos, "%s is already searched", field.text)
+	}
+	search.AddMultiField(name).Items = items
+	return nil
+}
+
+func (p *queryParser) text(search *Search, field queryToken, name, operator string) error {
+	if p.token.kind != tokenWord && p.token.kind != tokenString {
+		return p.fail(p.token.pos, "expected a value, found %s", p.token)
+	}
+	value := p.token
+	p.read()
+
+	var f *TextField
+	if i, exists := search.fieldIndex[name]; exists {
+		text, ok := search.fields[i].(*TextField)
+		if !ok {
+			return p.fail(field.pos, "%s is already searched as %s", field.text, searchFieldKind(search.fields[i]))
+		}
+		f = text
+	} else {
+		f = search.AddTextField(name)
+	}
+	var target *string
+	switch operator {
+	case "=":
+		target = &f.Is
+	case "!=", "is_not":
+		target = &f.IsNot
+	case "starts_with":
+		target = &f.StartsWith
+	case "ends_with":
+		target = &f.EndsWith
+	case "contains":
+		target = &f.Contains
+	}
+	if *target != "" {
+		return p.fail(field.pos, "%s %s is already searched", field.text, operator)
+	}
+	*target = value.text
+	return nil
+}
+
+type queryBound struct {
+	pos    int
+	isTime bool
+	isDate bool // a time without its time of day, covering the whole day
+	time   time.Time
+	number *Decimal
+}
+
+func (p *queryParser) bound() (queryBound, error) {
+	value, ok := p.parseBound(p.token)
+	if !ok {
+		return queryBound{}, p.fail(p.token.pos, "expected a number or a date, found %s", p.token)
+	}
+	p.read()
+	return value, nil
+}
+
+func (p *queryParser) parseBound(token queryToken) (queryBound, bool) {
+	if token.kind != tokenWord {
+		return queryBound{}, false
+	}
+	if t, err := time.Parse(DateFormat, token.text); err == nil {
+		return queryBound{pos: token.pos, isTime: true, isDate: true, time: t}, true
+	}
+	if t, err := time.Parse(time.RFC3339, token.text); err == nil {
+		return queryBound{pos: token.pos, isTime: true, time: t}, true
+	}
+	number := &Decimal{}
+	if err := number.UnmarshalText([]byte(token.text)); err == nil {
+		return queryBound{pos: token.pos, number: number}, true
+	}
+	return queryBound{}, false
+}
+
+// setBound sets the min (>=), the max (<=) or the exact value (=) of a range or time field
+func (p *queryParser) setBound(search *Search, field queryToken, name, operator string, value queryBound) error {
+	i, exists := search.fieldIndex[name]
+	if value.isTime {
+		var f *TimeField
+		if !exists {
+			f = search.AddTimeField(name)
+		} else if times, ok := search.fields[i].(*TimeField); ok {
+			f = times
+		} else {
+			return p.fail(field.pos, "%s is already searched as %s", field.text, searchFieldKind(search.fields[i]))
+		}
+		bounds := map[string]time.Time{operator: value.time}
+		if value.isDate {
+			switch operator {
+			case "=":
+				bounds = map[string]time.Time{">=": value.time, "<=": endOfDay(value.time)}
+			case "<=":
+				bounds[operator] = endOfDay(value.time)
+			}
+		}
+		targets := map[string]*time.Time{"=": &f.Is, ">=": &f.Min, "<=": &f.Max}
+		for op := range bounds {
+			if !targets[op].IsZero() {
+				return p.fail(field.pos, "%s %s is already searched", field.text, operator)
+			}
+		}
+		for op, t := range bounds {
+			*targets[op] = t
+		}
+		return nil
+	}
+	var f *RangeField
+	if !exists {
+		f = search.AddRangeField(name)
+	} else if numbers, ok := search.fields[i].(*RangeField); ok {
+		f = numbers
+	} else {
+		return p.fail(field.pos, "%s is already searched as %s", field.text, searchFieldKind(search.fields[i]))
+	}
+	target := map[string]*string{"=": &f.IsText, ">=": &f.MinText, "<=": &f.MaxText}[operator]
+	if *target != "" {
+		return p.fail(field.pos, "%s %s is already searched", field.text, operator)
+	}
+	*target = value.number.String()
+	return nil
+}
+
+func searchFieldKind(field interface{}) string {
+	switch field.(type) {
+	case *TextField:
+		return "a text"
+	case *RangeField:
+		return "a number"
+	case *TimeField:
+		return "a date"
+	case *MultiField:
+		return "a list"
+	}
+	return "a boolean"
+}
+
+// String formats the search in the syntax read by ParseSearch
+func (s *Search) String() string {
+	var criteria []string
+	add := func(name, operator, value string) {
+		criteria = append(criteria, name+" "+operator+" "+value)
+	}
+	for _, field := range s.fields {
+		switch f := field.(type) {
+		case *TextField:
+			name := queryFieldName(f.XMLName)
+			for _, op := range []struct{ operator, value string }{
+				{"=", f.Is}, {"!=", f.IsNot}, {"starts_with", f.StartsWith}, {"ends_with", f.EndsWith}, {"contains", f.Contains},
+			} {
+				if op.value != "" {
+					add(name, op.operator, strconv.Quote(op.value))
+				}
+			}
+		case *RangeField:
+			name := queryFieldName(f.XMLName)
+			is, min, max := f.bounds()
+			if is != "" {
+				add(name, "=", is)
+			}
+			switch {
+			case min != "" && max != "":
+				add(name, "between", min+" and "+max)
+			case min != "":
+				add(name, ">=", min)
+			case max != "":
+				add(name, "<=", max)
+			}
+		case *TimeField:
+			name := queryFieldName(f.XMLName)
+			if !f.Is.IsZero() {
+				add(name, "=", queryTime(f.Is))
+			}
+			switch {
+			case !f.Min.IsZero() && queryTime(f.Min) == f.Min.UTC().Format(DateFormat) && f.Max.Equal(endOfDay(f.Min)):
+				add(name, "=", queryTime(f.Min))
+			case !f.Min.IsZero() && !f.Max.IsZero():
+				add(name, "between", queryTime(f.Min)+" and "+queryMaxTime(f.Max))
+			case !f.Min.IsZero():
+				add(name, ">=", queryTime(f.Min))
+			case !f.Max.IsZero():
+				add(name, "<=", queryMaxTime(f.Max))
+			}
+		case *MultiField:
+			items := make([]string, len(f.Items))
+			for i, item := range f.Items {
+				items[i] = queryWord(item)
+			}
+			add(queryFieldName(f.XMLName), "in", "("+strings.Join(items, ", ")+")")
+		case *KeyValueField:
+			add(queryFieldName(f.XMLName), "=", queryWord(f.Value))
+		}
+	}
+	return strings.Join(criteria, " and ")
+}
+
+func queryFieldName(name xml.Name) string {
+	return strings.Replace(name.Local, "-", "_", -1)
+}
+
+// queryTime writes the dates at midnight UTC without their time
+func queryTime(t time.Time) string {
+	t = t.UTC()
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0 {
+		return t.Format(DateFormat)
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// queryMaxTime writes the last second of a day as the date, which covers the day as a max. The other times,
+// midnight included, are written in full
+func queryMaxTime(t time.Time) string {
+	t = t.UTC()
+	if t.Hour() == 23 && t.Minute() == 59 && t.Second() == 59 && t.Nanosecond() == 0 {
+		return t.Format(DateFormat)
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// endOfDay returns the last second of the day, the precision of the times sent to the gateway
+func endOfDay(day time.Time) time.Time {
+	return day.AddDate(0, 0, 1).Add(-time.Second)
+}
+
+// queryWord quotes the values which would not be read back as a single word
+func queryWord(value string) string {
+	if value == "" || !utf8.ValidString(value) {
+		return strconv.Quote(value)
+	}
+	for i := 0; i < len(value); i++ {
+		if isQuerySpace(value[i]) || isQueryDelimiter(value[i]) || value[i] == '\\' {
+			return strconv.Quote(value)
+		}
+	}
+	return value
+}
@@ -7,31 +7,31 @@ import (
 )
 
 type Record struct {
-	Count             int      `xml:"count"`
-	XMLName           string   `xml:"record"`
-	CardType          string   `xml:"card-type"`
-	MerchantAccountId string   `xml:"merchant-account-id"`
-	Kind              string   `xml:"kind"`
-	AmountSettled     *Decimal `xml:"amount-settled"`
+	Count             int      `xml:"count" json:"count"`
+	XMLName           string   `xml:"record" json:"-"`
+	CardType          string   `xml:"card-type" json:"card_type"`
+	MerchantAccountId string   `xml:"merchant-account-id" json:"merchant_account_id"`
+	Kind              string   `xml:"kind" json:"kind"`
+	AmountSettled     *Decimal `xml:"amount-settled" json:"amount_settled"`
 }
 
 type XMLRecords struct {
-	XMLName string   `xml:"records"`
-	Type    []Record `xml:"record"`
+	XMLName string   `xml:"records" json:"-"`
+	Type    []Record `xml:"record" json:"record"`
 }
 
 type SettlementBatchSummary struct {
-	XMLName string     `xml:"settlement-batch-summary"`
-	Records XMLRecords `xml:"records"`
+	XMLName string     `xml:"settlement-batch-summary" json:"-"`
+	Records XMLRecords `xml:"records" json:"records"`
 }
 
 type Settlement struct {
-	XMLName string `xml:"settlement_batch_summary"`
-	Date    string `xml:"settlement_date"`
+	XMLName string `xml:"settlement_batch_summary" json:"-"`
+	Date    string `xml:"settlement_date" json:"settlement_date"`
 }
 
 func (c *APIClient) GenerateSettlement(ctx context.Context, s *Settlement) (*SettlementBatchSummary, error) {
-	response, err := c.do(ctx, http.MethodPost, "settlement_batch_summary", s)
+	response, err := c.do(ctx, "GenerateSettlement", http.MethodPost, "settlement_batch_summary", s)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,129 @@
+package braintree
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// The decimals, the dates and the statuses are stored as text (dates as 2006-01-02), and read from the text,
+// numeric and date columns. A NULL column reads as the zero value : scan nullable columns in pointers.
+
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+func (d *Decimal) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*d = Decimal{}
+		return nil
+	case string:
+		return d.scanText(v)
+	case []byte:
+		return d.scanText(string(v))
+	case int64:
+		*d = Decimal{Unscaled: v}
+		return nil
+	case float64:
+		return d.scanText(strconv.FormatFloat(v, 'f', -1, 64))
+	}
+	return fmt.Errorf("cannot scan %T into a Decimal", src)
+}
+
+func (d *Decimal) scanText(text string) error {
+	parsed, err := ParseDecimal(text)
+	if err != nil {
+		return err
+	}
+	*d = *parsed
+	return nil
+}
+
+// Value writes the zero date as NULL
+func (d Date) Value() (driver.Value, error) {
+	if d.IsZero() {
+		return nil, nil
+	}
+	return d.Format(DateFormat), nil
+}
+
+func (d *Date) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*d = Date{}
+		return nil
+	case time.Time:
+		*d = Date{Time: time.Date(v.Year(), v.Month(), v.Day(), 0, 0, 0, 0, time.UTC)}
+		return nil
+	case string:
+		return d.scanText(v)
+	case []byte:
+		return d.scanText(string(v))
+	}
+	return fmt.Errorf("cannot scan %T into a Date", src)
+}
+
+func (d *Date) scanText(text string) error {
+	parsed, err := time.Parse(DateFormat, text)
+	if err != nil {
+		if parsed, err = time.Parse(time.RFC3339, text); err != nil {
+			return err
+		}
+		return d.Scan(parsed)
+	}
+	*d = Date{Time: parsed}
+	return nil
+}
+
+// Value writes the empty status as NULL, as Scan reads NULL as the empty status
+func (s Status) Value() (driver.Value, error) {
+	return stringValue(string(s)), nil
+}
+
+func (s *Status) Scan(src interface{}) error {
+	text, err := scanString(src, "Status")
+	*s = Status(text)
+	return err
+}
+
+func (s SubscriptionStatus) Value() (driver.Value, error) {
+	return stringValue(string(s)), nil
+}
+
+func (s *SubscriptionStatus) Scan(src interface{}) error {
+	text, err := scanString(src, "SubscriptionStatus")
+	*s = SubscriptionStatus(text)
+	return err
+}
+
+func (s DisputeStatus) Value() (driver.Value, error) {
+	return stringValue(string(s)), nil
+}
+
+func (s *DisputeStatus) Scan(src interface{}) error {
+	text, err := scanString(src, "DisputeStatus")
+	*s = DisputeStatus(text)
+	return err
+}
+
+// stringValue returns nil for the empty string
+func stringValue(s string) driver.Value {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func scanString(src interface{}, name string) (string, error) {
+	switch v := src.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	}
+	return "", fmt.Errorf("cannot scan %T into a %s", src, name)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/xml"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 )
 
@@ -25,100 +26,212 @@ const (
 )
 
 type SubscriptionStatusEvent struct {
-	Timestamp          time.Time          `xml:"timestamp"`
-	Status             SubscriptionStatus `xml:"status"`
-	CurrencyISOCode    string             `xml:"currency-iso-code"`
-	User               string             `xml:"user"`
-	PlanID             string             `xml:"plan-id"`
-	SubscriptionSource string             `xml:"subscription-source"`
-	Balance            *Decimal           `xml:"balance"`
-	Price              *Decimal           `xml:"price"`
+	Timestamp          time.Time          `xml:"timestamp" json:"timestamp"`
+	Status             SubscriptionStatus `xml:"status" json:"status"`
+	CurrencyISOCode    string             `xml:"currency-iso-code" json:"currency_iso_code"`
+	User               string             `xml:"user" json:"user"`
+	PlanID             string             `xml:"plan-id" json:"plan_id"`
+	SubscriptionSource string             `xml:"subscription-source" json:"subscription_source"`
+	Balance            *Decimal           `xml:"balance" json:"balance"`
+	Price              *Decimal           `xml:"price" json:"price"`
 }
 
 type Subscription struct {
-	XMLName                 string                     `xml:"subscription"`
-	Id                      string                     `xml:"id"`
-	BillingDayOfMonth       string                     `xml:"billing-day-of-month"`
-	BillingPeriodEndDate    string                     `xml:"billing-period-end-date"`
-	BillingPeriodStartDate  string                     `xml:"billing-period-start-date"`
-	CurrentBillingCycle     string                     `xml:"current-billing-cycle"`
-	DaysPastDue             string                     `xml:"days-past-due"`
-	FailureCount            string                     `xml:"failure-count"`
-	FirstBillingDate        string                     `xml:"first-billing-date"`
-	MerchantAccountId       string                     `xml:"merchant-account-id"`
-	NextBillingDate         string                     `xml:"next-billing-date"`
-	PaidThroughDate         string                     `xml:"paid-through-date"`
-	PaymentMethodToken      string                     `xml:"payment-method-token"`
-	PlanId                  string                     `xml:"plan-id"`
-	TrialDurationUnit       string                     `xml:"trial-duration-unit"`
-	TrialDuration           string                     `xml:"trial-duration"`
-	Status                  SubscriptionStatus         `xml:"status"`
-	NeverExpires            bool                       `xml:"never-expires"`
-	TrialPeriod             bool                       `xml:"trial-period"`
-	Balance                 *Decimal                   `xml:"balance"`
-	NextBillAmount          *Decimal                   `xml:"next-bill-amount"`
-	NextBillingPeriodAmount *Decimal                   `xml:"next-billing-period-amount"`
-	Price                   *Decimal                   `xml:"price"`
-	Transactions            *Transactions              `xml:"transactions"`
-	Options                 *SubscriptionOpts          `xml:"options"`
-	Descriptor              *Descriptor                `xml:"descriptor"`
-	AddOns                  *AddOnList                 `xml:"add-ons"`
-	Discounts               *DiscountList              `xml:"discounts"`
-	NumberOfBillingCycles   *int                       `xml:"number-of-billing-cycles"`
-	CreatedAt               *time.Time                 `xml:"created-at,omitempty"`
-	UpdatedAt               *time.Time                 `xml:"updated-at,omitempty"`
-	StatusEvents            []*SubscriptionStatusEvent `xml:"status-history>status-event"`
+	XMLName                 string                     `xml:"subscription" json:"-"`
+	Id                      string                     `xml:"id" json:"id"`
+	BillingDayOfMonth       int                        `xml:"billing-day-of-month" json:"billing_day_of_month"`
+	BillingPeriodEndDate    *Date                      `xml:"billing-period-end-date" json:"billing_period_end_date"`
+	BillingPeriodStartDate  *Date                      `xml:"billing-period-start-date" json:"billing_period_start_date"`
+	CurrentBillingCycle     int                        `xml:"current-billing-cycle" json:"current_billing_cycle"`
+	DaysPastDue             int                        `xml:"days-past-due" json:"days_past_due"`
+	FailureCount            int                        `xml:"failure-count" json:"failure_count"`
+	FirstBillingDate        *Date                      `xml:"first-billing-date" json:"first_billing_date"`
+	MerchantAccountId       string                     `xml:"merchant-account-id" json:"merchant_account_id"`
+	NextBillingDate         *Date                      `xml:"next-billing-date" json:"next_billing_date"`
+	PaidThroughDate         *Date                      `xml:"paid-through-date" json:"paid_through_date"`
+	PaymentMethodToken      string                     `xml:"payment-method-token" json:"payment_method_token"`
+	PlanId                  string                     `xml:"plan-id" json:"plan_id"`
+	TrialDurationUnit       string                     `xml:"trial-duration-unit" json:"trial_duration_unit"`
+	TrialDuration           int                        `xml:"trial-duration" json:"trial_duration"`
+	Status                  SubscriptionStatus         `xml:"status" json:"status"`
+	NeverExpires            bool                       `xml:"never-expires" json:"never_expires"`
+	TrialPeriod             bool                       `xml:"trial-period" json:"trial_period"`
+	Balance                 *Decimal                   `xml:"balance" json:"balance"`
+	NextBillAmount          *Decimal                   `xml:"next-bill-amount" json:"next_bill_amount"`
+	NextBillingPeriodAmount *Decimal                   `xml:"next-billing-period-amount" json:"next_billing_period_amount"`
+	Price                   *Decimal                   `xml:"price" json:"price"`
+	Transactions            *Transactions              `xml:"transactions" json:"transactions"`
+	Options                 *SubscriptionOpts          `xml:"options" json:"options"`
+	Descriptor              *Descriptor                `xml:"descriptor" json:"descriptor"`
+	AddOns                  *AddOnList                 `xml:"add-ons" json:"add_ons"`
+	Discounts               *DiscountList              `xml:"discounts" json:"discounts"`
+	NumberOfBillingCycles   *int                       `xml:"number-of-billing-cycles" json:"number_of_billing_cycles"`
+	CreatedAt               *time.Time                 `xml:"created-at,omitempty" json:"created_at,omitempty"`
+	UpdatedAt               *time.Time                 `xml:"updated-at,omitempty" json:"updated_at,omitempty"`
+	StatusEvents            []*SubscriptionStatusEvent `xml:"status-history>status-event" json:"status_history"`
+
+	raw *subscriptionText // the text of the dates and counters, when decoded from XML
+}
+
+func (s *Subscription) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var element struct {
+		Inner []byte `xml:",innerxml"`
+	}
+	if err := d.DecodeElement(&element, &start); err != nil {
+		return err
+	}
+	// the element is decoded twice : into the fields, and into the text of the dates and counters
+	body := append(append([]byte("<subscription>"), element.Inner...), "</subscription>"...)
+	type typeWithNoFunctions Subscription
+	if err := xml.Unmarshal(body, (*typeWithNoFunctions)(s)); err != nil {
+		return err
+	}
+	raw := &subscriptionText{}
+	if err := xml.Unmarshal(body, raw); err != nil {
+		return err
+	}
+	s.raw = raw
+	s.BillingPeriodStartDate = dateOrNil(s.BillingPeriodStartDate)
+	s.BillingPeriodEndDate = dateOrNil(s.BillingPeriodEndDate)
+	s.FirstBillingDate = dateOrNil(s.FirstBillingDate)
+	s.NextBillingDate = dateOrNil(s.NextBillingDate)
+	s.PaidThroughDate = dateOrNil(s.PaidThroughDate)
+	return nil
+}
+
+// subscriptionText holds the dates and counters of a subscription as the gateway sent them
+type subscriptionText struct {
+	BillingDayOfMonth      string `xml:"billing-day-of-month"`
+	BillingPeriodEndDate   string `xml:"billing-period-end-date"`
+	BillingPeriodStartDate string `xml:"billing-period-start-date"`
+	CurrentBillingCycle    string `xml:"current-billing-cycle"`
+	DaysPastDue            string `xml:"days-past-due"`
+	FailureCount           string `xml:"failure-count"`
+	FirstBillingDate       string `xml:"first-billing-date"`
+	NextBillingDate        string `xml:"next-billing-date"`
+	PaidThroughDate        string `xml:"paid-through-date"`
+	TrialDuration          string `xml:"trial-duration"`
+}
+
+// text returns the text the subscription was decoded from. For the subscriptions which were not decoded
+// from the gateway XML, it formats the fields instead : the dates with DateFormat, the counters in decimal,
+// and empty for the missing dates and for the days past due and trial duration when zero
+func (s *Subscription) text() *subscriptionText {
+	if s.raw != nil {
+		return s.raw
+	}
+	optional := func(n int) string {
+		if n == 0 {
+			return ""
+		}
+		return strconv.Itoa(n)
+	}
+	return &subscriptionText{
+		BillingDayOfMonth:      strconv.Itoa(s.BillingDayOfMonth),
+		BillingPeriodEndDate:   formatDate(s.BillingPeriodEndDate),
+		BillingPeriodStartDate: formatDate(s.BillingPeriodStartDate),
+		CurrentBillingCycle:    strconv.Itoa(s.CurrentBillingCycle),
+		DaysPastDue:            optional(s.DaysPastDue),
+		FailureCount:           strconv.Itoa(s.FailureCount),
+		FirstBillingDate:       formatDate(s.FirstBillingDate),
+		NextBillingDate:        formatDate(s.NextBillingDate),
+		PaidThroughDate:        formatDate(s.PaidThroughDate),
+		TrialDuration:          optional(s.TrialDuration),
+	}
+}
+
+// The raw accessors return the dates and counters as the gateway sent them, empty for the missing or nil
+// elements. They are formatted from the fields for the subscriptions built in code or decoded from JSON.
+
+func (s *Subscription) RawBillingDayOfMonth() string {
+	return s.text().BillingDayOfMonth
+}
+
+func (s *Subscription) RawBillingPeriodStartDate() string {
+	return s.text().BillingPeriodStartDate
+}
+
+func (s *Subscription) RawBillingPeriodEndDate() string {
+	return s.text().BillingPeriodEndDate
+}
+
+func (s *Subscription) RawCurrentBillingCycle() string {
+	return s.text().CurrentBillingCycle
+}
+
+func (s *Subscription) RawDaysPastDue() string {
+	return s.text().DaysPastDue
+}
+
+func (s *Subscription) RawFailureCount() string {
+	return s.text().FailureCount
+}
+
+func (s *Subscription) RawFirstBillingDate() string {
+	return s.text().FirstBillingDate
+}
+
+func (s *Subscription) RawNextBillingDate() string {
+	return s.text().NextBillingDate
+}
+
+func (s *Subscription) RawPaidThroughDate() string {
+	return s.text().PaidThroughDate
+}
+
+func (s *Subscription) RawTrialDuration() string {
+	return s.text().TrialDuration
 }
 
 type SubscriptionRequest struct {
-	XMLName               string                `xml:"subscription"`
-	Id                    string                `xml:"id,omitempty"`
-	FailureCount          string                `xml:"failure-count,omitempty"`
-	FirstBillingDate      string                `xml:"first-billing-date,omitempty"`
-	MerchantAccountId     string                `xml:"merchant-account-id,omitempty"`
-	PaymentMethodNonce    string                `xml:"paymentMethodNonce,omitempty"`
-	PaymentMethodToken    string                `xml:"paymentMethodToken,omitempty"`
-	PlanId                string                `xml:"planId,omitempty"`
-	TrialDuration         string                `xml:"trial-duration,omitempty"`
-	TrialDurationUnit     string                `xml:"trial-duration-unit,omitempty"`
-	NeverExpires          *bool                 `xml:"never-expires,omitempty"`
-	BillingDayOfMonth     *int                  `xml:"billing-day-of-month,omitempty"`
-	NumberOfBillingCycles *int                  `xml:"number-of-billing-cycles,omitempty"`
-	Options               *SubscriptionOpts     `xml:"options,omitempty"`
-	Price                 *Decimal              `xml:"price,omitempty"`
-	TrialPeriod           *bool                 `xml:"trial-period,omitempty"`
-	Descriptor            *Descriptor           `xml:"descriptor,omitempty"`
-	AddOns                *ModificationsRequest `xml:"add-ons,omitempty"`
-	Discounts             *ModificationsRequest `xml:"discounts,omitempty"`
+	XMLName               string                `xml:"subscription" json:"-"`
+	Id                    string                `xml:"id,omitempty" json:"id,omitempty"`
+	FailureCount          string                `xml:"failure-count,omitempty" json:"failure_count,omitempty"`
+	FirstBillingDate      *Date                 `xml:"first-billing-date,omitempty" json:"first_billing_date,omitempty"`
+	MerchantAccountId     string                `xml:"merchant-account-id,omitempty" json:"merchant_account_id,omitempty"`
+	PaymentMethodNonce    string                `xml:"paymentMethodNonce,omitempty" json:"payment_method_nonce,omitempty"`
+	PaymentMethodToken    string                `xml:"paymentMethodToken,omitempty" json:"payment_method_token,omitempty"`
+	PlanId                string                `xml:"planId,omitempty" json:"plan_id,omitempty"`
+	TrialDuration         string                `xml:"trial-duration,omitempty" json:"trial_duration,omitempty"`
+	TrialDurationUnit     string                `xml:"trial-duration-unit,omitempty" json:"trial_duration_unit,omitempty"`
+	NeverExpires          *bool                 `xml:"never-expires,omitempty" json:"never_expires,omitempty"`
+	BillingDayOfMonth     *int                  `xml:"billing-day-of-month,omitempty" json:"billing_day_of_month,omitempty"`
+	NumberOfBillingCycles *int                  `xml:"number-of-billing-cycles,omitempty" json:"number_of_billing_cycles,omitempty"`
+	Options               *SubscriptionOpts     `xml:"options,omitempty" json:"options,omitempty"`
+	Price                 *Decimal              `xml:"price,omitempty" json:"price,omitempty"`
+	TrialPeriod           *bool                 `xml:"trial-period,omitempty" json:"trial_period,omitempty"`
+	Descriptor            *Descriptor           `xml:"descriptor,omitempty" json:"descriptor,omitempty"`
+	AddOns                *ModificationsRequest `xml:"add-ons,omitempty" json:"add_ons,omitempty"`
+	Discounts             *ModificationsRequest `xml:"discounts,omitempty" json:"discounts,omitempty"`
 }
 
 type Subscriptions struct {
-	Subscription []*Subscription `xml:"subscription"`
+	Subscription []*Subscription `xml:"subscription" json:"subscription"`
 }
 
 type SubscriptionOpts struct {
-	DoNotInheritAddOnsOrDiscounts        bool `xml:"do-not-inherit-add-ons-or-discounts,omitempty"`
-	ProrateCharges                       bool `xml:"prorate-charges,omitempty"`
-	ReplaceAllAddOnsAndDiscounts         bool `xml:"replace-all-add-ons-and-discounts,omitempty"`
-	RevertSubscriptionOnProrationFailure bool `xml:"revert-subscription-on-proration-failure,omitempty"`
-	StartImmediately                     bool `xml:"start-immediately,omitempty"`
+	DoNotInheritAddOnsOrDiscounts        bool `xml:"do-not-inherit-add-ons-or-discounts,omitempty" json:"do_not_inherit_add_ons_or_discounts,omitempty"`
+	ProrateCharges                       bool `xml:"prorate-charges,omitempty" json:"prorate_charges,omitempty"`
+	ReplaceAllAddOnsAndDiscounts         bool `xml:"replace-all-add-ons-and-discounts,omitempty" json:"replace_all_add_ons_and_discounts,omitempty"`
+	RevertSubscriptionOnProrationFailure bool `xml:"revert-subscription-on-proration-failure,omitempty" json:"revert_subscription_on_proration_failure,omitempty"`
+	StartImmediately                     bool `xml:"start-immediately,omitempty" json:"start_immediately,omitempty"`
 }
 
 type SubscriptionTransactionRequest struct {
-	SubscriptionID string
-	Amount         *Decimal
-	Options        *SubscriptionTransactionOptionsRequest
+	SubscriptionID string                                 `json:"subscription_id"`
+	Amount         *Decimal                               `json:"amount"`
+	Options        *SubscriptionTransactionOptionsRequest `json:"options"`
 }
 
 func (s *SubscriptionTransactionRequest) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 	x := struct {
 		XMLName        xml.Name                               `xml:"transaction"`
-		Type           string                                 `xml:"type"`
+		Type           TxType                                 `xml:"type"`
 		SubscriptionID string                                 `xml:"subscription-id"`
 		Amount         *Decimal                               `xml:"amount,omitempty"`
 		Options        *SubscriptionTransactionOptionsRequest `xml:"options,omitempty"`
 	}{
-		Type:           "sale",
+		Type:           TxTypeSale,
 		SubscriptionID: s.SubscriptionID,
 		Amount:         s.Amount,
 		Options:        s.Options,
@@ -128,21 +241,21 @@ func (s *SubscriptionTransactionRequest) MarshalXML(e *xml.Encoder, start xml.St
 }
 
 type SubscriptionTransactionOptionsRequest struct {
-	SubmitForSettlement bool `xml:"submit-for-settlement"`
+	SubmitForSettlement bool `xml:"submit-for-settlement" json:"submit_for_settlement"`
 }
 
 type SubscriptionSearchResult struct {
-	TotalItems        int
-	CurrentPageNumber int
-	PageSize          int
-	TotalIDs          []string
-	Subscriptions     []*Subscription
+	TotalItems        int             `json:"total_items"`
+	CurrentPageNumber int             `json:"current_page_number"`
+	PageSize          int             `json:"page_size"`
+	TotalIDs          []string        `json:"total_ids"`
+	Subscriptions     []*Subscription `json:"subscriptions"`
 }
 
 const subscriptionsPath = "subscriptions"
 
 func (c *APIClient) CreateSubscription(ctx context.Context, sub *SubscriptionRequest) (*Subscription, error) {
-	response, err := c.do(ctx, http.MethodPost, subscriptionsPath, sub)
+	response, err := c.do(ctx, "CreateSubscription", http.MethodPost, subscriptionsPath, sub)
 	if err != nil {
 		return nil, err
 	}
@@ -158,7 +271,7 @@ func (c *APIClient) CreateSubscription(ctx context.Context, sub *SubscriptionReq
 }
 
 func (c *APIClient) UpdateSubscription(ctx context.Context, subId string, sub *SubscriptionRequest) (*Subscription, error) {
-	response, err := c.do(ctx, http.MethodPut, subscriptionsPath+"/"+subId, sub)
+	response, err := c.do(ctx, "UpdateSubscription", http.MethodPut, subscriptionsPath+"/"+subId, sub)
 	if err != nil {
 		return nil, err
 	}
@@ -174,7 +287,7 @@ func (c *APIClient) UpdateSubscription(ctx context.Context, subId string, sub *S
 }
 
 func (c *APIClient) FindSubscription(ctx context.Context, subId string) (*Subscription, error) {
-	response, err := c.do(ctx, http.MethodGet, subscriptionsPath+"/"+subId, nil)
+	response, err := c.do(ctx, "FindSubscription", http.MethodGet, subscriptionsPath+"/"+subId, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -190,7 +303,7 @@ func (c *APIClient) FindSubscription(ctx context.Context, subId string) (*Subscr
 }
 
 func (c *APIClient) CancelSubscription(ctx context.Context, subId string) (*Subscription, error) {
-	response, err := c.do(ctx, http.MethodPut, subscriptionsPath+"/"+subId+"/cancel", nil)
+	response, err := c.do(ctx, "CancelSubscription", http.MethodPut, subscriptionsPath+"/"+subId+"/cancel", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -206,7 +319,7 @@ func (c *APIClient) CancelSubscription(ctx context.Context, subId string) (*Subs
 }
 
 func (c *APIClient) RetryCharge(ctx context.Context, txReq *SubscriptionTransactionRequest) error {
-	response, err := c.do(ctx, http.MethodPost, transactionsPath, txReq)
+	response, err := c.do(ctx, "RetryCharge", http.MethodPost, transactionsPath, txReq)
 	if err != nil {
 		return err
 	}
@@ -218,7 +331,7 @@ func (c *APIClient) RetryCharge(ctx context.Context, txReq *SubscriptionTransact
 }
 
 func (c *APIClient) SearchSubscriptions(ctx context.Context, query *Search) (*SearchResult, error) {
-	response, err := c.do(ctx, http.MethodPost, subscriptionsPath+"/"+advancedSearchIdsPath, query)
+	response, err := c.do(ctx, "SearchSubscriptions", http.MethodPost, subscriptionsPath+"/"+advancedSearchIdsPath, query)
 	if err != nil {
 		return nil, err
 	}
@@ -267,7 +380,7 @@ func (c *APIClient) SearchSubscription(ctx context.Context, query *Search, searc
 }
 
 func (c *APIClient) FetchSubscriptions(ctx context.Context, query *Search) ([]*Subscription, error) {
-	response, err := c.do(ctx, http.MethodPost, subscriptionsPath+"/"+advancedSearchPath, query)
+	response, err := c.do(ctx, "FetchSubscriptions", http.MethodPost, subscriptionsPath+"/"+advancedSearchPath, query)
 	if err != nil {
 		return nil, err
 	}
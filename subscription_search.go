@@ -0,0 +1,92 @@
+package braintree
+
+import (
+	"strconv"
+	"time"
+)
+
+var subscriptionStatuses = []string{
+	string(SubscriptionStatusActive), string(SubscriptionStatusCanceled), string(SubscriptionStatusExpired),
+	string(SubscriptionStatusPastDue), string(SubscriptionStatusPending),
+}
+
+// SubscriptionSearch builds the criteria of a subscription search, see TransactionSearch
+//
+//	query, err := NewSubscriptionSearch().Status(SubscriptionStatusPastDue).DaysPastDue(&days, nil).Search()
+//	it := c.IterateSubscriptions(query)
+type SubscriptionSearch struct {
+	criteria
+}
+
+func NewSubscriptionSearch() *SubscriptionSearch {
+	return &SubscriptionSearch{}
+}
+
+// Search returns the search to be given to SearchSubscriptions or IterateSubscriptions, or an *InvalidSearchError
+func (s *SubscriptionSearch) Search() (*Search, error) {
+	return s.build()
+}
+
+func (s *SubscriptionSearch) IDs(ids ...string) *SubscriptionSearch {
+	s.multi("ids", nil, ids)
+	return s
+}
+
+func (s *SubscriptionSearch) Status(statuses ...SubscriptionStatus) *SubscriptionSearch {
+	values := make([]string, len(statuses))
+	for i, status := range statuses {
+		values[i] = string(status)
+	}
+	s.multi("status", subscriptionStatuses, values)
+	return s
+}
+
+func (s *SubscriptionSearch) PlanID(ids ...string) *SubscriptionSearch {
+	s.multi("plan-id", nil, ids)
+	return s
+}
+
+func (s *SubscriptionSearch) MerchantAccountID(ids ...string) *SubscriptionSearch {
+	s.multi("merchant-account-id", nil, ids)
+	return s
+}
+
+func (s *SubscriptionSearch) InTrialPeriod(inTrial bool) *SubscriptionSearch {
+	s.multi("in-trial-period", nil, []string{strconv.FormatBool(inTrial)})
+	return s
+}
+
+// DaysPastDue searches the past due subscriptions by the number of days, a nil bound leaving that side open
+func (s *SubscriptionSearch) DaysPastDue(min, max *int) *SubscriptionSearch {
+	s.intRange("days-past-due", min, max)
+	return s
+}
+
+func (s *SubscriptionSearch) BillingCyclesRemaining(min, max *int) *SubscriptionSearch {
+	s.intRange("billing-cycles-remaining", min, max)
+	return s
+}
+
+func (s *SubscriptionSearch) Price(min, max *Decimal) *SubscriptionSearch {
+	s.decimalRange("price", min, max)
+	return s
+}
+
+func (s *SubscriptionSearch) CreatedAt(min, max time.Time) *SubscriptionSearch {
+	s.timeRange("created-at", min, max)
+	return s
+}
+
+func (s *SubscriptionSearch) NextBillingDate(min, max time.Time) *SubscriptionSearch {
+	s.timeRange("next-billing-date", min, max)
+	return s
+}
+
+func (s *SubscriptionSearch) ID() *TextField {
+	return s.text("id")
+}
+
+// TransactionID finds the subscription which charged the transaction
+func (s *SubscriptionSearch) TransactionID() *TextField {
+	return s.text("transaction-id")
+}
@@ -7,24 +7,24 @@ import (
 )
 
 func (c *APIClient) SandboxSettle(ctx context.Context, transactionID string) (*Tx, error) {
-	return c.setStatus(ctx, transactionID, "settle")
+	return c.setStatus(ctx, "SandboxSettle", transactionID, "settle")
 }
 
 func (c *APIClient) SandboxSettlementConfirm(ctx context.Context, transactionID string) (*Tx, error) {
-	return c.setStatus(ctx, transactionID, "settlement_confirm")
+	return c.setStatus(ctx, "SandboxSettlementConfirm", transactionID, "settlement_confirm")
 }
 
 func (c *APIClient) SandboxSettlementDecline(ctx context.Context, transactionID string) (*Tx, error) {
-	return c.setStatus(ctx, transactionID, "settlement_decline")
+	return c.setStatus(ctx, "SandboxSettlementDecline", transactionID, "settlement_decline")
 }
 
 func (c *APIClient) SandboxSettlementPending(ctx context.Context, transactionID string) (*Tx, error) {
-	return c.setStatus(ctx, transactionID, StatusSettlementPending)
+	return c.setStatus(ctx, "SandboxSettlementPending", transactionID, StatusSettlementPending)
 }
 
-func (c *APIClient) setStatus(ctx context.Context, transactionID string, status Status) (*Tx, error) {
+func (c *APIClient) setStatus(ctx context.Context, operation, transactionID string, status Status) (*Tx, error) {
 	if c.Key.URL != ProductionURL {
-		response, err := c.do(ctx, http.MethodPut, transactionsPath+"/"+transactionID+"/"+string(status), nil)
+		response, err := c.do(ctx, operation, http.MethodPut, transactionsPath+"/"+transactionID+"/"+string(status), nil)
 		if err != nil {
 			return nil, err
 		}
@@ -38,6 +38,6 @@ func (c *APIClient) setStatus(ctx context.Context, transactionID string, status
 		}
 		return nil, &invalidResponseError{response}
 	} else {
-		return nil, &testOperationPerformedInProductionError{}
+		return nil, ErrNotAllowedInProduction
 	}
 }
@@ -0,0 +1,130 @@
+// +build unit
+
+package tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/badu/braintree"
+)
+
+func decimals(parts []*Decimal) string {
+	s := make([]string, len(parts))
+	for i, part := range parts {
+		s[i] = part.String()
+	}
+	return strings.Join(s, " ")
+}
+
+func TestDecimalAllocate(t *testing.T) {
+	t.Parallel()
+
+	one := NewDecimal(1, 0)
+	for _, tt := range []struct {
+		name   string
+		total  *Decimal
+		scale  int
+		ratios []*Decimal
+		out    string
+	}{
+		{"thirds", NewDecimal(10000, 2), 2, []*Decimal{one, one, one}, "33.34 33.33 33.33"},
+		{"largest remainder", NewDecimal(100, 2), 2, []*Decimal{NewDecimal(20, 2), NewDecimal(35, 2), NewDecimal(45, 2)}, "0.20 0.35 0.45"},
+		{"remainders", NewDecimal(1000, 2), 2, []*Decimal{NewDecimal(1, 0), NewDecimal(2, 0), NewDecimal(4, 0)}, "1.43 2.86 5.71"},
+		{"ratio zero", NewDecimal(500, 2), 2, []*Decimal{NewDecimal(0, 0), NewDecimal(3, 1)}, "0.00 5.00"},
+		{"negative", NewDecimal(-1000, 2), 2, []*Decimal{one, one, one}, "-3.34 -3.33 -3.33"},
+		{"yen", NewDecimal(100, 0), 0, []*Decimal{one, one, one}, "34 33 33"},
+		{"upscaled", NewDecimal(1, 0), 3, []*Decimal{one, one, one}, "0.334 0.333 0.333"},
+	} {
+		parts, err := tt.total.Allocate(tt.scale, tt.ratios...)
+		if err != nil {
+			t.Errorf("%s : %v", tt.name, err)
+			continue
+		}
+		if got := decimals(parts); got != tt.out {
+			t.Errorf("%s : expected %s, got %s", tt.name, tt.out, got)
+		}
+		sum := NewDecimal(0, 0)
+		for _, part := range parts {
+			sum, _ = sum.Add(part)
+		}
+		if sum.Cmp(tt.total) != 0 {
+			t.Errorf("%s : the parts add up to %s", tt.name, sum)
+		}
+	}
+
+	if parts, err := NewDecimal(1000, 2).Split(2, 3); err != nil || decimals(parts) != "3.34 3.33 3.33" {
+		t.Errorf("split : %s (%v)", decimals(parts), err)
+	}
+	if _, err := NewDecimal(1005, 3).Split(2, 2); !errors.Is(err, ErrDecimalPrecision) {
+		t.Errorf("expected a precision error, got %v", err)
+	}
+	if _, err := NewDecimal(100, 2).Allocate(2, NewDecimal(0, 0)); err == nil {
+		t.Error("expected an error for ratios adding up to zero")
+	}
+	if _, err := NewDecimal(100, 2).Allocate(2, NewDecimal(-1, 0), NewDecimal(2, 0)); err == nil {
+		t.Error("expected an error for a negative ratio")
+	}
+	if _, err := NewDecimal(100, 2).Split(2, 0); err == nil {
+		t.Error("expected an error for no parts")
+	}
+}
+
+func TestMoneyAllocate(t *testing.T) {
+	t.Parallel()
+
+	parts, err := Money{Amount: NewDecimal(1000, 0), Currency: "JPY"}.Split(3)
+	if err != nil || len(parts) != 3 || parts[0].String() != "334 JPY" || parts[2].String() != "333 JPY" {
+		t.Fatalf("unexpected parts %v (%v)", parts, err)
+	}
+	parts, err = Money{Amount: NewDecimal(1000, 3), Currency: "KWD"}.Allocate(NewDecimal(1, 0), NewDecimal(2, 0))
+	if err != nil || parts[0].String() != "0.333 KWD" || parts[1].String() != "0.667 KWD" {
+		t.Fatalf("unexpected parts %v (%v)", parts, err)
+	}
+	if _, err := (Money{Amount: NewDecimal(100, 2), Currency: "XYZ"}).Split(2); !errors.Is(err, ErrUnknownCurrency) {
+		t.Fatalf("expected an unknown currency, got %v", err)
+	}
+}
+
+func TestReconcileLineItems(t *testing.T) {
+	t.Parallel()
+
+	tx := &TxRequest{
+		Amount:    NewDecimal(2700, 2),
+		TaxAmount: NewDecimal(250, 2),
+		LineItems: LineItemRequests{
+			{Kind: LineItemDebitKind, Name: "shirt", Quantity: NewDecimal(2, 0), UnitAmount: NewDecimal(1000, 2), TotalAmount: NewDecimal(2000, 2), UnitTaxAmount: NewDecimal(100, 2), TaxAmount: NewDecimal(200, 2)},
+			{Kind: LineItemDebitKind, Name: "socks", Quantity: NewDecimal(15, 1), UnitAmount: NewDecimal(333, 2), TotalAmount: NewDecimal(500, 2), TaxAmount: NewDecimal(50, 2), DiscountAmount: NewDecimal(50, 2)},
+			{Kind: LineItemCreditKind, Name: "voucher", Quantity: NewDecimal(1, 0), UnitAmount: NewDecimal(-0, 2), TotalAmount: NewDecimal(0, 2)},
+		},
+	}
+	if err := tx.ReconcileLineItems(); err != nil {
+		t.Fatal(err)
+	}
+	tx.LineItems[2].UnitAmount, tx.LineItems[2].TotalAmount = NewDecimal(200, 2), NewDecimal(200, 2)
+	if err := tx.ReconcileLineItems(); err == nil || !strings.Contains(err.Error(), "transaction.amount is 27.00, expected 25.00") {
+		t.Fatalf("expected the amount to mismatch, got %v", err)
+	}
+
+	tx.Amount = NewDecimal(2550, 2)
+	tx.TaxAmount = NewDecimal(300, 2)
+	tx.LineItems[0].TotalAmount = NewDecimal(2100, 2)
+	err := tx.ReconcileLineItems()
+	var mismatch *LineItemsMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("expected a mismatch error, got %v", err)
+	}
+	var fields []string
+	for _, m := range mismatch.Mismatches {
+		fields = append(fields, m.Field+"="+m.Actual.String()+"/"+m.Expected.String())
+	}
+	want := "transaction.line-items.0.total-amount=21.00/20.00 transaction.tax-amount=3.00/2.50 transaction.amount=25.50/26.50"
+	if got := strings.Join(fields, " "); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+
+	if err := (&TxRequest{Amount: NewDecimal(100, 2)}).ReconcileLineItems(); err != nil {
+		t.Fatalf("no line items : %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+// +build unit
+
+package tests
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/badu/braintree"
+	"github.com/badu/braintree/cassette"
+)
+
+func customerEchoServer(t *testing.T, bodies *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		*bodies = append(*bodies, string(body))
+		var req struct {
+			XMLName    xml.Name `xml:"customer"`
+			ID         string   `xml:"id"`
+			FirstName  string   `xml:"first-name"`
+			CreditCard struct {
+				Number string `xml:"number"`
+			} `xml:"credit-card"`
+		}
+		if err := xml.Unmarshal(body, &req); err != nil {
+			t.Errorf("bad request body %s : %v", body, err)
+		}
+		// the merchant id is echoed as the company, as the gateway does in some of its responses
+		merchant := strings.Split(r.URL.Path, "/")[2]
+		w.Header().Set("Location", "/merchants/"+merchant+"/customers/"+req.ID)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`<customer><id>` + req.ID + `</id><first-name>` + req.FirstName + `</first-name><company>` + merchant + `</company>` +
+			`<credit-cards type="array"><credit-card><token>tok-` + req.ID + `</token><last-4>` + req.CreditCard.Number[len(req.CreditCard.Number)-4:] + `</last-4></credit-card></credit-cards></customer>`))
+	}))
+}
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "cassette")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	path := filepath.Join(dir, "fixtures", "customers.json")
+
+	var bodies []string
+	srv := customerEchoServer(t, &bodies)
+
+	recorder, err := cassette.New(path, cassette.ModeAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recording := NewWithHttpClient(srv.URL, "merchant-1", "public", "private", &http.Client{Transport: recorder})
+
+	recordedID := RandomString()
+	if _, err := recording.CreateCustomer(context.Background(), &CustomerRequest{
+		ID:           recordedID,
+		FirstName:    "Lucy",
+		CustomFields: CustomFields{"a": "1", "b": "2", "c": "3"},
+		CreditCard:   &CreditCard{Number: testCardVisa, CVV: "123", ExpirationDate: "05/30"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+	srv.Close()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{testCardVisa, "private", "merchant-1", "Authorization", "<cvv>"} {
+		if strings.Contains(string(data), secret) {
+			t.Fatalf("cassette contains %q : %s", secret, data)
+		}
+	}
+
+	replayer, err := cassette.New(path, cassette.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replaying := NewWithHttpClient(srv.URL, "merchant-2", "other-public", "other-private", &http.Client{Transport: replayer})
+
+	liveID := RandomString()
+	customer, err := replaying.CreateCustomer(context.Background(), &CustomerRequest{
+		ID:           liveID,
+		FirstName:    "Lucy",
+		CustomFields: CustomFields{"c": "3", "b": "2", "a": "1"},
+		CreditCard:   &CreditCard{Number: testCardVisa, CVV: "123", ExpirationDate: "05/30"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if customer.Id != liveID {
+		t.Fatalf("got customer id %q, want the live random id %q", customer.Id, liveID)
+	}
+	if customer.Company != "merchant-2" {
+		t.Fatalf("got company %q, want the live merchant id", customer.Company)
+	}
+	if customer.CreditCards.CreditCard[0].Token != "tok-"+liveID {
+		t.Fatalf("got token %q, want %q", customer.CreditCards.CreditCard[0].Token, "tok-"+liveID)
+	}
+	if len(replayer.Unused()) != 0 {
+		t.Fatalf("got %d unused interactions", len(replayer.Unused()))
+	}
+
+	_, err = replaying.CreateCustomer(context.Background(), &CustomerRequest{ID: liveID, FirstName: "Someone else"})
+	if !errors.Is(err, cassette.ErrNoInteraction) {
+		t.Fatalf("expected ErrNoInteraction, got %v", err)
+	}
+	if len(bodies) != 1 {
+		t.Fatalf("got %d requests on the server, want 1", len(bodies))
+	}
+}
+
+func TestCassetteScrubsClientTokens(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "cassette")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	path := filepath.Join(dir, "tokens.json")
+
+	// the client tokens are base64 encoded, and carry the merchant id
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		merchant := strings.Split(r.URL.Path, "/")[2]
+		token := base64.StdEncoding.EncodeToString([]byte(`{"version":2,"merchantId":"` + merchant + `"}`))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`<client-token><value>` + token + `</value></client-token>`))
+	}))
+	defer srv.Close()
+
+	recorder, err := cassette.New(path, cassette.ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recording := NewWithHttpClient(srv.URL, "merchant-1", "public", "private", &http.Client{Transport: recorder})
+	if _, err := recording.GenerateToken(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	replayer, err := cassette.New(path, cassette.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replayer.Unused()) != 1 {
+		t.Fatalf("got %d interactions, want 1", len(replayer.Unused()))
+	}
+	recorded := replayer.Unused()[0].Response.Body
+	replaying := NewWithHttpClient(srv.URL, "merchant-2", "public", "private", &http.Client{Transport: replayer})
+	token, err := replaying.GenerateToken(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(decoded), `"merchantId":"merchant-2"`) {
+		t.Fatalf("got token %s, want the live merchant id", decoded)
+	}
+	start := strings.Index(recorded, "<value>") + len("<value>")
+	decoded, _ = base64.StdEncoding.DecodeString(recorded[start:strings.Index(recorded, "</value>")])
+	if strings.Contains(string(decoded), "merchant-1") {
+		t.Fatalf("recorded token carries the merchant id : %s", decoded)
+	}
+}
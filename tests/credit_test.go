@@ -0,0 +1,82 @@
+// +build unit
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/badu/braintree"
+)
+
+func TestPayValidatesType(t *testing.T) {
+	c, _, done := fakeClient(t)
+	defer done()
+	ctx := context.Background()
+
+	for _, typ := range []TxType{"", "refund", "Sale"} {
+		_, err := c.Pay(ctx, &TxRequest{Type: typ, Amount: NewDecimal(1000, 2), PaymentMethodNonce: "fake-valid-nonce"})
+		if !errors.Is(err, ErrInvalidTxType) {
+			t.Fatalf("type %q : got %v, want ErrInvalidTxType", typ, err)
+		}
+	}
+	if _, err := c.Pay(ctx, nil); !errors.Is(err, ErrInvalidTxType) {
+		t.Fatalf("nil transaction : got %v, want ErrInvalidTxType", err)
+	}
+	if _, err := c.Credit(ctx, nil); !errors.Is(err, ErrInvalidTxType) {
+		t.Fatalf("nil credit : got %v, want ErrInvalidTxType", err)
+	}
+	if result, err := c.SearchTxs(ctx, &Search{}); err != nil || len(result.IDs) != 0 {
+		t.Fatalf("expected no transaction to be sent, got %v (%v)", result, err)
+	}
+
+	tx, err := c.Pay(ctx, &TxRequest{Type: TxTypeSale, Amount: NewDecimal(1000, 2), PaymentMethodNonce: "fake-valid-nonce"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Type != TxTypeSale {
+		t.Fatalf("got type %q, want %q", tx.Type, TxTypeSale)
+	}
+}
+
+func TestCredit(t *testing.T) {
+	c, _, done := fakeClient(t)
+	defer done()
+	ctx := context.Background()
+
+	req := &TxRequest{Amount: NewDecimal(2500, 2), CreditCard: &CreditCard{Number: testCardVisa, ExpirationDate: "05/2030"}}
+	credit, err := c.Credit(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if credit.Type != TxTypeCredit || credit.Status != StatusSubmittedForSettlement || credit.Amount.String() != "25.00" {
+		t.Fatalf("unexpected credit %+v", credit)
+	}
+	if credit.RefundedTransactionId != nil {
+		t.Fatalf("expected an unreferenced credit, refunding %q", *credit.RefundedTransactionId)
+	}
+	if req.Type != "" {
+		t.Fatalf("expected the request to be left unchanged, got type %q", req.Type)
+	}
+
+	_, err = c.Credit(ctx, &TxRequest{Type: TxTypeSale, Amount: NewDecimal(2500, 2), PaymentMethodNonce: "fake-valid-nonce"})
+	if !errors.Is(err, ErrInvalidTxType) {
+		t.Fatalf("got %v, want ErrInvalidTxType", err)
+	}
+
+	if _, err := c.Pay(ctx, &TxRequest{Type: TxTypeSale, Amount: NewDecimal(1000, 2), PaymentMethodNonce: "fake-valid-nonce"}); err != nil {
+		t.Fatal(err)
+	}
+	query, err := NewTransactionSearch().Type(TxTypeCredit).Search()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := c.SearchTxs(ctx, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.IDs) != 1 || result.IDs[0] != credit.Id {
+		t.Fatalf("got credits %v, want [%s]", result.IDs, credit.Id)
+	}
+}
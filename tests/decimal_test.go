@@ -0,0 +1,126 @@
+// +build unit
+
+package tests
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	. "github.com/badu/braintree"
+)
+
+func TestDecimalArithmetic(t *testing.T) {
+	t.Parallel()
+
+	d := func(s string) *Decimal {
+		v, err := ParseDecimal(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	tests := []struct {
+		name string
+		op   func() (*Decimal, error)
+		out  string
+	}{
+		{"add", func() (*Decimal, error) { return d("10.5").Add(d("0.25")) }, "10.75"},
+		{"add negative", func() (*Decimal, error) { return d("1.00").Add(d("-3")) }, "-2.00"},
+		{"sub", func() (*Decimal, error) { return d("0.1").Sub(d("0.30")) }, "-0.20"},
+		{"mul", func() (*Decimal, error) { return d("19.99").Mul(d("3")) }, "59.97"},
+		{"mul scales", func() (*Decimal, error) { return d("1.5").Mul(d("-0.25")) }, "-0.375"},
+		{"quo", func() (*Decimal, error) { return d("10").Quo(d("3"), 2, RoundHalfUp) }, "3.33"},
+		{"quo up", func() (*Decimal, error) { return d("20").Quo(d("3"), 2, RoundHalfUp) }, "6.67"},
+		{"quo down", func() (*Decimal, error) { return d("20").Quo(d("3"), 2, RoundDown) }, "6.66"},
+		{"quo negative", func() (*Decimal, error) { return d("-20").Quo(d("3"), 2, RoundHalfUp) }, "-6.67"},
+		{"quo scales", func() (*Decimal, error) { return d("1.000").Quo(d("0.08"), 1, RoundHalfEven) }, "12.5"},
+		{"neg", func() (*Decimal, error) { return d("2.50").Neg() }, "-2.50"},
+		{"abs", func() (*Decimal, error) { return d("-0.05").Abs() }, "0.05"},
+		{"half up", func() (*Decimal, error) { return d("2.345").Round(2, RoundHalfUp) }, "2.35"},
+		{"half up negative", func() (*Decimal, error) { return d("-2.345").Round(2, RoundHalfUp) }, "-2.35"},
+		{"half even down", func() (*Decimal, error) { return d("2.345").Round(2, RoundHalfEven) }, "2.34"},
+		{"half even up", func() (*Decimal, error) { return d("2.355").Round(2, RoundHalfEven) }, "2.36"},
+		{"half even negative", func() (*Decimal, error) { return d("-2.5").Round(0, RoundHalfEven) }, "-2"},
+		{"half even above half", func() (*Decimal, error) { return d("2.3451").Round(2, RoundHalfEven) }, "2.35"},
+		{"truncate", func() (*Decimal, error) { return d("-2.349").Round(2, RoundDown) }, "-2.34"},
+		{"round up scale", func() (*Decimal, error) { return d("2.3").Round(3, RoundDown) }, "2.300"},
+		{"rescale", func() (*Decimal, error) { return d("2.500").Rescale(1) }, "2.5"},
+	}
+	for _, tt := range tests {
+		out, err := tt.op()
+		if err != nil {
+			t.Errorf("%s : %v", tt.name, err)
+			continue
+		}
+		if out.String() != tt.out {
+			t.Errorf("%s : expected %s, got %s", tt.name, tt.out, out)
+		}
+	}
+
+	if d("-0.05").Sign() != -1 || d("0.00").Sign() != 0 || d("3").Sign() != 1 {
+		t.Error("unexpected signs")
+	}
+	if d("-1.50").Cmp(d("-1.5")) != 0 || d("-2").Cmp(d("1")) != -1 {
+		t.Error("unexpected comparison of negative decimals")
+	}
+}
+
+func TestDecimalErrors(t *testing.T) {
+	t.Parallel()
+
+	max := NewDecimal(math.MaxInt64, 2)
+	min := NewDecimal(math.MinInt64, 0)
+	if _, err := max.Add(NewDecimal(1, 2)); !errors.Is(err, ErrDecimalOverflow) {
+		t.Errorf("add : expected an overflow, got %v", err)
+	}
+	if _, err := max.Mul(NewDecimal(2, 0)); !errors.Is(err, ErrDecimalOverflow) {
+		t.Errorf("mul : expected an overflow, got %v", err)
+	}
+	if _, err := max.Rescale(3); !errors.Is(err, ErrDecimalOverflow) {
+		t.Errorf("rescale : expected an overflow, got %v", err)
+	}
+	if _, err := min.Neg(); !errors.Is(err, ErrDecimalOverflow) {
+		t.Errorf("neg : expected an overflow, got %v", err)
+	}
+	if _, err := min.Sub(NewDecimal(1, 0)); !errors.Is(err, ErrDecimalOverflow) {
+		t.Errorf("sub : expected an overflow, got %v", err)
+	}
+	if _, err := NewDecimal(1, 0).Quo(NewDecimal(0, 2), 2, RoundHalfUp); !errors.Is(err, ErrDivisionByZero) {
+		t.Errorf("quo : expected a division by zero, got %v", err)
+	}
+	if _, err := NewDecimal(2505, 3).Rescale(2); !errors.Is(err, ErrDecimalPrecision) {
+		t.Errorf("rescale : expected a loss of precision, got %v", err)
+	}
+	if _, err := ParseDecimal("92233720368547758.08"); !errors.Is(err, ErrDecimalOverflow) {
+		t.Errorf("parse : expected an overflow, got %v", err)
+	}
+	for _, in := range []string{"1.2.3", "+-5", "--5", "5e3", "1E-2", "5.", ".", "-", "1,5", " 1", "0x10", "1_000"} {
+		if d, err := ParseDecimal(in); err == nil {
+			t.Errorf("parse %q : expected an error, got %s", in, d)
+		}
+	}
+	for in, out := range map[string]string{"+5": "5", "-0.05": "-0.05", ".5": "0.5", "007.10": "7.10"} {
+		if d, err := ParseDecimal(in); err != nil || d.String() != out {
+			t.Errorf("parse %q : expected %s, got %v (%v)", in, out, d, err)
+		}
+	}
+}
+
+func TestDecimalMarshalNegative(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		d   *Decimal
+		out string
+	}{
+		{NewDecimal(-5, 2), "-0.05"},
+		{NewDecimal(-150, 2), "-1.50"},
+		{NewDecimal(-25, -1), "-250"},
+		{NewDecimal(0, -2), "0"},
+	} {
+		if got := tt.d.String(); got != tt.out {
+			t.Errorf("%+v : expected %s, got %s", tt.d, tt.out, got)
+		}
+	}
+}
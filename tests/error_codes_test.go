@@ -0,0 +1,107 @@
+// +build unit
+
+package tests
+
+import (
+	"context"
+	"encoding/xml"
+	"testing"
+
+	. "github.com/badu/braintree"
+)
+
+func TestErrorCodesCatalog(t *testing.T) {
+	t.Parallel()
+
+	if !CodeTransactionAmountIsRequired.Known() || CodeTransactionAmountIsRequired.Description() != "Amount is required." {
+		t.Fatalf("unexpected description %q", CodeTransactionAmountIsRequired.Description())
+	}
+	if g, w := CodeCreditCardNumberIsInvalid.Class(), ErrorClassUser; g != w {
+		t.Fatalf("got class %v, want %v", g, w)
+	}
+	if g, w := CodeTransactionCannotRefundUnlessSettled.Class(), ErrorClassIntegration; g != w {
+		t.Fatalf("got class %v, want %v", g, w)
+	}
+	if unknown := ErrorCode("12345"); unknown.Known() || unknown.Class() != ErrorClassUnknown || unknown.Class().String() != "unknown" {
+		t.Fatalf("%q should not be in the catalog", unknown)
+	}
+}
+
+func TestErrorCodesOnAPIError(t *testing.T) {
+	t.Parallel()
+
+	c, _, stop := fakeClient(t)
+	defer stop()
+	ctx := context.Background()
+
+	_, err := c.Pay(ctx, &TxRequest{Type: "sale", Amount: NewDecimal(1000, 2), CreditCard: &CreditCard{Number: "4111111111111112"}})
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		t.Fatalf("expected an api error, got %v", err)
+	}
+	if !apiErr.Has(CodeCreditCardNumberIsInvalid) || !apiErr.Has(CodeCreditCardExpirationDateIsRequired) || apiErr.Has(CodeTransactionAmountIsRequired) {
+		t.Fatalf("unexpected codes %v", validationCodes(err))
+	}
+	fields := apiErr.FieldErrors()
+	if number := fields["transaction.credit-card.number"]; len(number) != 1 || number[0].Code != string(CodeCreditCardNumberIsInvalid) {
+		t.Fatalf("unexpected field errors %+v", fields)
+	}
+	if expiration := fields["transaction.credit-card.expiration-date"]; len(expiration) != 1 {
+		t.Fatalf("unexpected field errors %+v", fields)
+	}
+
+	tx, err := c.Pay(ctx, &TxRequest{Type: "sale", Amount: NewDecimal(1000, 2), PaymentMethodNonce: "fake-valid-nonce"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.Refund(ctx, tx.Id)
+	apiErr, ok = err.(*APIError)
+	if !ok || !apiErr.Has(CodeTransactionCannotRefundUnlessSettled) {
+		t.Fatalf("expected a refund error, got %v", err)
+	}
+	if base := apiErr.FieldErrors()["transaction"]; len(base) != 1 || base[0].Attribute != "Base" {
+		t.Fatalf("unexpected field errors %+v", apiErr.FieldErrors())
+	}
+}
+
+func TestErrorCodesFieldErrorsOnLineItems(t *testing.T) {
+	t.Parallel()
+
+	const response = `<?xml version="1.0" encoding="UTF-8"?>
+<api-error-response>
+  <errors>
+    <errors type="array"/>
+    <transaction>
+      <errors type="array"/>
+      <line-items>
+        <index-1>
+          <errors type="array">
+            <error>
+              <code>95811</code>
+              <attribute type="symbol">quantity</attribute>
+              <message>Quantity is required.</message>
+            </error>
+            <error>
+              <code>95821</code>
+              <attribute type="symbol">unit_of_measure</attribute>
+              <message>Unit of measure is too long.</message>
+            </error>
+          </errors>
+        </index-1>
+      </line-items>
+    </transaction>
+  </errors>
+  <message>Quantity is required.</message>
+</api-error-response>`
+	apiErr := &APIError{}
+	if err := xml.Unmarshal([]byte(response), apiErr); err != nil {
+		t.Fatal(err)
+	}
+	if !apiErr.Has(CodeLineItemQuantityIsRequired) {
+		t.Fatalf("missing code, got %+v", apiErr.All())
+	}
+	fields := apiErr.FieldErrors()
+	if len(fields) != 2 || len(fields["transaction.line-items.1.quantity"]) != 1 || len(fields["transaction.line-items.1.unit-of-measure"]) != 1 {
+		t.Fatalf("unexpected field errors %+v", fields)
+	}
+}
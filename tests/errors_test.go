@@ -0,0 +1,106 @@
+// +build unit
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/badu/braintree"
+)
+
+func TestErrorsMatchStatusCodes(t *testing.T) {
+	t.Parallel()
+
+	for status, sentinel := range map[int]error{
+		http.StatusUnauthorized:        ErrAuthentication,
+		http.StatusForbidden:           ErrAuthorization,
+		http.StatusNotFound:            ErrNotFound,
+		http.StatusUpgradeRequired:     ErrUpgradeRequired,
+		http.StatusTooManyRequests:     ErrRateLimited,
+		http.StatusInternalServerError: ErrServerError,
+		http.StatusServiceUnavailable:  ErrDownForMaintenance,
+	} {
+		status := status
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(HdrRequestID, "req-1")
+			w.WriteHeader(status)
+		}))
+		c := New(srv.URL, "merchant", "public", "private")
+		_, err := c.FindCustomer(context.Background(), "cus1")
+		srv.Close()
+
+		if !errors.Is(err, sentinel) {
+			t.Errorf("status %d : %v is not %v", status, err, sentinel)
+		}
+		if errors.Is(err, ErrProcessorDeclined) {
+			t.Errorf("status %d : %v should not be a decline", status, err)
+		}
+		var responseErr ResponseError
+		if !errors.As(err, &responseErr) {
+			t.Fatalf("status %d : %T is not a ResponseError", status, err)
+		}
+		if responseErr.StatusCode() != status || responseErr.Path() != "customers/cus1" || responseErr.RequestID() != "req-1" {
+			t.Errorf("status %d : got %d %q %q", status, responseErr.StatusCode(), responseErr.Path(), responseErr.RequestID())
+		}
+		if responseErr.Response() == nil || responseErr.Response().StatusCode != status {
+			t.Errorf("status %d : missing response", status)
+		}
+	}
+}
+
+func TestErrorsThroughRetries(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "merchant", "public", "private")
+	c.Retry = &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}
+	_, err := c.FindTransaction(context.Background(), "tx1")
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) || !errors.Is(err, ErrDownForMaintenance) {
+		t.Fatalf("got %v", err)
+	}
+	if errors.Is(err, ErrNotFound) {
+		t.Fatalf("%v should not be a not found", err)
+	}
+}
+
+func TestErrorsDeclinedAndRejected(t *testing.T) {
+	t.Parallel()
+
+	c, _, stop := fakeClient(t)
+	defer stop()
+	ctx := context.Background()
+
+	_, err := c.Pay(ctx, &TxRequest{Type: "sale", Amount: NewDecimal(200000, 2), PaymentMethodNonce: "fake-valid-nonce"})
+	if !errors.Is(err, ErrProcessorDeclined) || errors.Is(err, ErrGatewayRejected) {
+		t.Fatalf("expected a processor decline, got %v", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Path() != "transactions" || apiErr.RequestID() == "" {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	_, err = c.Pay(ctx, &TxRequest{Type: "sale", Amount: NewDecimal(1000, 2), CreditCard: &CreditCard{Number: "4000111111111511", ExpirationDate: "05/30"}})
+	if !errors.Is(err, ErrGatewayRejected) || errors.Is(err, ErrProcessorDeclined) {
+		t.Fatalf("expected a gateway rejection, got %v", err)
+	}
+
+	_, err = c.Pay(ctx, &TxRequest{Type: "sale"})
+	if errors.Is(err, ErrProcessorDeclined) || errors.Is(err, ErrNotFound) || !errors.As(err, &apiErr) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+
+	production := New(ProductionURL, "merchant", "public", "private")
+	if _, err := production.SandboxSettle(ctx, "tx1"); !errors.Is(err, ErrNotAllowedInProduction) {
+		t.Fatalf("got %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+// +build unit
+
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	. "github.com/badu/braintree"
+)
+
+func TestExportTxs(t *testing.T) {
+	t.Parallel()
+
+	c, _, stop := fakeClient(t)
+	defer stop()
+	ctx := context.Background()
+
+	orderID := RandomString()
+	for _, amount := range []int64{1000, 2050, 3000} {
+		_, err := c.Pay(ctx, &TxRequest{
+			Type:       "sale",
+			Amount:     NewDecimal(amount, 2),
+			OrderId:    orderID,
+			CreditCard: &CreditCard{Number: testCardVisa, ExpirationDate: "05/30"},
+			Customer:   &CustomerRequest{Email: "finance@acme.com"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	s := NewTransactionSearch()
+	s.OrderID().Is = orderID
+	query, err := s.Search()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	columns := []string{"id", "status", "amount", "currency", "created_at", "credit_card.last4", "customer.email", "descriptor.name", "disputes.0.case_number"}
+	var out bytes.Buffer
+	e := &Exporter{Client: c, Format: ExportCSV, Columns: columns, Concurrency: 2}
+	n, err := e.ExportTxs(ctx, &out, query)
+	if err != nil || n != 3 {
+		t.Fatalf("exported %d (%v)", n, err)
+	}
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 || strings.Join(rows[0], ",") != strings.Join(columns, ",") {
+		t.Fatalf("unexpected rows %v", rows)
+	}
+	amounts := map[string]bool{}
+	for _, row := range rows[1:] {
+		amounts[row[2]] = true
+		if row[0] == "" || row[1] != string(StatusAuthorized) || row[3] != "USD" || row[4] == "" ||
+			row[5] != testCardVisa[len(testCardVisa)-4:] || row[6] != "finance@acme.com" || row[7] != "" || row[8] != "" {
+			t.Errorf("unexpected row %v", row)
+		}
+	}
+	if !amounts["10.00"] || !amounts["20.50"] || !amounts["30.00"] {
+		t.Errorf("unexpected amounts %v", amounts)
+	}
+
+	out.Reset()
+	e = &Exporter{Client: c, Format: ExportJSONL, Columns: []string{"id", "amount", "tax_exempt", "descriptor.name"}}
+	if n, err = e.ExportTxs(ctx, &out, query); err != nil || n != 3 {
+		t.Fatalf("exported %d (%v)", n, err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], `{"id":"`) {
+		t.Fatalf("unexpected lines %q", lines)
+	}
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatal(err)
+	}
+	if record["tax_exempt"] != false || record["descriptor.name"] != nil || record["amount"] == nil {
+		t.Errorf("unexpected record %v", record)
+	}
+}
+
+func TestExportCustomersAndSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	c, _, stop := fakeClient(t)
+	defer stop()
+	ctx := context.Background()
+
+	company := RandomString()
+	customer, err := c.CreateCustomer(ctx, &CustomerRequest{
+		Company:    company,
+		CreditCard: &CreditCard{Number: testCardVisa, ExpirationDate: "05/2031"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub, err := c.CreateSubscription(ctx, &SubscriptionRequest{PlanId: "monthly", PaymentMethodToken: customer.DefaultCreditCard().Token})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	customers := NewCustomerSearch()
+	customers.Company().Is = company
+	query, err := customers.Search()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	e := &Exporter{Client: c, Format: ExportCSV, Columns: []string{"id", "company", "credit_cards.credit_card.0.last_4"}}
+	if n, err := e.ExportCustomers(ctx, &out, query); err != nil || n != 1 {
+		t.Fatalf("exported %d (%v)", n, err)
+	}
+	if want := "id,company,credit_cards.credit_card.0.last_4\n" + customer.Id + "," + company + ",1111\n"; out.String() != want {
+		t.Errorf("expected %q, got %q", want, out.String())
+	}
+
+	query, err = NewSubscriptionSearch().IDs(sub.Id).Search()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	e = &Exporter{Client: c, Format: ExportCSV}
+	if n, err := e.ExportSubscriptions(ctx, &out, query); err != nil || n != 1 {
+		t.Fatalf("exported %d (%v)", n, err)
+	}
+	if !strings.HasPrefix(out.String(), "id,plan_id,status,price,next_billing_date,created_at\n"+sub.Id+",monthly,Active,10.00,") {
+		t.Errorf("unexpected export %q", out.String())
+	}
+}
+
+func TestExportRejectsUnknownColumns(t *testing.T) {
+	t.Parallel()
+
+	for _, column := range []string{"nope", "credit_card", "amount.value", "disputes.first", "customer.credit_cards"} {
+		e := &Exporter{Format: ExportCSV, Columns: []string{"id", column}}
+		var out bytes.Buffer
+		if _, err := e.ExportTxs(context.Background(), &out, nil); err == nil || !strings.Contains(err.Error(), column) {
+			t.Errorf("%s : expected an error, got %v", column, err)
+		}
+		if out.Len() != 0 {
+			t.Errorf("%s : wrote %q", column, out.String())
+		}
+	}
+}
@@ -0,0 +1,243 @@
+// +build unit
+
+package tests
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/badu/braintree"
+	"github.com/badu/braintree/fakegateway"
+)
+
+func fakeClient(t *testing.T) (*APIClient, *fakegateway.Server, func()) {
+	gateway := fakegateway.New()
+	gateway.MerchantID, gateway.PublicKey, gateway.PrivateKey = "merchant", "public", "private"
+	gateway.AddPlan(&Plan{Id: "monthly", Name: "Monthly", Price: NewDecimal(1000, 2), BillingFrequency: IntPtr(1)})
+	srv := httptest.NewServer(gateway)
+	return New(srv.URL, "merchant", "public", "private"), gateway, srv.Close
+}
+
+func validationCodes(err error) []string {
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		return nil
+	}
+	var codes []string
+	for _, e := range apiErr.All() {
+		codes = append(codes, e.Code)
+	}
+	return codes
+}
+
+func TestFakeGatewayTransactionLifecycle(t *testing.T) {
+	t.Parallel()
+
+	c, gateway, stop := fakeClient(t)
+	defer stop()
+	ctx := context.Background()
+
+	customer, err := c.CreateCustomer(ctx, &CustomerRequest{
+		FirstName:  "Lucy",
+		Email:      "lucy@example.com",
+		CreditCard: &CreditCard{Number: testCardVisa, ExpirationDate: "05/30", CVV: "123"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	card := customer.DefaultCreditCard()
+	if card == nil || card.Last4 != "1111" || card.CardType != "Visa" || card.Number != "" {
+		t.Fatalf("unexpected card %+v", card)
+	}
+
+	tx, err := c.Pay(ctx, &TxRequest{Type: "sale", Amount: NewDecimal(1000, 2), PaymentMethodToken: card.Token})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Status != StatusAuthorized || tx.ProcessorResponseCode != 1000 || tx.Customer.Id != customer.Id {
+		t.Fatalf("unexpected transaction %+v", tx)
+	}
+
+	_, err = c.Refund(ctx, tx.Id)
+	if codes := validationCodes(err); len(codes) != 1 || codes[0] != "91506" {
+		t.Fatalf("refunding an unsettled transaction : got %v (%v)", codes, err)
+	}
+
+	if tx, err = c.SubmitForSettlement(ctx, tx.Id, NewDecimal(800, 2)); err != nil {
+		t.Fatal(err)
+	}
+	if tx.Status != StatusSubmittedForSettlement || tx.Amount.String() != "8.00" {
+		t.Fatalf("unexpected transaction %+v", tx)
+	}
+	if tx, err = c.SandboxSettle(ctx, tx.Id); err != nil || tx.Status != StatusSettled {
+		t.Fatalf("settle : %v %+v", err, tx)
+	}
+	_, err = c.Void(ctx, tx.Id)
+	if codes := validationCodes(err); len(codes) != 1 || codes[0] != "91504" {
+		t.Fatalf("voiding a settled transaction : got %v (%v)", codes, err)
+	}
+
+	refund, err := c.Refund(ctx, tx.Id, NewDecimal(500, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refund.Type != "credit" || refund.RefundedTransactionId == nil || *refund.RefundedTransactionId != tx.Id {
+		t.Fatalf("unexpected refund %+v", refund)
+	}
+	_, err = c.Refund(ctx, tx.Id, NewDecimal(301, 2))
+	if codes := validationCodes(err); len(codes) != 1 || codes[0] != "91521" {
+		t.Fatalf("refunding too much : got %v (%v)", codes, err)
+	}
+	stored, ok := gateway.Transaction(tx.Id)
+	if !ok || stored.RefundIds == nil || len(*stored.RefundIds) != 1 {
+		t.Fatalf("unexpected stored transaction %+v", stored)
+	}
+
+	search := &Search{}
+	search.AddTextField("customer-email").Is = "LUCY@example.com"
+	search.AddMultiField("status").Items = []string{string(StatusSettled)}
+	result, err := c.SearchTxs(ctx, search)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.IDs) != 1 || result.IDs[0] != tx.Id {
+		t.Fatalf("got ids %v, want [%s]", result.IDs, tx.Id)
+	}
+	result.Page = 1
+	page, err := c.SearchTx(ctx, search, result)
+	if err != nil || len(page.Transactions) != 1 || page.Transactions[0].Id != tx.Id {
+		t.Fatalf("search page : %v %+v", err, page)
+	}
+
+	summary, err := c.GenerateSettlement(ctx, &Settlement{Date: time.Now().UTC().Format(DateFormat)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summary.Records.Type) != 1 || summary.Records.Type[0].AmountSettled.String() != "8.00" {
+		t.Fatalf("unexpected settlement %+v", summary.Records)
+	}
+}
+
+func TestFakeGatewayErrors(t *testing.T) {
+	t.Parallel()
+
+	c, _, stop := fakeClient(t)
+	defer stop()
+	ctx := context.Background()
+
+	_, err := c.Pay(ctx, &TxRequest{Type: "sale", CreditCard: &CreditCard{Number: testCardVisa, ExpirationDate: "05/30"}})
+	if codes := validationCodes(err); len(codes) != 1 || codes[0] != "81502" {
+		t.Fatalf("missing amount : got %v (%v)", codes, err)
+	}
+	_, err = c.Pay(ctx, &TxRequest{Type: "sale", Amount: NewDecimal(1000, 2), CreditCard: &CreditCard{Number: "4111111111111112"}})
+	if codes := validationCodes(err); len(codes) != 2 || codes[0] != "81715" || codes[1] != "81709" {
+		t.Fatalf("invalid card : got %v (%v)", codes, err)
+	}
+
+	_, err = c.Pay(ctx, &TxRequest{Type: "sale", Amount: NewDecimal(200100, 2), PaymentMethodNonce: "fake-valid-nonce"})
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.StatusCode() != 422 || apiErr.Error() != "Insufficient Funds" {
+		t.Fatalf("expected a decline, got %v", err)
+	}
+	if apiErr.Transaction == nil || apiErr.Transaction.Status != StatusProcessorDeclined || apiErr.Transaction.ProcessorResponseCode != 2001 {
+		t.Fatalf("unexpected declined transaction %+v", apiErr.Transaction)
+	}
+
+	_, err = c.Pay(ctx, &TxRequest{Type: "sale", Amount: NewDecimal(1000, 2), CreditCard: &CreditCard{Number: "4000111111111511", ExpirationDate: "05/30"}})
+	apiErr, ok = err.(*APIError)
+	if !ok || apiErr.Transaction == nil || apiErr.Transaction.GatewayRejectionReason != FraudReason {
+		t.Fatalf("expected a fraud rejection, got %v", err)
+	}
+
+	_, err = c.FindTransaction(ctx, "missing")
+	if httpErr, ok := err.(IAPIError); !ok || httpErr.StatusCode() != 404 {
+		t.Fatalf("expected a 404, got %v", err)
+	}
+
+	unauthorized := New(c.Key.URL, "merchant", "public", "wrong")
+	_, err = unauthorized.FindCustomer(ctx, "any")
+	if httpErr, ok := err.(IAPIError); !ok || httpErr.StatusCode() != 401 {
+		t.Fatalf("expected a 401, got %v", err)
+	}
+}
+
+func TestFakeGatewaySubscriptionsAndDisputes(t *testing.T) {
+	t.Parallel()
+
+	c, gateway, stop := fakeClient(t)
+	defer stop()
+	ctx := context.Background()
+
+	customer, err := c.CreateCustomer(ctx, &CustomerRequest{PaymentMethodNonce: "fake-valid-mastercard-nonce"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := customer.DefaultCreditCard().Token
+
+	sub, err := c.CreateSubscription(ctx, &SubscriptionRequest{PlanId: "monthly", PaymentMethodToken: token})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub.Status != SubscriptionStatusActive || sub.Price.String() != "10.00" || sub.Transactions == nil || len(sub.Transactions.Transaction) != 1 {
+		t.Fatalf("unexpected subscription %+v", sub)
+	}
+	if tx := sub.Transactions.Transaction[0]; tx.SubscriptionId != sub.Id || tx.Status != StatusSubmittedForSettlement {
+		t.Fatalf("unexpected subscription transaction %+v", tx)
+	}
+
+	_, err = c.CreateSubscription(ctx, &SubscriptionRequest{PlanId: "yearly", PaymentMethodToken: token})
+	if codes := validationCodes(err); len(codes) != 1 || codes[0] != "91904" {
+		t.Fatalf("unknown plan : got %v (%v)", codes, err)
+	}
+
+	if sub, err = c.CancelSubscription(ctx, sub.Id); err != nil || sub.Status != SubscriptionStatusCanceled {
+		t.Fatalf("cancel : %v %+v", err, sub)
+	}
+	_, err = c.CancelSubscription(ctx, sub.Id)
+	if codes := validationCodes(err); len(codes) != 1 || codes[0] != "81905" {
+		t.Fatalf("canceling twice : got %v (%v)", codes, err)
+	}
+
+	search := &Search{}
+	search.AddMultiField("status").Items = []string{string(SubscriptionStatusCanceled)}
+	result, err := c.SearchSubscriptions(ctx, search)
+	if err != nil || len(result.IDs) != 1 || result.IDs[0] != sub.Id {
+		t.Fatalf("search : %v %+v", err, result)
+	}
+
+	tx, err := c.Pay(ctx, &TxRequest{
+		Type:       "sale",
+		Amount:     NewDecimal(1000, 2),
+		CreditCard: &CreditCard{Number: "4023898493988028", ExpirationDate: "05/30"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tx.Disputes) != 1 || tx.Disputes[0].Status != DisputeStatusOpen {
+		t.Fatalf("expected an open dispute, got %+v", tx.Disputes)
+	}
+	disputeID := tx.Disputes[0].ID
+	evidence, err := c.AddTextEvidence(ctx, disputeID, &DisputeTextEvidenceRequest{Content: "tracking number 123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.RemoveEvidence(ctx, disputeID, evidence.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Accept(ctx, disputeID); err != nil {
+		t.Fatal(err)
+	}
+	dispute, err := c.FindDispute(ctx, disputeID)
+	if err != nil || dispute.Status != DisputeStatusAccepted || dispute.Transaction.ID != tx.Id {
+		t.Fatalf("find dispute : %v %+v", err, dispute)
+	}
+	if err := c.Finalize(ctx, disputeID); err == nil {
+		t.Fatal("finalizing an accepted dispute should fail")
+	}
+
+	if _, ok := gateway.OpenDispute("missing", FraudDisputeReason); ok {
+		t.Fatal("opened a dispute on a missing transaction")
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"encoding/binary"
 	"fmt"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"regexp"
 	"strconv"
@@ -16,6 +18,8 @@ import (
 	"time"
 
 	. "github.com/badu/braintree"
+	"github.com/badu/braintree/cassette"
+	"github.com/badu/braintree/fakegateway"
 )
 
 const (
@@ -76,8 +80,49 @@ func testSubMerchantAccount() string {
 
 func TestMain(m *testing.M) {
 	client = NewWithHttpClient(SandboxURL, "4ngqq224rnk6gvxh", "jkq28pcxj4r85dwr", "66062a3876e2dc298f2195f0bf173f5a", DefaultClient)
-	client.Logger = log.New(os.Stderr, "", 0)
-	m.Run()
+	client.Logger = NewStdLogger(log.New(os.Stderr, "", 0), LevelDebug)
+
+	// BRAINTREE_GATEWAY=fake runs the suite against the fake gateway instead of the sandbox, which serves only
+	// part of it : the cassette CI replays is recorded this way, testing the client against the fake gateway
+	// rather than the sandbox (see the Makefile)
+	var fake *httptest.Server
+	if os.Getenv("BRAINTREE_GATEWAY") == "fake" {
+		gateway := fakegateway.New()
+		gateway.MerchantID, gateway.PublicKey, gateway.PrivateKey = client.Key.MerchId, client.Key.PublicKey, client.Key.PrivateKey
+		fake = httptest.NewServer(gateway)
+		client.Key.URL = fake.URL
+	}
+
+	// BRAINTREE_CASSETTE points to a cassette file : the suite replays the interactions recorded there,
+	// recording the missing ones unless BRAINTREE_CASSETTE_MODE is "replay" (e.g. in CI, where there is no network)
+	var recorder *cassette.Recorder
+	if path := os.Getenv("BRAINTREE_CASSETTE"); path != "" {
+		mode := cassette.ModeAuto
+		switch os.Getenv("BRAINTREE_CASSETTE_MODE") {
+		case "replay":
+			mode = cassette.ModeReplay
+		case "record":
+			mode = cassette.ModeRecord
+		}
+		var err error
+		recorder, err = cassette.New(path, mode)
+		if err != nil {
+			panic(fmt.Errorf("error loading cassette %s: %s", path, err))
+		}
+		client.Client = &http.Client{Timeout: DefaultClient.Timeout, Transport: recorder}
+	}
+
+	code := m.Run()
+	if fake != nil {
+		fake.Close()
+	}
+
+	if recorder != nil {
+		if err := recorder.Save(); err != nil {
+			panic(fmt.Errorf("error saving cassette: %s", err))
+		}
+	}
+	os.Exit(code)
 }
 
 var ValidBIN = regexp.MustCompile(`^\d{6}$`).MatchString
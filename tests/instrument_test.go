@@ -0,0 +1,210 @@
+// +build unit
+
+package tests
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/badu/braintree"
+)
+
+const declinedTxXML = `<api-error-response>
+  <message>Do Not Honor</message>
+  <errors>
+    <errors type="array"/>
+    <transaction>
+      <errors type="array">
+        <error>
+          <code>81502</code>
+          <attribute type="symbol">amount</attribute>
+          <message>Amount is required.</message>
+        </error>
+      </errors>
+    </transaction>
+  </errors>
+  <transaction>
+    <id>tx2</id>
+    <status>processor_declined</status>
+    <processor-response-code>2000</processor-response-code>
+    <processor-response-type>soft_declined</processor-response-type>
+  </transaction>
+</api-error-response>`
+
+func TestInstrumentationCollectsMetrics(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch {
+		case r.Method == http.MethodGet && calls == 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`<transaction><id>tx1</id><processor-response-type>approved</processor-response-type></transaction>`))
+		default:
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(declinedTxXML))
+		}
+	}))
+	defer srv.Close()
+
+	collector := NewMemoryCollector()
+	c := New(srv.URL, "merchant", "public", "private")
+	c.Retry = &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}
+	c.Instrumentation = collector
+
+	if _, err := c.FindTransaction(context.Background(), "tx1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Pay(context.Background(), &TxRequest{Type: "sale"}); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	recorded := collector.Calls()
+	if len(recorded) != 2 {
+		t.Fatalf("got %d calls, want 2", len(recorded))
+	}
+	find, pay := recorded[0], recorded[1]
+	if find.Operation != "FindTransaction" || find.Attempts != 2 || find.StatusCode != http.StatusOK || find.ProcessorResponseType != ResponseTypeApproved {
+		t.Fatalf("unexpected metrics %+v", find)
+	}
+	if find.MerchantID != "merchant" || find.Path != "transactions/tx1" {
+		t.Fatalf("unexpected span %+v", find.Span)
+	}
+	if pay.Operation != "Pay" || pay.StatusCode != http.StatusUnprocessableEntity || pay.Err == nil {
+		t.Fatalf("unexpected metrics %+v", pay)
+	}
+	if len(pay.ValidationCodes) != 1 || pay.ValidationCodes[0] != "81502" {
+		t.Fatalf("got validation codes %v", pay.ValidationCodes)
+	}
+	if pay.ProcessorResponseType != ResponseTypeSoftDeclined {
+		t.Fatalf("got processor response type %q", pay.ProcessorResponseType)
+	}
+	if collector.Active() != 0 {
+		t.Fatalf("got %d active spans", collector.Active())
+	}
+
+	var buf bytes.Buffer
+	if err := collector.WritePrometheus(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, line := range []string{
+		`# TYPE braintree_requests_total counter`,
+		`braintree_requests_total{operation="FindTransaction",status="200"} 1`,
+		`braintree_requests_total{operation="Pay",status="422"} 1`,
+		`braintree_retries_total{operation="FindTransaction"} 1`,
+		`braintree_validation_errors_total{operation="Pay",code="81502"} 1`,
+		`braintree_processor_responses_total{operation="Pay",type="soft_declined"} 1`,
+		`braintree_request_duration_seconds_bucket{operation="Pay",le="+Inf"} 1`,
+		`braintree_request_duration_seconds_count{operation="FindTransaction"} 1`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("missing %q in\n%s", line, out)
+		}
+	}
+}
+
+func TestMemoryCollectorKeepsRecentCalls(t *testing.T) {
+	c, _, done := fakeClient(t)
+	defer done()
+	ctx := context.Background()
+
+	collector := NewMemoryCollector()
+	collector.MaxCalls = 2
+	c.Instrumentation = collector
+
+	tx, err := c.Pay(ctx, &TxRequest{Type: TxTypeSale, Amount: NewDecimal(1000, 2), PaymentMethodNonce: "fake-valid-nonce"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.SubmitForSettlement(ctx, tx.Id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.SandboxSettle(ctx, tx.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	recorded := collector.Calls()
+	if len(recorded) != 2 || recorded[0].Operation != "SubmitForSettlement" || recorded[1].Operation != "SandboxSettle" {
+		t.Fatalf("got %d calls, want the last two", len(recorded))
+	}
+	var buf bytes.Buffer
+	if err := collector.WritePrometheus(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if line := `braintree_requests_total{operation="Pay",status="201"} 1`; !strings.Contains(buf.String(), line+"\n") {
+		t.Fatalf("missing %q in\n%s", line, buf.String())
+	}
+}
+
+func TestMemoryCollectorBucketsChange(t *testing.T) {
+	c, _, done := fakeClient(t)
+	defer done()
+	ctx := context.Background()
+
+	collector := NewMemoryCollector()
+	c.Instrumentation = collector
+
+	if _, err := c.Pay(ctx, &TxRequest{Type: TxTypeSale, Amount: NewDecimal(1000, 2), PaymentMethodNonce: "fake-valid-nonce"}); err != nil {
+		t.Fatal(err)
+	}
+	collector.Buckets = append(append([]float64(nil), DefaultDurationBuckets...), 120)
+	if _, err := c.Pay(ctx, &TxRequest{Type: TxTypeSale, Amount: NewDecimal(1000, 2), PaymentMethodNonce: "fake-valid-nonce"}); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := collector.WritePrometheus(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if line := `braintree_request_duration_seconds_bucket{operation="Pay",le="60"} 2`; !strings.Contains(buf.String(), line+"\n") {
+		t.Fatalf("missing %q in\n%s", line, buf.String())
+	}
+	if strings.Contains(buf.String(), `le="120"`) {
+		t.Fatalf("expected the buckets of Pay to be the ones it was first seen with, got\n%s", buf.String())
+	}
+}
+
+func TestInstrumentationProcessorResponseTypes(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/verifications/v1"):
+			_, _ = w.Write([]byte(`<verification><id>v1</id><processor-response-type>hard_declined</processor-response-type></verification>`))
+		case strings.HasSuffix(r.URL.Path, "/transactions/advanced_search_ids"):
+			_, _ = w.Write([]byte(`<search-results><page-size>50</page-size><ids type="array"><item>tx1</item></ids></search-results>`))
+		default:
+			_, _ = w.Write([]byte(`<transaction><id>tx1</id><disbursement-details><processor-response-type>x</processor-response-type></disbursement-details><processor-response-type>approved</processor-response-type></transaction>`))
+		}
+	}))
+	defer srv.Close()
+
+	collector := NewMemoryCollector()
+	c := New(srv.URL, "merchant", "public", "private")
+	c.Instrumentation = collector
+	ctx := context.Background()
+	if _, err := c.FindVerification(ctx, "v1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.SearchTxs(ctx, &Search{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.FindTransaction(ctx, "tx1"); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []ResponseType
+	for _, call := range collector.Calls() {
+		got = append(got, call.ProcessorResponseType)
+	}
+	if len(got) != 3 || got[0] != ResponseTypeHardDeclined || got[1] != "" || got[2] != ResponseTypeApproved {
+		t.Fatalf("got processor response types %q", got)
+	}
+}
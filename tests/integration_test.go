@@ -1989,8 +1989,8 @@ func TestSubscriptionAllFieldsWithBillingDayOfMonth(t *testing.T) {
 	if sub1.Id == "" {
 		t.Fatal("invalid subscription id")
 	}
-	if sub1.BillingDayOfMonth != "15" {
-		t.Fatalf("got billing day of month %#v, want %#v", sub1.BillingDayOfMonth, "15")
+	if sub1.BillingDayOfMonth != 15 {
+		t.Fatalf("got billing day of month %#v, want %#v", sub1.BillingDayOfMonth, 15)
 	}
 	if x := sub1.NeverExpires; x {
 		t.Fatalf("got never expires %#v, want false", x)
@@ -2098,8 +2098,8 @@ func TestSubscriptionAllFieldsWithBillingDayOfMonthNeverExpires(t *testing.T) {
 	if sub1.Id == "" {
 		t.Fatal("invalid subscription id")
 	}
-	if sub1.BillingDayOfMonth != "15" {
-		t.Fatalf("got billing day of month %#v, want %#v", sub1.BillingDayOfMonth, "15")
+	if sub1.BillingDayOfMonth != 15 {
+		t.Fatalf("got billing day of month %#v, want %#v", sub1.BillingDayOfMonth, 15)
 	}
 	if x := sub1.NeverExpires; !x {
 		t.Fatalf("got never expires %#v, want true", x)
@@ -2182,7 +2182,7 @@ func TestSubscriptionAllFieldsWithFirstBillingDate(t *testing.T) {
 	}
 
 	// Create
-	firstBillingDate := fmt.Sprintf("%d-12-31", time.Now().Year())
+	firstBillingDate := &Date{Time: time.Date(time.Now().Year(), time.December, 31, 0, 0, 0, 0, time.UTC)}
 	sub1, err := client.CreateSubscription(context.Background(), &SubscriptionRequest{
 		PaymentMethodToken:    paymentMethod.Token,
 		PlanId:                "test_plan",
@@ -2203,11 +2203,11 @@ func TestSubscriptionAllFieldsWithFirstBillingDate(t *testing.T) {
 	if sub1.Id == "" {
 		t.Fatal("invalid subscription id")
 	}
-	if sub1.BillingDayOfMonth != "31" {
-		t.Fatalf("got billing day of month %#v, want %#v", sub1.BillingDayOfMonth, "31")
+	if sub1.BillingDayOfMonth != 31 {
+		t.Fatalf("got billing day of month %#v, want %#v", sub1.BillingDayOfMonth, 31)
 	}
-	if sub1.FirstBillingDate != firstBillingDate {
-		t.Fatalf("got first billing date %#v, want %#v", sub1.FirstBillingDate, firstBillingDate)
+	if sub1.RawFirstBillingDate() != firstBillingDate.Format(DateFormat) {
+		t.Fatalf("got first billing date %#v, want %#v", sub1.RawFirstBillingDate(), firstBillingDate.Format(DateFormat))
 	}
 	if x := sub1.NeverExpires; x {
 		t.Fatalf("got never expires %#v, want false", x)
@@ -2292,7 +2292,7 @@ func TestSubscriptionAllFieldsWithFirstBillingDateNeverExpires(t *testing.T) {
 	}
 
 	// Create
-	firstBillingDate := fmt.Sprintf("%d-12-31", time.Now().Year())
+	firstBillingDate := &Date{Time: time.Date(time.Now().Year(), time.December, 31, 0, 0, 0, 0, time.UTC)}
 	sub1, err := client.CreateSubscription(context.Background(), &SubscriptionRequest{
 		PaymentMethodToken: paymentMethod.Token,
 		PlanId:             "test_plan",
@@ -2313,11 +2313,11 @@ func TestSubscriptionAllFieldsWithFirstBillingDateNeverExpires(t *testing.T) {
 	if sub1.Id == "" {
 		t.Fatal("invalid subscription id")
 	}
-	if sub1.BillingDayOfMonth != "31" {
-		t.Fatalf("got billing day of month %#v, want %#v", sub1.BillingDayOfMonth, "31")
+	if sub1.BillingDayOfMonth != 31 {
+		t.Fatalf("got billing day of month %#v, want %#v", sub1.BillingDayOfMonth, 31)
 	}
-	if sub1.FirstBillingDate != firstBillingDate {
-		t.Fatalf("got first billing date %#v, want %#v", sub1.FirstBillingDate, firstBillingDate)
+	if sub1.RawFirstBillingDate() != firstBillingDate.Format(DateFormat) {
+		t.Fatalf("got first billing date %#v, want %#v", sub1.RawFirstBillingDate(), firstBillingDate.Format(DateFormat))
 	}
 	if x := sub1.NeverExpires; !x {
 		t.Fatalf("got never expires %#v, want true", x)
@@ -2423,11 +2423,11 @@ func TestSubscriptionAllFieldsWithTrialPeriod(t *testing.T) {
 	if sub1.Id == "" {
 		t.Fatal("invalid subscription id")
 	}
-	if sub1.BillingDayOfMonth != fmt.Sprintf("%d", firstBillingDate.Day()) {
+	if sub1.BillingDayOfMonth != firstBillingDate.Day() {
 		t.Fatalf("got billing day of month %#v, want %#v", sub1.BillingDayOfMonth, firstBillingDate.Day())
 	}
-	if sub1.FirstBillingDate != firstBillingDate.Format(DateFormat) {
-		t.Fatalf("got first billing date %#v, want %#v", sub1.FirstBillingDate, firstBillingDate)
+	if sub1.RawFirstBillingDate() != firstBillingDate.Format(DateFormat) {
+		t.Fatalf("got first billing date %#v, want %#v", sub1.RawFirstBillingDate(), firstBillingDate)
 	}
 	if x := sub1.NeverExpires; x {
 		t.Fatalf("got never expires %#v, want false", x)
@@ -2441,7 +2441,7 @@ func TestSubscriptionAllFieldsWithTrialPeriod(t *testing.T) {
 	if x := sub1.TrialPeriod; !x {
 		t.Fatalf("got trial period %#v, want false", x)
 	}
-	if sub1.TrialDuration != "7" {
+	if sub1.TrialDuration != 7 {
 		t.Fatalf("got trial duration %#v, want 7", sub1.TrialDuration)
 	}
 	if sub1.TrialDurationUnit != Day {
@@ -2536,11 +2536,11 @@ func TestSubscriptionAllFieldsWithTrialPeriodNeverExpires(t *testing.T) {
 	if sub1.Id == "" {
 		t.Fatal("invalid subscription id")
 	}
-	if sub1.BillingDayOfMonth != fmt.Sprintf("%d", firstBillingDate.Day()) {
+	if sub1.BillingDayOfMonth != firstBillingDate.Day() {
 		t.Fatalf("got billing day of month %#v, want %#v", sub1.BillingDayOfMonth, firstBillingDate.Day())
 	}
-	if sub1.FirstBillingDate != firstBillingDate.Format(DateFormat) {
-		t.Fatalf("got first billing date %#v, want %#v", sub1.FirstBillingDate, firstBillingDate)
+	if sub1.RawFirstBillingDate() != firstBillingDate.Format(DateFormat) {
+		t.Fatalf("got first billing date %#v, want %#v", sub1.RawFirstBillingDate(), firstBillingDate)
 	}
 	if x := sub1.NeverExpires; !x {
 		t.Fatalf("got never expires %#v, want true", x)
@@ -2554,7 +2554,7 @@ func TestSubscriptionAllFieldsWithTrialPeriodNeverExpires(t *testing.T) {
 	if x := sub1.TrialPeriod; !x {
 		t.Fatalf("got trial period %#v, want false", x)
 	}
-	if sub1.TrialDuration != "7" {
+	if sub1.TrialDuration != 7 {
 		t.Fatalf("got trial duration %#v, want 7", sub1.TrialDuration)
 	}
 	if sub1.TrialDurationUnit != Day {
@@ -2793,10 +2793,10 @@ func TestSubscriptionTransactions(t *testing.T) {
 	if x := sub2.Transactions.Transaction[0].SubscriptionId; x != sub.Id {
 		t.Fatal(x)
 	}
-	if x := sub2.Transactions.Transaction[0].SubscriptionDetails.BillingPeriodStartDate; x != sub.BillingPeriodStartDate {
+	if x := sub2.Transactions.Transaction[0].SubscriptionDetails.RawBillingPeriodStartDate(); x != sub.RawBillingPeriodStartDate() {
 		t.Fatal(x)
 	}
-	if x := sub2.Transactions.Transaction[0].SubscriptionDetails.BillingPeriodEndDate; x != sub.BillingPeriodEndDate {
+	if x := sub2.Transactions.Transaction[0].SubscriptionDetails.RawBillingPeriodEndDate(); x != sub.RawBillingPeriodEndDate() {
 		t.Fatal(x)
 	}
 
@@ -4839,7 +4839,7 @@ func TestSettleTransaction(t *testing.T) {
 	prodGateway := New(ProductionURL, "my_merchant_id", "my_public_key", "my_private_key")
 
 	_, err = prodGateway.SandboxSettle(context.Background(), txn.Id)
-	if err.Error() != "Operation not allowed in production environment" {
+	if err.Error() != "braintree: operation not allowed in production environment" {
 		t.Fatal(err)
 	}
 
@@ -4875,7 +4875,7 @@ func TestSettlementConfirmTransaction(t *testing.T) {
 	prodGateway := New(ProductionURL, "my_merchant_id", "my_public_key", "my_private_key")
 
 	_, err = prodGateway.SandboxSettlementConfirm(context.Background(), txn.Id)
-	if err.Error() != "Operation not allowed in production environment" {
+	if err.Error() != "braintree: operation not allowed in production environment" {
 		t.Fatal(err)
 	}
 
@@ -4911,7 +4911,7 @@ func TestSettlementDeclinedTransaction(t *testing.T) {
 	prodGateway := New(ProductionURL, "my_merchant_id", "my_public_key", "my_private_key")
 
 	_, err = prodGateway.SandboxSettlementDecline(context.Background(), txn.Id)
-	if err.Error() != "Operation not allowed in production environment" {
+	if err.Error() != "braintree: operation not allowed in production environment" {
 		t.Fatal(err)
 	}
 
@@ -4947,7 +4947,7 @@ func TestSettlementPendingTransaction(t *testing.T) {
 	prodGateway := New(ProductionURL, "my_merchant_id", "my_public_key", "my_private_key")
 
 	_, err = prodGateway.SandboxSettlementPending(context.Background(), txn.Id)
-	if err.Error() != "Operation not allowed in production environment" {
+	if err.Error() != "braintree: operation not allowed in production environment" {
 		t.Fatal(err)
 	}
 
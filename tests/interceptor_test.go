@@ -0,0 +1,134 @@
+// +build unit
+
+package tests
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/badu/braintree"
+)
+
+func TestInterceptorsChainOrder(t *testing.T) {
+	t.Parallel()
+
+	var gotHeader string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("X-Trace-Id")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`<transaction><id>tx1</id></transaction>`))
+	}))
+	defer srv.Close()
+
+	var order []string
+	record := func(name string) Interceptor {
+		return func(next RoundTrip) RoundTrip {
+			return func(ctx context.Context, call *Call) (*Response, error) {
+				order = append(order, name+">")
+				resp, err := next(ctx, call)
+				order = append(order, "<"+name)
+				return resp, err
+			}
+		}
+	}
+
+	var seen *Call
+	c := New(srv.URL, "merchant", "public", "private")
+	c.Interceptors = []Interceptor{
+		record("outer"),
+		record("inner"),
+		func(next RoundTrip) RoundTrip {
+			return func(ctx context.Context, call *Call) (*Response, error) {
+				seen = call
+				call.Header.Set("X-Trace-Id", "trace-1")
+				return next(ctx, call)
+			}
+		},
+	}
+
+	if _, err := c.Pay(context.Background(), &TxRequest{Type: "sale", Amount: NewDecimal(100, 2)}); err != nil {
+		t.Fatal(err)
+	}
+
+	if g, w := strings.Join(order, " "), "outer> inner> <inner <outer"; g != w {
+		t.Fatalf("got order %q, want %q", g, w)
+	}
+	if gotHeader != "trace-1" {
+		t.Fatalf("injected header not sent, got %q", gotHeader)
+	}
+	if seen.Method != http.MethodPost || seen.Path != "transactions" || seen.Version != 3 || seen.Attempt != 1 {
+		t.Fatalf("unexpected call %+v", seen)
+	}
+	if !bytes.Contains(seen.Payload, []byte("<amount>1.00</amount>")) {
+		t.Fatalf("payload not exposed to interceptors : %s", seen.Payload)
+	}
+}
+
+func TestInterceptorFaultInjectionIsRetried(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<transaction><id>tx1</id></transaction>`))
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "merchant", "public", "private")
+	c.Retry = &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}
+	c.Interceptors = []Interceptor{
+		func(next RoundTrip) RoundTrip {
+			return func(ctx context.Context, call *Call) (*Response, error) {
+				if call.Attempt == 1 {
+					return &Response{Response: &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{}}}, nil
+				}
+				return next(ctx, call)
+			}
+		},
+	}
+
+	tx, err := c.FindTransaction(context.Background(), "tx1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Id != "tx1" {
+		t.Fatalf("got id %q, want tx1", tx.Id)
+	}
+}
+
+func TestBuiltInInterceptors(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<transaction><id>tx1</id></transaction>`))
+	}))
+	defer srv.Close()
+
+	var (
+		buf     bytes.Buffer
+		elapsed time.Duration
+		status  int
+	)
+	c := New(srv.URL, "merchant", "public", "private")
+	c.Interceptors = []Interceptor{
+		LoggingInterceptor(NewStdLogger(log.New(&buf, "", 0), LevelInfo)),
+		TimingInterceptor(func(call *Call, response *Response, d time.Duration, err error) {
+			elapsed = d
+			status = response.StatusCode
+		}),
+	}
+
+	if _, err := c.FindTransaction(context.Background(), "tx1"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "INFO braintree call method=GET path=transactions/tx1 version=3 attempt=1 status=200") {
+		t.Fatalf("unexpected log output %q", buf.String())
+	}
+	if elapsed <= 0 || status != http.StatusOK {
+		t.Fatalf("timing interceptor not called, elapsed %v status %d", elapsed, status)
+	}
+}
@@ -0,0 +1,190 @@
+// +build unit
+
+package tests
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/badu/braintree"
+)
+
+func TestIterateTxs(t *testing.T) {
+	t.Parallel()
+
+	c, _, stop := fakeClient(t)
+	defer stop()
+	ctx := context.Background()
+
+	orderID := RandomString()
+	want := map[string]bool{}
+	for i := 0; i < 120; i++ {
+		tx, err := c.Pay(ctx, &TxRequest{Type: "sale", Amount: NewDecimal(1000, 2), OrderId: orderID, PaymentMethodNonce: "fake-valid-nonce"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want[tx.Id] = true
+	}
+	if _, err := c.Pay(ctx, &TxRequest{Type: "sale", Amount: NewDecimal(1000, 2), OrderId: "other", PaymentMethodNonce: "fake-valid-nonce"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, concurrency := range []int{0, 3} {
+		search := &Search{}
+		search.AddTextField("order-id").Is = orderID
+		it := c.IterateTxs(search)
+		it.Concurrency = concurrency
+		seen := map[string]bool{}
+		for it.Next(ctx) {
+			if seen[it.Value().Id] || !want[it.Value().Id] {
+				t.Fatalf("unexpected transaction %s", it.Value().Id)
+			}
+			seen[it.Value().Id] = true
+		}
+		if err := it.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if len(seen) != len(want) || it.Total() != len(want) || it.Value() != nil {
+			t.Fatalf("concurrency %d : got %d transactions out of %d", concurrency, len(seen), it.Total())
+		}
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	defer cancel()
+	search := &Search{}
+	search.AddTextField("order-id").Is = orderID
+	it := c.IterateTxs(search)
+	count := 0
+	for it.Next(cancelled) {
+		count++
+		if count == 10 {
+			cancel()
+		}
+	}
+	if it.Err() != context.Canceled || count != 10 {
+		t.Fatalf("expected the iteration to stop after 10 transactions, got %d (%v)", count, it.Err())
+	}
+}
+
+func TestIterateResumesAfterCancelledCall(t *testing.T) {
+	t.Parallel()
+
+	c, gateway, stop := fakeClient(t)
+	defer stop()
+	gateway.PageSize = 10
+	ctx := context.Background()
+
+	orderID := RandomString()
+	for i := 0; i < 30; i++ {
+		if _, err := c.Pay(ctx, &TxRequest{Type: "sale", Amount: NewDecimal(1000, 2), OrderId: orderID, PaymentMethodNonce: "fake-valid-nonce"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// while blocked, the pages are held until the context of their call is done
+	var blocked int32
+	held := make(chan struct{}, 2)
+	c.Interceptors = []Interceptor{func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, call *Call) (*Response, error) {
+			if call.Path == "transactions/advanced_search" && atomic.LoadInt32(&blocked) == 1 {
+				held <- struct{}{}
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			return next(ctx, call)
+		}
+	}}
+
+	search := &Search{}
+	search.AddTextField("order-id").Is = orderID
+	it := c.IterateTxs(search)
+	count := 0
+	for ; count < 10; count++ {
+		if !it.Next(ctx) {
+			t.Fatalf("stopped after %d transactions (%v)", count, it.Err())
+		}
+	}
+
+	// the next call fetches the two remaining pages ahead, and is cancelled meanwhile
+	atomic.StoreInt32(&blocked, 1)
+	it.Concurrency = 2
+	cancelled, cancel := context.WithCancel(ctx)
+	go func() {
+		<-held
+		<-held
+		cancel()
+	}()
+	if it.Next(cancelled) || it.Err() != context.Canceled {
+		t.Fatalf("got %v, want the call to be cancelled", it.Err())
+	}
+
+	atomic.StoreInt32(&blocked, 0)
+	for it.Next(ctx) {
+		count++
+	}
+	if err := it.Err(); err != nil || count != 30 {
+		t.Fatalf("got %d transactions (%v), want the iteration to resume", count, err)
+	}
+}
+
+func TestIterateCustomersSubscriptionsAndCards(t *testing.T) {
+	t.Parallel()
+
+	c, _, stop := fakeClient(t)
+	defer stop()
+	ctx := context.Background()
+
+	company := RandomString()
+	for i := 0; i < 3; i++ {
+		customer, err := c.CreateCustomer(ctx, &CustomerRequest{
+			Company:    company,
+			CreditCard: &CreditCard{Number: testCardVisa, ExpirationDate: "05/2031"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.CreateSubscription(ctx, &SubscriptionRequest{PlanId: "monthly", PaymentMethodToken: customer.DefaultCreditCard().Token}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	search := &Search{}
+	search.AddTextField("company").Is = company
+	customers := c.IterateCustomers(search)
+	count := 0
+	for customers.Next(ctx) {
+		if customers.Value().Company != company {
+			t.Fatalf("unexpected customer %+v", customers.Value())
+		}
+		count++
+	}
+	if customers.Err() != nil || count != 3 {
+		t.Fatalf("got %d customers (%v)", count, customers.Err())
+	}
+
+	search = &Search{}
+	search.AddTextField("plan-id").Is = "monthly"
+	subscriptions := c.IterateSubscriptions(search)
+	count = 0
+	for subscriptions.Next(ctx) {
+		count++
+	}
+	if subscriptions.Err() != nil || count != 3 {
+		t.Fatalf("got %d subscriptions (%v)", count, subscriptions.Err())
+	}
+
+	from := time.Date(2031, time.May, 1, 0, 0, 0, 0, time.UTC)
+	cards := c.IterateExpiringBetween(from, from)
+	count = 0
+	for cards.Next(ctx) {
+		if cards.Value().ExpirationYear != "2031" {
+			t.Fatalf("unexpected card %+v", cards.Value())
+		}
+		count++
+	}
+	if cards.Err() != nil || count != 3 {
+		t.Fatalf("got %d cards (%v)", count, cards.Err())
+	}
+}
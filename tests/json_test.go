@@ -0,0 +1,221 @@
+// +build unit
+
+package tests
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/badu/braintree"
+)
+
+// roundTrip encodes the record in JSON, decodes it in a new one, and checks that both encode to the same XML
+func roundTrip(t *testing.T, record interface{}) []byte {
+	t.Helper()
+	b, err := json.Marshal(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := reflect.New(reflect.TypeOf(record).Elem()).Interface()
+	if err := json.Unmarshal(b, decoded); err != nil {
+		t.Fatalf("%s : %v", b, err)
+	}
+	want, err := xml.Marshal(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := xml.Marshal(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(want, got) {
+		t.Fatalf("the JSON form changed the record\n%s\n%s", want, got)
+	}
+	return b
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	c, _, stop := fakeClient(t)
+	defer stop()
+	ctx := context.Background()
+
+	customer, err := c.CreateCustomer(ctx, &CustomerRequest{
+		FirstName:    "Jane",
+		Email:        "jane@example.com",
+		CustomFields: CustomFields{"segment": "gold"},
+		CreditCard: &CreditCard{
+			Number:         testCardVisa,
+			ExpirationDate: "05/2031",
+			BillingAddress: &Address{StreetAddress: "1 Main St", PostalCode: "60622"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := roundTrip(t, customer)
+	for _, key := range []string{`"first_name":"Jane"`, `"credit_cards":`, `"custom_fields":{"segment":"gold"}`, `"created_at":"`} {
+		if !strings.Contains(string(b), key) {
+			t.Errorf("missing %s in %s", key, b)
+		}
+	}
+
+	tx, err := c.Pay(ctx, &TxRequest{
+		Type:       "sale",
+		Amount:     NewDecimal(1050, 2),
+		OrderId:    "order-1",
+		CreditCard: &CreditCard{Number: "4023898493988028", ExpirationDate: "05/30"},
+		Options:    &TxOpts{SubmitForSettlement: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b = roundTrip(t, tx)
+	for _, key := range []string{`"amount":"10.50"`, `"currency_iso_code":"USD"`, `"order_id":"order-1"`, `"status":"submitted_for_settlement"`, `"disputes":[{`} {
+		if !strings.Contains(string(b), key) {
+			t.Errorf("missing %s in %s", key, b)
+		}
+	}
+	if strings.Contains(string(b), "XMLName") {
+		t.Errorf("the xml names leaked in %s", b)
+	}
+
+	dispute, err := c.FindDispute(ctx, tx.Disputes[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roundTrip(t, dispute)
+
+	sub, err := c.CreateSubscription(ctx, &SubscriptionRequest{PlanId: "monthly", PaymentMethodToken: customer.DefaultCreditCard().Token})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b = roundTrip(t, sub)
+	if !strings.Contains(string(b), `"plan_id":"monthly"`) || !strings.Contains(string(b), `"price":"10.00"`) {
+		t.Errorf("unexpected subscription %s", b)
+	}
+}
+
+func TestJSONSensitiveFields(t *testing.T) {
+	t.Parallel()
+
+	card := &CreditCard{Number: testCardVisa, CVV: "737", ExpirationDate: "05/2031", Last4: "1111"}
+	b, err := json.Marshal(card)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), testCardVisa) || strings.Contains(string(b), "737") || strings.Contains(string(b), `"number"`) || strings.Contains(string(b), `"cvv"`) {
+		t.Fatalf("the card number or the CVV leaked in %s", b)
+	}
+	if !strings.Contains(string(b), `"last_4":"1111"`) {
+		t.Fatalf("missing the last 4 in %s", b)
+	}
+
+	account := &MerchantAccount{
+		Individual:     &MerchantAccountPerson{FirstName: "Jane", SSN: "123-45-6789"},
+		Business:       &MerchantAccountBusiness{LegalName: "Jane's", TaxId: "98-7654321"},
+		FundingOptions: &MerchantAccountFundingOptions{AccountNumber: "1234567890", RoutingNumber: "071101307"},
+	}
+	if b, err = json.Marshal(account); err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"123-45-6789", "98-7654321", "1234567890", "071101307"} {
+		if strings.Contains(string(b), secret) {
+			t.Errorf("%s leaked in %s", secret, b)
+		}
+	}
+}
+
+func TestJSONDate(t *testing.T) {
+	t.Parallel()
+
+	d := &Disbursement{Id: "d1", DisbursementDate: &Date{Time: time.Date(2026, time.March, 4, 0, 0, 0, 0, time.UTC)}, Amount: NewDecimal(-1250, 2)}
+	b := roundTrip(t, d)
+	if !strings.Contains(string(b), `"disbursement_date":"2026-03-04"`) || !strings.Contains(string(b), `"amount":"-12.50"`) {
+		t.Fatalf("unexpected disbursement %s", b)
+	}
+	var bad Date
+	if err := json.Unmarshal([]byte(`"2026-03-04T10:00:00Z"`), &bad); err == nil {
+		t.Fatal("expected an error for a time")
+	}
+
+	var zero Date
+	if b, err := json.Marshal(zero); err != nil || string(b) != "null" {
+		t.Fatalf("got %s (%v), want null", b, err)
+	}
+	for _, src := range []string{`null`, `""`} {
+		decoded := Date{Time: time.Now()}
+		if err := json.Unmarshal([]byte(src), &decoded); err != nil || !decoded.IsZero() {
+			t.Errorf("%s : got %v (%v), want the zero date", src, decoded, err)
+		}
+	}
+}
+
+func TestSQLValues(t *testing.T) {
+	t.Parallel()
+
+	var d Decimal
+	for src, want := range map[interface{}]string{"10.50": "10.50", int64(7): "7", 2.25: "2.25"} {
+		if err := d.Scan(src); err != nil || d.String() != want {
+			t.Errorf("scan %v : %s (%v)", src, &d, err)
+		}
+	}
+	if err := d.Scan([]byte("-0.05")); err != nil || d.String() != "-0.05" {
+		t.Errorf("scan bytes : %s (%v)", &d, err)
+	}
+	if err := d.Scan("1e5"); err == nil {
+		t.Error("expected an error for an exponent")
+	}
+	if v, err := NewDecimal(1050, 2).Value(); err != nil || v != "10.50" {
+		t.Errorf("value : %v (%v)", v, err)
+	}
+
+	var date Date
+	if err := date.Scan(time.Date(2026, time.March, 4, 23, 30, 0, 0, time.FixedZone("", -5*3600))); err != nil || date.Format(DateFormat) != "2026-03-04" {
+		t.Errorf("scan time : %s (%v)", date.Format(DateFormat), err)
+	}
+	if err := date.Scan([]byte("2026-04-05")); err != nil {
+		t.Error(err)
+	}
+	if v, err := date.Value(); err != nil || v != "2026-04-05" {
+		t.Errorf("value : %v (%v)", v, err)
+	}
+	if v, err := (Date{}).Value(); err != nil || v != nil {
+		t.Errorf("zero value : %v (%v), want NULL", v, err)
+	}
+
+	var status Status
+	var subStatus SubscriptionStatus
+	var disputeStatus DisputeStatus
+	if err := status.Scan([]byte("settled")); err != nil || status != StatusSettled {
+		t.Errorf("scan status : %s (%v)", status, err)
+	}
+	if err := subStatus.Scan("Past Due"); err != nil || subStatus != SubscriptionStatusPastDue {
+		t.Errorf("scan subscription status : %s (%v)", subStatus, err)
+	}
+	if err := disputeStatus.Scan(nil); err != nil || disputeStatus != "" {
+		t.Errorf("scan dispute status : %s (%v)", disputeStatus, err)
+	}
+	if err := disputeStatus.Scan(42); err == nil {
+		t.Error("expected an error for a number")
+	}
+	if v, err := DisputeStatusOpen.Value(); err != nil || v != string(DisputeStatusOpen) {
+		t.Errorf("value : %v (%v)", v, err)
+	}
+	if v, err := status.Value(); err != nil || v != string(StatusSettled) {
+		t.Errorf("value : %v (%v)", v, err)
+	}
+	for _, value := range []driver.Valuer{Status(""), SubscriptionStatus(""), DisputeStatus("")} {
+		if v, err := value.Value(); err != nil || v != nil {
+			t.Errorf("value of %T : got %v (%v), want NULL", value, v, err)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+// +build unit
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/badu/braintree"
+)
+
+func TestStatusTransitions(t *testing.T) {
+	for _, tc := range []struct {
+		from, to Status
+		allowed  bool
+	}{
+		{from: StatusAuthorizing, to: StatusAuthorized, allowed: true},
+		{from: StatusAuthorized, to: StatusSubmittedForSettlement, allowed: true},
+		{from: StatusAuthorized, to: StatusVoided, allowed: true},
+		{from: StatusSubmittedForSettlement, to: StatusSettling, allowed: true},
+		{from: StatusSettling, to: StatusSettled, allowed: true},
+		{from: StatusAuthorized, to: StatusSettled},
+		{from: StatusSettled, to: StatusVoided},
+		{from: StatusVoided, to: StatusAuthorized},
+		{from: StatusUnknown, to: StatusSettled},
+	} {
+		if got := tc.from.CanTransitionTo(tc.to); got != tc.allowed {
+			t.Errorf("%s to %s : got %t, want %t", tc.from, tc.to, got, tc.allowed)
+		}
+	}
+	if !StatusSettled.Final() || len(StatusSettled.Transitions()) != 0 || StatusSubmittedForSettlement.Final() || StatusUnknown.Final() {
+		t.Error("unexpected final statuses")
+	}
+	if !StatusSettling.Allows(OpRefund) || StatusSubmittedForSettlement.Allows(OpRefund) || StatusSettled.Allows(OpVoid) {
+		t.Error("unexpected allowed operations")
+	}
+}
+
+func TestTxCan(t *testing.T) {
+	for _, tc := range []struct {
+		tx                                Tx
+		submit, void, refund, releaseHeld bool
+	}{
+		{tx: Tx{Status: StatusAuthorized, Type: TxTypeSale}, submit: true, void: true},
+		{tx: Tx{Status: StatusSubmittedForSettlement, Type: TxTypeSale}, void: true},
+		{tx: Tx{Status: StatusSettling, Type: TxTypeSale}, refund: true},
+		{tx: Tx{Status: StatusSettled, Type: TxTypeSale}, refund: true},
+		{tx: Tx{Status: StatusSettled, Type: TxTypeCredit}},
+		{tx: Tx{Status: StatusSettled, Type: TxTypeSale, EscrowStatus: EscrowHeld}, releaseHeld: true},
+		{tx: Tx{Status: StatusSettled, Type: TxTypeSale, EscrowStatus: EscrowReleased}, refund: true},
+		{tx: Tx{Status: StatusVoided, Type: TxTypeSale}},
+		{tx: Tx{Status: StatusProcessorDeclined, Type: TxTypeSale}},
+	} {
+		tx := tc.tx
+		if tx.CanSubmitForSettlement() != tc.submit || tx.CanVoid() != tc.void || tx.CanRefund() != tc.refund || tx.CanReleaseFromEscrow() != tc.releaseHeld {
+			t.Errorf("%s %s %s : got %t %t %t %t", tx.Type, tx.Status, tx.EscrowStatus, tx.CanSubmitForSettlement(), tx.CanVoid(), tx.CanRefund(), tx.CanReleaseFromEscrow())
+		}
+	}
+}
+
+func TestTxRefundableAmount(t *testing.T) {
+	ids := []string{"r1", "r2"}
+	tx := &Tx{Status: StatusSettled, Type: TxTypeSale, Amount: NewDecimal(10000, 2), RefundIds: &ids}
+	refunds := []*Tx{
+		{Id: "r1", Status: StatusSubmittedForSettlement, Amount: NewDecimal(3000, 2)},
+		{Id: "r2", Status: StatusVoided, Amount: NewDecimal(2000, 2)},
+	}
+	if got := tx.RefundableAmount(refunds...); got == nil || got.String() != "70.00" {
+		t.Fatalf("got %v, want 70.00", got)
+	}
+	if got := tx.RefundableAmount(refunds[0]); got != nil {
+		t.Fatalf("got %v, want nil for a missing refund", got)
+	}
+	unsettled := &Tx{Status: StatusAuthorized, Type: TxTypeSale, Amount: NewDecimal(10000, 2)}
+	if got := unsettled.RefundableAmount(); got.Sign() != 0 {
+		t.Fatalf("got %v, want zero", got)
+	}
+	yen := &Tx{Status: StatusAuthorized, Type: TxTypeSale, Amount: NewDecimal(100, 0)}
+	if got := yen.RefundableAmount(); got.String() != "0" {
+		t.Fatalf("got %v, want zero at the scale of the amount", got)
+	}
+	if got := (&Tx{Status: StatusSettled, Type: TxTypeSale}).RefundableAmount(); got != nil {
+		t.Fatalf("got %v, want nil without an amount", got)
+	}
+	if got := tx.RefundableAmount(refunds[0], &Tx{Id: "r2", Status: StatusSettled}); got != nil {
+		t.Fatalf("got %v, want nil for a refund without an amount", got)
+	}
+}
+
+func TestGuards(t *testing.T) {
+	c, _, done := fakeClient(t)
+	defer done()
+	ctx := context.Background()
+
+	var writes int
+	c.Interceptors = []Interceptor{func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, call *Call) (*Response, error) {
+			if call.Method != http.MethodGet && call.Path != "transactions/advanced_search" {
+				writes++
+			}
+			return next(ctx, call)
+		}
+	}}
+
+	tx, err := c.Pay(ctx, &TxRequest{Type: TxTypeSale, Amount: NewDecimal(10000, 2), PaymentMethodNonce: "fake-valid-nonce"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.SandboxSettle(ctx, tx.Id); err == nil {
+		t.Fatal("expected settling an authorized transaction to fail")
+	}
+
+	// without guards, the gateway refuses
+	if _, err := c.Refund(ctx, tx.Id); validationCodes(err) == nil {
+		t.Fatalf("got %v, want the gateway to refuse the refund", err)
+	}
+
+	c.Guards = true
+	writes = 0
+	for name, call := range map[string]func() error{
+		"refund": func() error {
+			_, err := c.Refund(ctx, tx.Id)
+			return err
+		},
+		"release": func() error {
+			_, err := c.ReleaseFromEscrow(ctx, tx.Id)
+			return err
+		},
+		"settle too much": func() error {
+			_, err := c.SubmitForSettlement(ctx, tx.Id, NewDecimal(10001, 2))
+			return err
+		},
+	} {
+		if err := call(); !errors.Is(err, ErrOperationNotAllowed) {
+			t.Fatalf("%s : got %v, want ErrOperationNotAllowed", name, err)
+		}
+	}
+	if writes != 0 {
+		t.Fatalf("expected the guarded operations not to reach the gateway, got %d calls", writes)
+	}
+
+	if _, err := c.SubmitForSettlement(ctx, tx.Id, NewDecimal(9000, 2)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.SandboxSettle(ctx, tx.Id); err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.Void(ctx, tx.Id)
+	if !errors.Is(err, ErrOperationNotAllowed) {
+		t.Fatalf("voiding a settled transaction : got %v, want ErrOperationNotAllowed", err)
+	}
+
+	if _, err := c.Refund(ctx, tx.Id, NewDecimal(4000, 2)); err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.RefundWithRequest(ctx, tx.Id, &RefundRequest{Amount: NewDecimal(5001, 2)})
+	if !errors.Is(err, ErrOperationNotAllowed) {
+		t.Fatalf("refunding more than left : got %v, want ErrOperationNotAllowed", err)
+	}
+	refundable, err := c.RefundableAmount(ctx, tx.Id)
+	if err != nil || refundable.String() != "50.00" {
+		t.Fatalf("got refundable %v (%v), want 50.00", refundable, err)
+	}
+	if _, err := c.Refund(ctx, tx.Id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Refund(ctx, tx.Id); !errors.Is(err, ErrOperationNotAllowed) {
+		t.Fatalf("refunding a fully refunded transaction : got %v, want ErrOperationNotAllowed", err)
+	}
+}
+
+func TestGuardsPartialSettlements(t *testing.T) {
+	c, _, done := fakeClient(t)
+	defer done()
+	ctx := context.Background()
+
+	auth, err := c.Pay(ctx, &TxRequest{Type: TxTypeSale, Amount: NewDecimal(10000, 2), PaymentMethodNonce: "fake-valid-nonce"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.SubmitForPartialSettlement(ctx, auth.Id, NewDecimal(6000, 2), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	c.Guards = true
+	_, err = c.SubmitForSettlement(ctx, auth.Id, NewDecimal(4001, 2))
+	if !errors.Is(err, ErrOperationNotAllowed) {
+		t.Fatalf("settling more than left on the authorization : got %v, want ErrOperationNotAllowed", err)
+	}
+	if _, err := c.SubmitForSettlement(ctx, auth.Id, NewDecimal(4000, 2)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGuardsWithoutAmount(t *testing.T) {
+	status := StatusSettled
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<transaction><id>tx1</id><type>sale</type><status>` + string(status) + `</status></transaction>`))
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "merchant", "public", "private")
+	c.Guards = true
+	if _, err := c.Refund(context.Background(), "tx1", NewDecimal(1000, 2)); err == nil {
+		t.Fatal("expected refunding a transaction without an amount to fail")
+	}
+	status = StatusAuthorized
+	if _, err := c.SubmitForSettlement(context.Background(), "tx1", NewDecimal(1000, 2)); err == nil {
+		t.Fatal("expected settling a transaction without an amount to fail")
+	}
+}
@@ -0,0 +1,105 @@
+// +build unit
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/badu/braintree"
+)
+
+func money(unscaled int64, scale int, currency string) Money {
+	return Money{Amount: NewDecimal(unscaled, scale), Currency: currency}
+}
+
+func TestCurrencyExponents(t *testing.T) {
+	t.Parallel()
+
+	for currency, want := range map[string]int{"JPY": 0, "USD": 2, "eur": 2, "KWD": 3, "CLF": 4} {
+		if exponent, ok := CurrencyExponent(currency); !ok || exponent != want {
+			t.Errorf("%s : expected %d, got %d (%v)", currency, want, exponent, ok)
+		}
+	}
+	if _, ok := CurrencyExponent("XYZ"); ok {
+		t.Error("XYZ is not a currency")
+	}
+}
+
+func TestMoneyValidate(t *testing.T) {
+	t.Parallel()
+
+	for _, m := range []Money{
+		money(1050, 2, "USD"),
+		money(1000, 1, "JPY"),
+		money(1500, 0, "JPY"),
+		money(1234, 3, "KWD"),
+		money(5, 1, "EUR"),
+	} {
+		if err := m.Validate(); err != nil {
+			t.Errorf("%s : %v", m, err)
+		}
+	}
+	for _, tt := range []struct {
+		m    Money
+		want error
+	}{
+		{money(105, 1, "JPY"), ErrDecimalPrecision},
+		{money(1001, 3, "USD"), ErrDecimalPrecision},
+		{money(10001, 4, "KWD"), ErrDecimalPrecision},
+		{money(100, 2, "XYZ"), ErrUnknownCurrency},
+	} {
+		if err := tt.m.Validate(); !errors.Is(err, tt.want) {
+			t.Errorf("%s : expected %v, got %v", tt.m, tt.want, err)
+		}
+	}
+}
+
+func TestMoneyFormat(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		m      Money
+		locale string
+		out    string
+	}{
+		{money(123450, 2, "USD"), "en-US", "$1,234.50"},
+		{money(123450, 2, "EUR"), "de-DE", "1.234,50\u00a0€"},
+		{money(123450, 2, "EUR"), "fr_FR", "1\u202f234,50\u00a0€"},
+		{money(-5, 2, "EUR"), "nl", "-€\u00a00,05"},
+		{money(1234567, 0, "JPY"), "ja-JP", "¥1,234,567"},
+		{money(12345, 1, "JPY"), "en", "¥1,235"},
+		{money(1234567, 3, "KWD"), "en-GB", "KWD\u00a01,234.567"},
+		{money(1005, 3, "BRL"), "pt-BR", "R$\u00a01,01"},
+		{money(100, 0, "USD"), "xx", "$100.00"},
+	} {
+		if got := tt.m.Format(tt.locale); got != tt.out {
+			t.Errorf("%s in %s : expected %q, got %q", tt.m, tt.locale, tt.out, got)
+		}
+	}
+}
+
+func TestPayChecksAmountPrecision(t *testing.T) {
+	t.Parallel()
+
+	c, _, stop := fakeClient(t)
+	defer stop()
+	c.Currencies = map[string]string{"": "USD", "tokyo": "JPY"}
+	ctx := context.Background()
+
+	card := &CreditCard{Number: testCardVisa, ExpirationDate: "05/30"}
+	if _, err := c.Pay(ctx, &TxRequest{Type: "sale", Amount: NewDecimal(1005, 3), CreditCard: card}); !errors.Is(err, ErrDecimalPrecision) {
+		t.Fatalf("expected a precision error, got %v", err)
+	}
+	if _, err := c.Pay(ctx, &TxRequest{Type: "sale", Amount: NewDecimal(105, 1), MerchantAccountId: "tokyo", CreditCard: card}); !errors.Is(err, ErrDecimalPrecision) {
+		t.Fatalf("expected a precision error, got %v", err)
+	}
+	tx, err := c.Pay(ctx, &TxRequest{Type: "sale", Amount: NewDecimal(1050, 2), CreditCard: card})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := tx.AmountMoney(); m.Currency != "USD" || m.Amount.Cmp(NewDecimal(1050, 2)) != 0 || m.Format("en") != "$10.50" {
+		t.Fatalf("unexpected money %s", m)
+	}
+}
@@ -0,0 +1,241 @@
+// +build unit
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	. "github.com/badu/braintree"
+)
+
+func TestSubmitForPartialSettlement(t *testing.T) {
+	c, gateway, done := fakeClient(t)
+	defer done()
+	ctx := context.Background()
+
+	auth, err := c.Pay(ctx, &TxRequest{Type: TxTypeSale, Amount: NewDecimal(10000, 2), OrderId: "order-1", PaymentMethodNonce: "fake-valid-nonce"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := c.SubmitForPartialSettlement(ctx, auth.Id, NewDecimal(6000, 2), &PartialSettlementOpts{OrderId: "shipment-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Type != TxTypeSale || first.Status != StatusSubmittedForSettlement || first.Amount.String() != "60.00" || first.OrderId != "shipment-1" {
+		t.Fatalf("unexpected partial settlement %+v", first)
+	}
+	if first.AuthorizedTransactionId == nil || *first.AuthorizedTransactionId != auth.Id {
+		t.Fatalf("got authorized transaction %v, want %s", first.AuthorizedTransactionId, auth.Id)
+	}
+	second, err := c.SubmitForPartialSettlement(ctx, auth.Id, NewDecimal(3000, 2), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.OrderId != "order-1" {
+		t.Fatalf("got order id %q, want the one of the authorization", second.OrderId)
+	}
+
+	remaining, err := c.RemainingAuthorization(ctx, auth.Id)
+	if err != nil || remaining.String() != "10.00" {
+		t.Fatalf("got remaining %v (%v), want 10.00", remaining, err)
+	}
+	_, err = c.SubmitForPartialSettlement(ctx, auth.Id, NewDecimal(1001, 2), nil)
+	if !errors.Is(err, ErrSettlementAmountTooLarge) {
+		t.Fatalf("got %v, want ErrSettlementAmountTooLarge", err)
+	}
+	parent, _ := gateway.Transaction(auth.Id)
+	if parent.Status != StatusAuthorized || parent.PartialSettlementTransactionIds == nil || len(*parent.PartialSettlementTransactionIds) != 2 {
+		t.Fatalf("expected the refused capture not to reach the gateway, got %+v", parent.PartialSettlementTransactionIds)
+	}
+
+	found, err := c.FindTransaction(ctx, auth.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := *found.PartialSettlementTransactionIds
+	if len(ids) != 2 || ids[0] != first.Id || ids[1] != second.Id {
+		t.Fatalf("got partial settlements %v, want [%s %s]", ids, first.Id, second.Id)
+	}
+
+	if _, err := c.SubmitForPartialSettlement(ctx, auth.Id, NewDecimal(0, 2), nil); err == nil {
+		t.Fatal("expected a zero amount to be refused")
+	}
+}
+
+func TestSubmitForPartialSettlementLoadsSettledAmount(t *testing.T) {
+	c, _, done := fakeClient(t)
+	defer done()
+	ctx := context.Background()
+
+	auth, err := c.Pay(ctx, &TxRequest{Type: TxTypeSale, Amount: NewDecimal(5000, 2), PaymentMethodNonce: "fake-valid-nonce"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	voided, err := c.SubmitForPartialSettlement(ctx, auth.Id, NewDecimal(2000, 2), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Void(ctx, voided.Id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.SubmitForPartialSettlement(ctx, auth.Id, NewDecimal(1500, 2), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// another client knows nothing of the captures above, and loads them from the gateway
+	other := &APIClient{Key: c.Key, Client: c.Client}
+	remaining, err := other.RemainingAuthorization(ctx, auth.Id)
+	if err != nil || remaining.String() != "35.00" {
+		t.Fatalf("got remaining %v (%v), want 35.00", remaining, err)
+	}
+	if _, err := other.SubmitForPartialSettlement(ctx, auth.Id, NewDecimal(3501, 2), nil); !errors.Is(err, ErrSettlementAmountTooLarge) {
+		t.Fatalf("got %v, want ErrSettlementAmountTooLarge", err)
+	}
+	if _, err := other.SubmitForPartialSettlement(ctx, auth.Id, NewDecimal(3500, 2), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// the first client sees the captures of the other one
+	_, err = c.SubmitForPartialSettlement(ctx, auth.Id, NewDecimal(1, 2), nil)
+	if !errors.Is(err, ErrSettlementAmountTooLarge) {
+		t.Fatalf("got %v, want ErrSettlementAmountTooLarge", err)
+	}
+	if _, err := c.Void(ctx, voided.Id); err == nil {
+		t.Fatal("expected voiding a voided capture to fail")
+	}
+	if _, err := c.Void(ctx, auth.Id); err != nil {
+		t.Fatal(err)
+	}
+	if remaining, err := c.RemainingAuthorization(ctx, auth.Id); err != nil || remaining.Sign() != 0 {
+		t.Fatalf("got remaining %v (%v), want nothing left on a voided authorization", remaining, err)
+	}
+}
+
+func TestSubmitForPartialSettlementInFlight(t *testing.T) {
+	c, _, done := fakeClient(t)
+	defer done()
+	ctx := context.Background()
+
+	auth, err := c.Pay(ctx, &TxRequest{Type: TxTypeSale, Amount: NewDecimal(5000, 2), PaymentMethodNonce: "fake-valid-nonce"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the first capture is held until the second one was refused
+	started, refused := make(chan struct{}), make(chan struct{})
+	c.Interceptors = []Interceptor{func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, call *Call) (*Response, error) {
+			if strings.HasSuffix(call.Path, "/submit_for_partial_settlement") {
+				close(started)
+				<-refused
+			}
+			return next(ctx, call)
+		}
+	}}
+	result := make(chan error, 1)
+	go func() {
+		_, err := c.SubmitForPartialSettlement(ctx, auth.Id, NewDecimal(3000, 2), nil)
+		result <- err
+	}()
+	<-started
+	_, err = c.SubmitForPartialSettlement(ctx, auth.Id, NewDecimal(3000, 2), nil)
+	close(refused)
+	if !errors.Is(err, ErrSettlementAmountTooLarge) {
+		t.Fatalf("got %v, want the capture in flight to be accounted for", err)
+	}
+	if err := <-result; err != nil {
+		t.Fatal(err)
+	}
+
+	c.Interceptors = nil
+	if _, err := c.SubmitForPartialSettlement(ctx, auth.Id, NewDecimal(2000, 2), nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSubmitForPartialSettlementUnauthorized(t *testing.T) {
+	c, _, done := fakeClient(t)
+	defer done()
+	ctx := context.Background()
+
+	tx, err := c.Pay(ctx, &TxRequest{
+		Type:               TxTypeSale,
+		Amount:             NewDecimal(5000, 2),
+		PaymentMethodNonce: "fake-valid-nonce",
+		Options:            &TxOpts{SubmitForSettlement: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.SubmitForPartialSettlement(ctx, tx.Id, NewDecimal(1000, 2), nil)
+	if !errors.Is(err, ErrOperationNotAllowed) || errors.Is(err, ErrSettlementAmountTooLarge) {
+		t.Fatalf("got %v, want ErrOperationNotAllowed for a transaction submitted for settlement", err)
+	}
+	if remaining, err := c.RemainingAuthorization(ctx, tx.Id); err != nil || remaining.Sign() != 0 {
+		t.Fatalf("got remaining %v (%v), want nothing left on a transaction submitted for settlement", remaining, err)
+	}
+}
+
+func TestSubmitForPartialSettlementGatewayLag(t *testing.T) {
+	c, _, done := fakeClient(t)
+	defer done()
+	ctx := context.Background()
+
+	auth, err := c.Pay(ctx, &TxRequest{Type: TxTypeSale, Amount: NewDecimal(10000, 2), PaymentMethodNonce: "fake-valid-nonce"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the gateway does not list the partial settlements on the authorization, nor returns them in searches
+	listed := regexp.MustCompile(`(?s)<partial-settlement-transaction-ids[^>]*>.*?</partial-settlement-transaction-ids>`)
+	lagging := true
+	c.Interceptors = []Interceptor{func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, call *Call) (*Response, error) {
+			response, err := next(ctx, call)
+			if err != nil || !lagging {
+				return response, err
+			}
+			switch call.Path {
+			case "transactions/" + auth.Id:
+				response.Body = listed.ReplaceAll(response.Body, nil)
+			case "transactions/advanced_search":
+				response.Body = []byte(`<credit-card-transactions type="collection"></credit-card-transactions>`)
+			}
+			return response, nil
+		}
+	}}
+	first, err := c.SubmitForPartialSettlement(ctx, auth.Id, NewDecimal(6000, 2), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if remaining, err := c.RemainingAuthorization(ctx, auth.Id); err != nil || remaining.String() != "40.00" {
+		t.Fatalf("got remaining %v (%v), want the capture the gateway does not list to be deducted", remaining, err)
+	}
+	if _, err := c.SubmitForPartialSettlement(ctx, auth.Id, NewDecimal(4001, 2), nil); !errors.Is(err, ErrSettlementAmountTooLarge) {
+		t.Fatalf("got %v, want ErrSettlementAmountTooLarge", err)
+	}
+
+	// the authorization lists the capture, which the search does not return yet
+	lagging = false
+	c.Interceptors = []Interceptor{func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, call *Call) (*Response, error) {
+			response, err := next(ctx, call)
+			if err == nil && call.Path == "transactions/advanced_search" {
+				response.Body = []byte(`<credit-card-transactions type="collection"></credit-card-transactions>`)
+			}
+			return response, err
+		}
+	}}
+	other := &APIClient{Key: c.Key, Client: c.Client, Interceptors: c.Interceptors}
+	if remaining, err := other.RemainingAuthorization(ctx, auth.Id); err != nil || remaining.String() != "40.00" {
+		t.Fatalf("got remaining %v (%v), want %s to be fetched", remaining, err, first.Id)
+	}
+	if _, err := c.SubmitForPartialSettlement(ctx, auth.Id, NewDecimal(4000, 2), nil); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,136 @@
+// +build unit
+
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/badu/braintree"
+)
+
+func TestRedactXMLCreditCard(t *testing.T) {
+	t.Parallel()
+
+	payload, err := xml.Marshal(&TxRequest{
+		Type:   "sale",
+		Amount: NewDecimal(1000, 2),
+		CreditCard: &CreditCard{
+			Number:         testCardVisa,
+			ExpirationDate: "05/14",
+			CVV:            "100",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	redacted := string(RedactXML(payload))
+	if strings.Contains(redacted, testCardVisa) {
+		t.Fatalf("card number leaked : %s", redacted)
+	}
+	if !strings.Contains(redacted, "<number>411111******1111</number>") {
+		t.Fatalf("card number not masked to first 6 / last 4 : %s", redacted)
+	}
+	if strings.Contains(redacted, "cvv") || strings.Contains(redacted, ">100<") {
+		t.Fatalf("cvv not dropped : %s", redacted)
+	}
+	if !strings.Contains(redacted, "<expiration-date>*****</expiration-date>") {
+		t.Fatalf("expiration date not masked : %s", redacted)
+	}
+	if !strings.Contains(redacted, "<amount>10.00</amount>") {
+		t.Fatalf("non sensitive data altered : %s", redacted)
+	}
+}
+
+func TestRedactXMLMerchantAccount(t *testing.T) {
+	t.Parallel()
+
+	payload, err := xml.Marshal(&MerchantAccount{
+		Individual: &MerchantAccountPerson{FirstName: "First", SSN: "123-45-6789"},
+		Business:   &MerchantAccountBusiness{TaxId: "98-7654321"},
+		FundingOptions: &MerchantAccountFundingOptions{
+			AccountNumber: "1123581321",
+			RoutingNumber: "071101307",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	redacted := string(RedactXML(payload))
+	for _, secret := range []string{"123-45-6789", "98-7654321", "1123581321", "071101307"} {
+		if strings.Contains(redacted, secret) {
+			t.Fatalf("%s leaked : %s", secret, redacted)
+		}
+	}
+	for _, masked := range []string{"<ssn>*******6789</ssn>", "<tax-id>******4321</tax-id>", "<account-number>******1321</account-number>", "<routing-number>*****1307</routing-number>"} {
+		if !strings.Contains(redacted, masked) {
+			t.Fatalf("expected %s in %s", masked, redacted)
+		}
+	}
+}
+
+func TestRedactXMLUnparseable(t *testing.T) {
+	t.Parallel()
+
+	if got := string(RedactXML([]byte("<number>4111111111111111"))); got != "[redacted 24 bytes]" {
+		t.Fatalf("got %q", got)
+	}
+	if got := RedactXML(nil); len(got) != 0 {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestClientLogsRedactedPayloads(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`<api-error-response><message>Do Not Honor</message><errors/></api-error-response>`))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	c := New(srv.URL, "merchant", "public", "private")
+	c.Logger = NewStdLogger(log.New(&buf, "", 0), LevelDebug)
+
+	_, err := c.Pay(context.Background(), &TxRequest{
+		Type:       "sale",
+		Amount:     NewDecimal(1000, 2),
+		CreditCard: &CreditCard{Number: testCardVisa, CVV: "100"},
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	out := buf.String()
+	if strings.Contains(out, testCardVisa) || strings.Contains(out, "cvv") {
+		t.Fatalf("sensitive data logged : %s", out)
+	}
+	if !strings.Contains(out, "DEBUG braintree request") || !strings.Contains(out, "DEBUG braintree error") {
+		t.Fatalf("unexpected log output : %s", out)
+	}
+}
+
+func TestClientWithoutLoggerDoesNotPanic(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "merchant", "public", "private")
+	if _, err := c.FindTransaction(context.Background(), "missing"); err == nil {
+		t.Fatal("expected an error")
+	}
+	if _, err := c.Pay(context.Background(), &TxRequest{Type: TxTypeSale, CustomFields: CustomFields{"<bad>": "x"}}); err == nil {
+		t.Fatal("expected a marshal error")
+	}
+}
@@ -0,0 +1,82 @@
+// +build unit
+
+package tests
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/badu/braintree"
+)
+
+func TestResponseCodesCatalog(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		code     ResponseCode
+		text     string
+		category ResponseCategory
+		action   ResponseAction
+		typ      ResponseType
+	}{
+		{1000, "Approved", ResponseCategoryApproved, ResponseActionNone, ResponseTypeApproved},
+		{2001, "Insufficient Funds", ResponseCategoryInsufficientFunds, ResponseActionRetryLater, ResponseTypeSoftDeclined},
+		{2004, "Expired Card", ResponseCategoryExpiredCard, ResponseActionNewCard, ResponseTypeHardDeclined},
+		{2014, "Processor Declined - Fraud Suspected", ResponseCategoryFraud, ResponseActionNeverRetry, ResponseTypeHardDeclined},
+		{2046, "Declined", ResponseCategoryDoNotHonor, ResponseActionNewCard, ResponseTypeSoftDeclined},
+		{3000, "Processor Network Unavailable - Try Again", ResponseCategoryIssuerUnavailable, ResponseActionRetryLater, ResponseTypeSoftDeclined},
+		{2999, "", ResponseCategoryDoNotHonor, ResponseActionNewCard, ResponseTypeSoftDeclined},
+		{0, "", ResponseCategoryUnknown, "", ""},
+	} {
+		if g := tc.code.Text(); g != tc.text {
+			t.Errorf("%d : got text %q, want %q", tc.code, g, tc.text)
+		}
+		if g := tc.code.Category(); g != tc.category {
+			t.Errorf("%d : got category %q, want %q", tc.code, g, tc.category)
+		}
+		if g := tc.code.Action(); g != tc.action {
+			t.Errorf("%d : got action %q, want %q", tc.code, g, tc.action)
+		}
+		if g := tc.code.Type(); g != tc.typ {
+			t.Errorf("%d : got type %q, want %q", tc.code, g, tc.typ)
+		}
+		if g := tc.code.Known(); g != (tc.text != "") {
+			t.Errorf("%d : got known %v", tc.code, g)
+		}
+	}
+
+	if ResponseCode(1000).CustomerMessage() != "" {
+		t.Fatal("approvals should not carry a customer message")
+	}
+	if fraud, declined := ResponseCode(2014).CustomerMessage(), ResponseCode(2038).CustomerMessage(); fraud == "" || fraud == declined {
+		t.Fatalf("unexpected customer messages %q and %q", fraud, declined)
+	}
+	if !ResponseCode(2001).Retryable() || ResponseCode(2004).Retryable() {
+		t.Fatal("only insufficient funds should be retryable")
+	}
+
+	var tx *Tx
+	if tx.ResponseCategory() != ResponseCategoryUnknown || tx.ResponseAction() != "" || tx.CustomerMessage() != "" {
+		t.Fatal("a nil transaction should have no response")
+	}
+}
+
+func TestResponseCodesOnDeclinedTransaction(t *testing.T) {
+	t.Parallel()
+
+	c, _, stop := fakeClient(t)
+	defer stop()
+
+	_, err := c.Pay(context.Background(), &TxRequest{Type: "sale", Amount: NewDecimal(200400, 2), PaymentMethodNonce: "fake-valid-nonce"})
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.Transaction == nil {
+		t.Fatalf("expected a decline, got %v", err)
+	}
+	tx := apiErr.Transaction
+	if tx.ResponseCategory() != ResponseCategoryExpiredCard || tx.ResponseAction() != ResponseActionNewCard {
+		t.Fatalf("unexpected response %d %q", tx.ProcessorResponseCode, tx.ProcessorResponseText)
+	}
+	if tx.ProcessorResponseType != ResponseTypeHardDeclined || tx.CustomerMessage() != ResponseCode(2004).CustomerMessage() {
+		t.Fatalf("unexpected response type %q or message %q", tx.ProcessorResponseType, tx.CustomerMessage())
+	}
+}
@@ -0,0 +1,191 @@
+// +build unit
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	. "github.com/badu/braintree"
+)
+
+func newFlakyServer(t *testing.T, failures int32, status int) (*httptest.Server, *int32) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		if n <= failures {
+			w.Header().Set(HdrRetryAfter, "0")
+			w.WriteHeader(status)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`<transaction><id>tx1</id><status>voided</status></transaction>`))
+	}))
+	return srv, &calls
+}
+
+func retryingClient(url string) *APIClient {
+	c := New(url, "merchant", "public", "private")
+	c.Retry = &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+	return c
+}
+
+func TestRetrySafeVerb(t *testing.T) {
+	t.Parallel()
+
+	srv, calls := newFlakyServer(t, 2, http.StatusServiceUnavailable)
+	defer srv.Close()
+
+	tx, err := retryingClient(srv.URL).FindTransaction(context.Background(), "tx1")
+	if err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if tx.Id != "tx1" {
+		t.Fatalf("got id %q, want tx1", tx.Id)
+	}
+	if got := atomic.LoadInt32(calls); got != 3 {
+		t.Fatalf("got %d calls, want 3", got)
+	}
+}
+
+func TestRetryExhausted(t *testing.T) {
+	t.Parallel()
+
+	srv, calls := newFlakyServer(t, 10, http.StatusBadGateway)
+	defer srv.Close()
+
+	_, err := retryingClient(srv.URL).FindTransaction(context.Background(), "tx1")
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("expected a *RetryError, got %#v", err)
+	}
+	if retryErr.Attempts != 3 {
+		t.Fatalf("got %d attempts, want 3", retryErr.Attempts)
+	}
+	if retryErr.StatusCode() != http.StatusBadGateway {
+		t.Fatalf("got status %d, want %d", retryErr.StatusCode(), http.StatusBadGateway)
+	}
+	if got := atomic.LoadInt32(calls); got != 3 {
+		t.Fatalf("got %d calls, want 3", got)
+	}
+}
+
+func TestRetryPostRequiresUnsafeRetries(t *testing.T) {
+	t.Parallel()
+
+	srv, calls := newFlakyServer(t, 1, http.StatusServiceUnavailable)
+	defer srv.Close()
+	c := retryingClient(srv.URL)
+
+	_, err := c.Pay(context.Background(), &TxRequest{Type: "sale", Amount: NewDecimal(1000, 2), OrderId: "order-1"})
+	if err == nil {
+		t.Fatal("expected POST without unsafe retries to fail without retrying, even with an order id")
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Fatalf("got %d calls, want 1", got)
+	}
+
+	atomic.StoreInt32(calls, 0)
+	if _, err := c.Void(context.Background(), "tx1"); err == nil {
+		t.Fatal("expected PUT without unsafe retries to fail without retrying")
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Fatalf("got %d calls, want 1", got)
+	}
+
+	atomic.StoreInt32(calls, 0)
+	ctx := WithUnsafeRetries(context.Background())
+	if _, err := c.Refund(ctx, "tx1"); err != nil {
+		t.Fatalf("expected POST with unsafe retries to be retried, got %v", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Fatalf("got %d calls, want 2", got)
+	}
+}
+
+func TestRetryBackoffWithoutMaxDelay(t *testing.T) {
+	t.Parallel()
+
+	srv, calls := newFlakyServer(t, 3, http.StatusServiceUnavailable)
+	defer srv.Close()
+	c := New(srv.URL, "merchant", "public", "private")
+	c.Retry = &RetryPolicy{MaxAttempts: 4, BaseDelay: 30 * time.Millisecond}
+
+	start := time.Now()
+	if _, err := c.FindTransaction(context.Background(), "tx1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt32(calls); got != 4 {
+		t.Fatalf("got %d calls, want 4", got)
+	}
+	// 30ms, 60ms and 120ms, instead of 3 times 30ms
+	if elapsed := time.Since(start); elapsed < 210*time.Millisecond {
+		t.Fatalf("expected the delays to double without a max delay, took %v", elapsed)
+	}
+}
+
+func TestRetryTransientErrorsOnly(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<transaction><id>tx1</id></transaction>`))
+	}))
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		err   error
+		calls int32
+	}{
+		{err: &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}, calls: 2},
+		{err: io.ErrUnexpectedEOF, calls: 2},
+		{err: errors.New("x509: certificate signed by unknown authority"), calls: 1},
+	} {
+		var calls int32
+		c := retryingClient(srv.URL)
+		c.Interceptors = []Interceptor{func(next RoundTrip) RoundTrip {
+			return func(ctx context.Context, call *Call) (*Response, error) {
+				if atomic.AddInt32(&calls, 1) == 1 {
+					return nil, tc.err
+				}
+				return next(ctx, call)
+			}
+		}}
+		_, _ = c.FindTransaction(context.Background(), "tx1")
+		if got := atomic.LoadInt32(&calls); got != tc.calls {
+			t.Errorf("%v : got %d calls, want %d", tc.err, got, tc.calls)
+		}
+	}
+}
+
+func TestRetryRespectsDeadline(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(HdrRetryAfter, "30")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "merchant", "public", "private")
+	c.Retry = &RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond, MaxDelay: time.Minute}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := c.FindTransaction(ctx, "tx1")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("retry ignored the context deadline, took %v", elapsed)
+	}
+}
@@ -0,0 +1,135 @@
+// +build unit
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/badu/braintree"
+)
+
+func TestTxScannerResumes(t *testing.T) {
+	t.Parallel()
+
+	c, gateway, stop := fakeClient(t)
+	defer stop()
+	ctx := context.Background()
+	gateway.PageSize, gateway.SearchLimit = 2, 5
+
+	from := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	now := from
+	gateway.Now = func() time.Time { return now }
+	all := map[string]bool{}
+	for day := 0; day < 7; day++ {
+		for _, hour := range []int{1, 9, 17} {
+			now = from.AddDate(0, 0, day).Add(time.Duration(hour) * time.Hour)
+			tx, err := c.Pay(ctx, &TxRequest{
+				Type:       "sale",
+				Amount:     NewDecimal(1000, 2),
+				CreditCard: &CreditCard{Number: testCardVisa, ExpirationDate: "05/30"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if day < 6 {
+				all[tx.Id] = true
+			}
+		}
+	}
+
+	dir, err := ioutil.TempDir("", "scan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store := &FileCheckpointStore{Dir: dir}
+	scanner := func() *TxScanner {
+		return &TxScanner{Client: c, Store: store, Key: "march", From: from, To: from.AddDate(0, 0, 6), Window: 72 * time.Hour, Limit: 5}
+	}
+
+	crash := errors.New("crash")
+	seen := map[string]int{}
+	count := 0
+	err = scanner().Scan(ctx, func(tx *Tx) error {
+		if count == 6 {
+			return crash
+		}
+		count++
+		seen[tx.Id]++
+		return nil
+	})
+	if err != crash {
+		t.Fatalf("expected the error of the callback, got %v", err)
+	}
+	checkpoint, err := store.Load(ctx, "march")
+	if err != nil || checkpoint == nil || checkpoint.WindowEnd.IsZero() {
+		t.Fatalf("expected a checkpoint, got %+v (%v)", checkpoint, err)
+	}
+
+	other := scanner()
+	other.Query = orderSearch("other")
+	if err := other.Scan(ctx, func(*Tx) error { return nil }); !errors.Is(err, ErrCheckpointMismatch) {
+		t.Fatalf("expected a checkpoint mismatch, got %v", err)
+	}
+
+	resumed := 0
+	if err := scanner().Scan(ctx, func(tx *Tx) error {
+		resumed++
+		seen[tx.Id]++
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != len(all) {
+		t.Fatalf("expected %d transactions, got %d", len(all), len(seen))
+	}
+	for id := range seen {
+		if !all[id] {
+			t.Fatalf("transaction %s is out of the range", id)
+		}
+	}
+	if resumed > len(all)-count+1 {
+		t.Fatalf("resumed with %d transactions, the scan started over", resumed)
+	}
+	if checkpoint, err := store.Load(ctx, "march"); err != nil || checkpoint != nil {
+		t.Fatalf("expected the checkpoint to be deleted, got %+v (%v)", checkpoint, err)
+	}
+}
+
+func TestTxScannerSecondWithTooManyResults(t *testing.T) {
+	t.Parallel()
+
+	c, gateway, stop := fakeClient(t)
+	defer stop()
+	ctx := context.Background()
+	at := time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC)
+	gateway.Now = func() time.Time { return at }
+	gateway.SearchLimit = 2
+	for i := 0; i < 2; i++ {
+		if _, err := c.Pay(ctx, &TxRequest{Type: "sale", Amount: NewDecimal(1000, 2), CreditCard: &CreditCard{Number: testCardVisa, ExpirationDate: "05/30"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dir, err := ioutil.TempDir("", "scan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	s := &TxScanner{Client: c, Store: &FileCheckpointStore{Dir: dir}, Key: "crowded", From: at.Add(-time.Hour), To: at.Add(time.Hour), Limit: 2}
+	if err := s.Scan(ctx, func(*Tx) error { return nil }); err == nil {
+		t.Fatal("expected an error for a second with too many transactions")
+	}
+}
+
+func orderSearch(orderID string) *Search {
+	s := NewTransactionSearch()
+	s.OrderID().Is = orderID
+	query, _ := s.Search()
+	return query
+}
@@ -0,0 +1,169 @@
+// +build unit
+
+package tests
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/badu/braintree"
+)
+
+func TestSearchBuildersXML(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2020, time.January, 2, 0, 0, 0, 0, time.UTC)
+	customers := NewCustomerSearch().CreatedAt(from, time.Time{}).CreditCardLast4("1111")
+	customers.Address(AddressPostalCode).Is = "60622"
+	customers.Email().EndsWith = "@example.com"
+
+	subscriptions := NewSubscriptionSearch().
+		Status(SubscriptionStatusActive, SubscriptionStatusPastDue).
+		PlanID("monthly", "yearly").
+		DaysPastDue(IntPtr(3), IntPtr(10)).
+		InTrialPeriod(false).
+		NextBillingDate(time.Time{}, from)
+
+	verifications := NewCreditCardVerificationSearch().
+		Status(VerificationStatusProcessorDeclined).
+		CreditCardType("Visa").
+		CreditCardBIN("411111")
+
+	for _, c := range []struct {
+		search interface {
+			Search() (*Search, error)
+		}
+		want []string
+	}{
+		{customers, []string{
+			`<created-at><min type="datetime">2020-01-02T00:00:00Z</min></created-at>`,
+			`<credit-card-number><ends-with>1111</ends-with></credit-card-number>`,
+			`<address-postal-code><is>60622</is></address-postal-code>`,
+			`<email><ends-with>@example.com</ends-with></email>`,
+		}},
+		{subscriptions, []string{
+			`<status type="array"><item>Active</item><item>Past Due</item></status>`,
+			`<plan-id type="array"><item>monthly</item><item>yearly</item></plan-id>`,
+			`<days-past-due><min>3</min><max>10</max></days-past-due>`,
+			`<in-trial-period type="array"><item>false</item></in-trial-period>`,
+			`<next-billing-date><max type="datetime">2020-01-02T00:00:00Z</max></next-billing-date>`,
+		}},
+		{verifications, []string{
+			`<status type="array"><item>processor_declined</item></status>`,
+			`<credit-card-card-type type="array"><item>Visa</item></credit-card-card-type>`,
+			`<credit-card-number><starts-with>411111</starts-with></credit-card-number>`,
+		}},
+	} {
+		query, err := c.search.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := xml.Marshal(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range c.want {
+			if !strings.Contains(string(b), want) {
+				t.Errorf("missing %s in %s", want, b)
+			}
+		}
+	}
+}
+
+func TestSearchBuildersRejectInvalidCriteria(t *testing.T) {
+	t.Parallel()
+
+	customers := NewCustomerSearch().CreditCardBIN("41")
+	customers.Address(AddressCompany).Is = "ACME"
+	subscriptions := NewSubscriptionSearch().
+		Status("Paused").
+		DaysPastDue(IntPtr(10), IntPtr(3)).
+		BillingCyclesRemaining(nil, nil).
+		PlanID()
+	verifications := NewCreditCardVerificationSearch().Status("settled").CreditCardType("Visa Electron")
+
+	for _, c := range []struct {
+		search interface {
+			Search() (*Search, error)
+		}
+		fields []string
+	}{
+		{customers, []string{"bin", "address-company"}},
+		{subscriptions, []string{"status", "days-past-due", "billing-cycles-remaining", "plan-id"}},
+		{verifications, []string{"status", "credit-card-card-type"}},
+	} {
+		_, err := c.search.Search()
+		var invalid *InvalidSearchError
+		if !errors.As(err, &invalid) {
+			t.Fatalf("expected an invalid search error, got %v", err)
+		}
+		if len(invalid.Problems) != len(c.fields) {
+			t.Errorf("expected %d problems, got %v", len(c.fields), invalid.Problems)
+		}
+		for i, field := range c.fields {
+			if i < len(invalid.Problems) && !strings.Contains(invalid.Problems[i], field) {
+				t.Errorf("no problem reported about %s in %v", field, invalid.Problems)
+			}
+		}
+	}
+}
+
+func TestSearchBuildersOnFakeGateway(t *testing.T) {
+	t.Parallel()
+
+	c, _, stop := fakeClient(t)
+	defer stop()
+	ctx := context.Background()
+
+	company := RandomString()
+	var last *Subscription
+	for i := 0; i < 3; i++ {
+		customer, err := c.CreateCustomer(ctx, &CustomerRequest{
+			Company:    company,
+			CreditCard: &CreditCard{Number: testCardVisa, ExpirationDate: "05/2031"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		last, err = c.CreateSubscription(ctx, &SubscriptionRequest{PlanId: "monthly", PaymentMethodToken: customer.DefaultCreditCard().Token})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := c.CancelSubscription(ctx, last.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	customers := NewCustomerSearch().CreditCardLast4(testCardVisa[len(testCardVisa)-4:])
+	customers.Company().Is = company
+	query, err := customers.Search()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := c.SearchCustomersByIDs(ctx, query)
+	if err != nil || len(result.IDs) != 3 {
+		t.Fatalf("customers : %v %+v", err, result)
+	}
+
+	query, err = NewSubscriptionSearch().PlanID("monthly").Status(SubscriptionStatusActive).Search()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err = c.SearchSubscriptions(ctx, query)
+	if err != nil || len(result.IDs) != 2 {
+		t.Fatalf("active subscriptions : %v %+v", err, result)
+	}
+
+	query, err = NewSubscriptionSearch().Status(SubscriptionStatusCanceled).Price(NewDecimal(1000, 2), nil).Search()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err = c.SearchSubscriptions(ctx, query)
+	if err != nil || len(result.IDs) != 1 || result.IDs[0] != last.Id {
+		t.Fatalf("canceled subscriptions : %v %+v", err, result)
+	}
+}
@@ -0,0 +1,185 @@
+// +build unit
+
+package tests
+
+import (
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/badu/braintree"
+)
+
+func TestParseSearch(t *testing.T) {
+	t.Parallel()
+
+	query := `status in (settled, settling) and amount between 10.00 and 50 and created_at >= 2026-01-01 and customer_email ends_with "@acme.com"`
+	search, err := ParseSearch(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := xml.Marshal(search)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<search>` +
+		`<status type="array"><item>settled</item><item>settling</item></status>` +
+		`<amount><min>10.00</min><max>50</max></amount>` +
+		`<created-at><min type="datetime">2026-01-01T00:00:00Z</min></created-at>` +
+		`<customer-email><ends-with>@acme.com</ends-with></customer-email>` +
+		`</search>`
+	if string(b) != want {
+		t.Fatalf("expected %s, got %s", want, b)
+	}
+	if got := search.String(); got != `status in (settled, settling) and amount between 10.00 and 50 and created_at >= 2026-01-01 and customer_email ends_with "@acme.com"` {
+		t.Fatalf("unexpected string %s", got)
+	}
+}
+
+func TestParseSearchMergesCriteria(t *testing.T) {
+	t.Parallel()
+
+	search, err := ParseSearch(`amount >= 10 and order_id starts_with A and amount <= 50.5 and order-id ENDS_WITH "z" and refund = true and id = "42" and days_past_due = 0`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `amount between 10 and 50.5 and order_id starts_with "A" and order_id ends_with "z" and refund = true and id = "42" and days_past_due = 0`
+	if got := search.String(); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
+
+func TestSearchStringRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	s := NewSubscriptionSearch().
+		Status(SubscriptionStatusPastDue, SubscriptionStatusActive).
+		DaysPastDue(IntPtr(3), nil).
+		NextBillingDate(time.Date(2026, time.March, 1, 12, 30, 0, 0, time.UTC), time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC))
+	s.ID().Contains = `quote " and, (parens)`
+	search, err := s.Search()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `status in ("Past Due", Active) and days_past_due >= 3 and next_billing_date between 2026-03-01T12:30:00Z and 2026-04-01T00:00:00Z and id contains "quote \" and, (parens)"`
+	if got := search.String(); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+	parsed, err := ParseSearch(search.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b1, _ := xml.Marshal(search)
+	b2, _ := xml.Marshal(parsed)
+	if string(b1) != string(b2) {
+		t.Fatalf("round trip changed the search\n%s\n%s", b1, b2)
+	}
+	if empty, err := ParseSearch("  "); err != nil || empty.String() != "" {
+		t.Fatalf("empty query : %v %q", err, empty)
+	}
+}
+
+func TestParseSearchDates(t *testing.T) {
+	t.Parallel()
+
+	for _, c := range []struct {
+		query, xml string
+	}{
+		{
+			query: `created_at <= 2026-01-31`,
+			xml:   `<search><created-at><max type="datetime">2026-01-31T23:59:59Z</max></created-at></search>`,
+		},
+		{
+			query: `created_at between 2026-01-01 and 2026-01-31`,
+			xml:   `<search><created-at><min type="datetime">2026-01-01T00:00:00Z</min><max type="datetime">2026-01-31T23:59:59Z</max></created-at></search>`,
+		},
+		{
+			query: `created_at = 2026-01-31`,
+			xml:   `<search><created-at><min type="datetime">2026-01-31T00:00:00Z</min><max type="datetime">2026-01-31T23:59:59Z</max></created-at></search>`,
+		},
+		{
+			query: `created_at <= 2026-01-31T00:00:00Z`,
+			xml:   `<search><created-at><max type="datetime">2026-01-31T00:00:00Z</max></created-at></search>`,
+		},
+		{
+			query: `created_at = 2026-01-31T10:00:00Z`,
+			xml:   `<search><created-at><is type="datetime">2026-01-31T10:00:00Z</is></created-at></search>`,
+		},
+	} {
+		search, err := ParseSearch(c.query)
+		if err != nil {
+			t.Fatalf("%s : %v", c.query, err)
+		}
+		b, err := xml.Marshal(search)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != c.xml {
+			t.Errorf("%s : expected %s, got %s", c.query, c.xml, b)
+		}
+		if got := search.String(); got != c.query {
+			t.Errorf("%s : got %s back", c.query, got)
+		}
+	}
+
+	if _, err := ParseSearch(`created_at >= 2026-01-01 and created_at = 2026-01-31`); err == nil {
+		t.Fatal("expected a day to conflict with the min already searched")
+	}
+}
+
+func TestParseSearchErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, c := range []struct {
+		query string
+		pos   int
+		msg   string
+	}{
+		{`status in settled`, 10, "expected ("},
+		{`status in (settled settling)`, 19, "expected , or )"},
+		{`amount between 10 50`, 18, "expected and"},
+		{`amount between 50 and 10`, 15, "greater than its max"},
+		{`amount between 10 and 2026-01-01`, 22, "cannot mix"},
+		{`amount > 10`, 7, "inclusive"},
+		{`amount >= ten`, 10, "expected a number or a date"},
+		{`id = 1 and id = "1"`, 11, "already searched as a number"},
+		{`id = "1" or id = "2"`, 9, "expected and"},
+		{`id like "1"`, 3, "unknown operator"},
+		{`customer_email ends_with "@acme.com`, 25, "unterminated"},
+		{`id = "1" and`, 12, "expected a field name"},
+		{`amount >= 1e3`, 10, "expected a number or a date"},
+		{`id starts_with "a" and id starts_with "b"`, 23, "already searched"},
+	} {
+		_, err := ParseSearch(c.query)
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("%s : expected a parse error, got %v", c.query, err)
+			continue
+		}
+		if parseErr.Pos != c.pos || !strings.Contains(parseErr.Msg, c.msg) || parseErr.Query != c.query {
+			t.Errorf("%s : expected %q at %d, got %v", c.query, c.msg, c.pos, parseErr)
+		}
+	}
+}
+
+func TestRangeFieldBounds(t *testing.T) {
+	t.Parallel()
+
+	search := &Search{}
+	f := search.AddRangeField("amount")
+	f.Is = 15.01
+	f.Min, f.MinText = 10, "0"
+	f.Max = 20.5
+	b, err := xml.Marshal(search)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `<search><amount><is>15.01</is><min>0</min><max>20.5</max></amount></search>`; string(b) != want {
+		t.Fatalf("expected %s, got %s", want, b)
+	}
+	if got := search.String(); got != `amount = 15.01 and amount between 0 and 20.5` {
+		t.Fatalf("unexpected string %s", got)
+	}
+}
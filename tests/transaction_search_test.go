@@ -0,0 +1,163 @@
+// +build unit
+
+package tests
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/badu/braintree"
+)
+
+func TestTransactionSearchXML(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2020, time.January, 2, 3, 4, 5, 0, time.UTC)
+	s := NewTransactionSearch().
+		Status(StatusSettled, StatusSettling).
+		Type("credit").
+		Refund(true).
+		CreatedAt(from, time.Time{}).
+		Amount(NewDecimal(1050, 2), nil).
+		CreditCardBIN("411111").
+		CreditCardLast4("1111")
+	s.Billing(AddressPostalCode).Is = "60622"
+	query, err := s.Search()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := xml.Marshal(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<status type="array"><item>settled</item><item>settling</item></status>`,
+		`<type type="array"><item>credit</item></type>`,
+		`<refund>true</refund>`,
+		`<created-at><min type="datetime">2020-01-02T03:04:05Z</min></created-at>`,
+		`<amount><min>10.50</min></amount>`,
+		`<credit-card-number><starts-with>411111</starts-with><ends-with>1111</ends-with></credit-card-number>`,
+		`<billing-postal-code><is>60622</is></billing-postal-code>`,
+	} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("missing %s in %s", want, b)
+		}
+	}
+}
+
+func TestTransactionSearchBounds(t *testing.T) {
+	t.Parallel()
+
+	s := NewTransactionSearch().Amount(NewDecimal(0, 2), NewDecimal(10, 1))
+	s.Billing(AddressPostalCode).Is = "60622"
+	query, err := s.Search()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the builder goes on changing its fields, the built search is left untouched
+	s.Billing(AddressPostalCode).Is = "10001"
+	s.CreditCardBIN("411111")
+	b, err := xml.Marshal(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<search><amount><min>0.00</min><max>1.0</max></amount><billing-postal-code><is>60622</is></billing-postal-code></search>`
+	if string(b) != want {
+		t.Fatalf("expected %s, got %s", want, b)
+	}
+}
+
+func TestTransactionSearchRejectsInvalidCriteria(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	s := NewTransactionSearch().
+		Status("settle").
+		Type("sale").
+		Refund(true).
+		SettledAt(now, now.Add(-time.Hour)).
+		VoidedAt(time.Time{}, time.Time{}).
+		Amount(NewDecimal(500, 2), NewDecimal(100, 2)).
+		CreditCardBIN("4111").
+		CreditCardLast4("11a1").
+		MerchantAccountID()
+	s.Shipping("planet").Is = "Mars"
+	_, err := s.Search()
+	var invalid *InvalidSearchError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("expected an invalid search error, got %v", err)
+	}
+	for _, field := range []string{"status", "refund", "settled-at", "voided-at", "amount", "bin", "last 4", "merchant-account-id", "shipping-planet"} {
+		found := false
+		for _, problem := range invalid.Problems {
+			if strings.Contains(problem, field) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("no problem reported about %s in %v", field, invalid.Problems)
+		}
+	}
+
+	_, err = s.Search()
+	var again *InvalidSearchError
+	if !errors.As(err, &again) || len(again.Problems) != len(invalid.Problems) {
+		t.Fatalf("expected the same problems when searching again, got %v", err)
+	}
+}
+
+func TestTransactionSearchOnFakeGateway(t *testing.T) {
+	t.Parallel()
+
+	c, _, stop := fakeClient(t)
+	defer stop()
+	ctx := context.Background()
+
+	orderID := RandomString()
+	var sale *Tx
+	for _, amount := range []int64{500, 1500, 2500} {
+		tx, err := c.Pay(ctx, &TxRequest{
+			Type:       "sale",
+			Amount:     NewDecimal(amount, 2),
+			OrderId:    orderID,
+			CreditCard: &CreditCard{Number: testCardMastercard, ExpirationDate: "05/30"},
+			Options:    &TxOpts{SubmitForSettlement: true},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sale = tx
+	}
+	if _, err := c.SandboxSettle(ctx, sale.Id); err != nil {
+		t.Fatal(err)
+	}
+	refund, err := c.Refund(ctx, sale.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewTransactionSearch().Amount(NewDecimal(1000, 2), NewDecimal(3000, 2)).Type("sale")
+	s.OrderID().Is = orderID
+	query, err := s.Search()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := c.SearchTxs(ctx, query)
+	if err != nil || len(result.IDs) != 2 {
+		t.Fatalf("amount range : %v %+v", err, result)
+	}
+
+	s = NewTransactionSearch().Refund(true).CreditCardLast4(testCardMastercard[len(testCardMastercard)-4:])
+	s.OrderID().Is = orderID
+	if query, err = s.Search(); err != nil {
+		t.Fatal(err)
+	}
+	result, err = c.SearchTxs(ctx, query)
+	if err != nil || len(result.IDs) != 1 || result.IDs[0] != refund.Id {
+		t.Fatalf("refunds : %v %+v", err, result)
+	}
+}
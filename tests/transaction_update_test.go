@@ -0,0 +1,178 @@
+// +build unit
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/badu/braintree"
+)
+
+func TestUpdateTransactionDetails(t *testing.T) {
+	c, gateway, done := fakeClient(t)
+	defer done()
+	ctx := context.Background()
+
+	tx, err := c.Pay(ctx, &TxRequest{Type: TxTypeSale, Amount: NewDecimal(4200, 2), OrderId: "order-1", PaymentMethodNonce: "fake-valid-nonce"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.UpdateTransactionDetails(ctx, tx.Id, &TxDetailsRequest{OrderId: "order-2"})
+	if codes := validationCodes(err); len(codes) != 1 || codes[0] != string(CodeTransactionCannotUpdateDetailsUnlessSubmitted) {
+		t.Fatalf("updating an authorized transaction : got %v (%v)", codes, err)
+	}
+	if _, err := c.SubmitForSettlement(ctx, tx.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := c.UpdateTransactionDetails(ctx, tx.Id, &TxDetailsRequest{
+		Amount:     NewDecimal(3900, 2),
+		OrderId:    "order-2",
+		Descriptor: &Descriptor{Name: "bistros*dinner", Phone: "(555) 123-4567", URL: "bistro.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Status != StatusSubmittedForSettlement || updated.Amount.String() != "39.00" || updated.OrderId != "order-2" {
+		t.Fatalf("unexpected transaction %+v", updated)
+	}
+	if updated.Descriptor == nil || updated.Descriptor.Name != "bistros*dinner" {
+		t.Fatalf("unexpected descriptor %+v", updated.Descriptor)
+	}
+
+	_, err = c.UpdateTransactionDetails(ctx, tx.Id, &TxDetailsRequest{Amount: NewDecimal(4000, 2)})
+	if codes := validationCodes(err); len(codes) != 1 || codes[0] != string(CodeTransactionSettlementAmountIsTooLarge) {
+		t.Fatalf("raising the amount : got %v (%v)", codes, err)
+	}
+
+	for _, details := range []*TxDetailsRequest{
+		nil,
+		{},
+		{Amount: NewDecimal(0, 2)},
+		{Descriptor: &Descriptor{Name: "bistro dinner"}},
+		{Descriptor: &Descriptor{Phone: "555-1234"}},
+		{Descriptor: &Descriptor{URL: "bistro.example.com"}},
+	} {
+		if _, err := c.UpdateTransactionDetails(ctx, tx.Id, details); !errors.Is(err, ErrInvalidTxUpdate) {
+			t.Fatalf("%+v : got %v, want ErrInvalidTxUpdate", details, err)
+		}
+	}
+	if stored, _ := gateway.Transaction(tx.Id); stored.Amount.String() != "39.00" || stored.Descriptor.Name != "bistros*dinner" {
+		t.Fatalf("expected the invalid updates not to reach the gateway, got %+v", stored)
+	}
+}
+
+func TestDescriptorValidate(t *testing.T) {
+	for _, tc := range []struct {
+		descriptor Descriptor
+		valid      bool
+	}{
+		{descriptor: Descriptor{Name: "abc*product"}, valid: true},
+		{descriptor: Descriptor{Name: "company*product name 1"}, valid: true},
+		{descriptor: Descriptor{Name: "company name*product12"}, valid: true},
+		{descriptor: Descriptor{Name: "company name*product123"}},
+		{descriptor: Descriptor{Name: "ab*product"}},
+		{descriptor: Descriptor{Name: "abc*"}},
+		{descriptor: Descriptor{Name: "crêpes!*dîner spécial"}, valid: true},
+		{descriptor: Descriptor{Name: "crêpes!*dîner spéciales"}},
+		{descriptor: Descriptor{Phone: "+1 555.123.4567"}},
+		{descriptor: Descriptor{Phone: "555.123.4567"}, valid: true},
+		{descriptor: Descriptor{Phone: "123456789012345"}},
+		{descriptor: Descriptor{URL: "example.com"}, valid: true},
+		{descriptor: Descriptor{URL: "cafécrème.com"}, valid: true},
+	} {
+		err := tc.descriptor.Validate()
+		if (err == nil) != tc.valid || (err != nil && !errors.Is(err, ErrInvalidTxUpdate)) {
+			t.Errorf("%+v : got %v, want valid %t", tc.descriptor, err, tc.valid)
+		}
+	}
+}
+
+func TestAdjustAuthorization(t *testing.T) {
+	c, _, done := fakeClient(t)
+	defer done()
+	ctx := context.Background()
+
+	tx, err := c.Pay(ctx, &TxRequest{Type: TxTypeSale, Amount: NewDecimal(5000, 2), PaymentMethodNonce: "fake-valid-nonce"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a tip of 7.50
+	adjusted, err := c.AdjustAuthorization(ctx, tx.Id, &AdjustAuthorizationRequest{Amount: NewDecimal(5750, 2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if adjusted.Status != StatusAuthorized || adjusted.Amount.String() != "57.50" || len(adjusted.AuthorizationAdjustments) != 1 {
+		t.Fatalf("unexpected transaction %+v", adjusted)
+	}
+	if adjustment := adjusted.AuthorizationAdjustments[0]; !adjustment.Success || adjustment.Amount.String() != "57.50" || adjustment.ProcessorResponseCode != 1000 || adjustment.Timestamp == nil {
+		t.Fatalf("unexpected adjustment %+v", adjustment)
+	}
+
+	if adjusted, err = c.AdjustAuthorization(ctx, tx.Id, &AdjustAuthorizationRequest{Amount: NewDecimal(4500, 2)}); err != nil {
+		t.Fatal(err)
+	}
+	if adjusted.Amount.String() != "45.00" || len(adjusted.AuthorizationAdjustments) != 2 {
+		t.Fatalf("unexpected transaction %+v", adjusted)
+	}
+
+	_, err = c.AdjustAuthorization(ctx, tx.Id, &AdjustAuthorizationRequest{Amount: NewDecimal(4500, 2)})
+	if codes := validationCodes(err); len(codes) != 1 || codes[0] != string(CodeTransactionNoNetAmountToPerformAuthAdjustment) {
+		t.Fatalf("adjusting to the same amount : got %v (%v)", codes, err)
+	}
+
+	_, err = c.AdjustAuthorization(ctx, tx.Id, &AdjustAuthorizationRequest{Amount: NewDecimal(250000, 2)})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Transaction == nil {
+		t.Fatalf("got %v, want the declined adjustment", err)
+	}
+	declined := apiErr.Transaction
+	if declined.Amount.String() != "45.00" || len(declined.AuthorizationAdjustments) != 3 {
+		t.Fatalf("unexpected transaction %+v", declined)
+	}
+	if adjustment := declined.AuthorizationAdjustments[2]; adjustment.Success || adjustment.ProcessorResponseCode != 2500 {
+		t.Fatalf("unexpected adjustment %+v", adjustment)
+	}
+
+	for _, adjustment := range []*AdjustAuthorizationRequest{nil, {}, {Amount: NewDecimal(0, 2)}, {Amount: NewDecimal(-100, 2)}} {
+		if _, err := c.AdjustAuthorization(ctx, tx.Id, adjustment); !errors.Is(err, ErrInvalidTxUpdate) {
+			t.Fatalf("%+v : got %v, want ErrInvalidTxUpdate", adjustment, err)
+		}
+	}
+
+	if _, err := c.SubmitForSettlement(ctx, tx.Id); err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.AdjustAuthorization(ctx, tx.Id, &AdjustAuthorizationRequest{Amount: NewDecimal(5000, 2)})
+	if codes := validationCodes(err); len(codes) != 1 || codes[0] != string(CodeTransactionMustBeInStateAuthorized) {
+		t.Fatalf("adjusting a submitted transaction : got %v (%v)", codes, err)
+	}
+}
+
+func TestAdjustAuthorizationResetsPartialSettlements(t *testing.T) {
+	c, _, done := fakeClient(t)
+	defer done()
+	ctx := context.Background()
+
+	auth, err := c.Pay(ctx, &TxRequest{Type: TxTypeSale, Amount: NewDecimal(10000, 2), PaymentMethodNonce: "fake-valid-nonce"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.SubmitForPartialSettlement(ctx, auth.Id, NewDecimal(6000, 2), nil); err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.AdjustAuthorization(ctx, auth.Id, &AdjustAuthorizationRequest{Amount: NewDecimal(5000, 2)})
+	if codes := validationCodes(err); len(codes) != 1 || codes[0] != string(CodeTransactionSettlementAmountIsTooLarge) {
+		t.Fatalf("lowering below the settled amount : got %v (%v)", codes, err)
+	}
+	if _, err := c.AdjustAuthorization(ctx, auth.Id, &AdjustAuthorizationRequest{Amount: NewDecimal(12000, 2)}); err != nil {
+		t.Fatal(err)
+	}
+	remaining, err := c.RemainingAuthorization(ctx, auth.Id)
+	if err != nil || remaining.String() != "60.00" {
+		t.Fatalf("got remaining %v (%v), want 60.00", remaining, err)
+	}
+}
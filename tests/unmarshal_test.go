@@ -328,3 +328,112 @@ func TestDecimalUnmarshalText(t *testing.T) {
 		}
 	}
 }
+
+func TestSubscriptionUnmarshalXML(t *testing.T) {
+	x := `
+	<subscription>
+		<id>sub1</id>
+		<billing-day-of-month type="integer">15</billing-day-of-month>
+		<billing-period-start-date type="date">2026-03-15</billing-period-start-date>
+		<billing-period-end-date type="date">2026-04-14</billing-period-end-date>
+		<current-billing-cycle type="integer">2</current-billing-cycle>
+		<days-past-due nil="true"></days-past-due>
+		<failure-count type="integer">1</failure-count>
+		<first-billing-date type="date">2026-02-15</first-billing-date>
+		<next-billing-date type="date">2026-04-15</next-billing-date>
+		<paid-through-date nil="true"></paid-through-date>
+		<trial-duration nil="true"></trial-duration>
+		<status>Active</status>
+		<transactions type="array">
+			<transaction>
+				<id>tx1</id>
+				<subscription>
+					<billing-period-start-date type="date">2026-03-15</billing-period-start-date>
+					<billing-period-end-date type="date">2026-04-14</billing-period-end-date>
+				</subscription>
+			</transaction>
+			<transaction>
+				<id>tx2</id>
+				<subscription>
+					<billing-period-start-date nil="true"/>
+					<billing-period-end-date nil="true"/>
+				</subscription>
+			</transaction>
+		</transactions>
+	</subscription>
+	`
+	var s Subscription
+	if err := xml.Unmarshal([]byte(x), &s); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.BillingDayOfMonth != 15 || s.CurrentBillingCycle != 2 || s.DaysPastDue != 0 || s.FailureCount != 1 || s.TrialDuration != 0 {
+		t.Errorf("got counters %d %d %d %d %d", s.BillingDayOfMonth, s.CurrentBillingCycle, s.DaysPastDue, s.FailureCount, s.TrialDuration)
+	}
+	if s.PaidThroughDate != nil {
+		t.Errorf("got paid through date %v, want nil", s.PaidThroughDate)
+	}
+	want := time.Date(2026, time.April, 15, 0, 0, 0, 0, time.UTC)
+	if s.NextBillingDate == nil || !s.NextBillingDate.Equal(want) {
+		t.Errorf("got next billing date %v, want %v", s.NextBillingDate, want)
+	}
+	raw := []string{
+		s.RawBillingDayOfMonth(), s.RawBillingPeriodStartDate(), s.RawBillingPeriodEndDate(), s.RawCurrentBillingCycle(),
+		s.RawDaysPastDue(), s.RawFailureCount(), s.RawFirstBillingDate(), s.RawNextBillingDate(), s.RawPaidThroughDate(),
+		s.RawTrialDuration(),
+	}
+	wantRaw := []string{"15", "2026-03-15", "2026-04-14", "2", "", "1", "2026-02-15", "2026-04-15", "", ""}
+	if !reflect.DeepEqual(raw, wantRaw) {
+		t.Errorf("got raw values %q, want %q", raw, wantRaw)
+	}
+
+	txs := s.Transactions.Transaction
+	if len(txs) != 2 {
+		t.Fatalf("got %d transactions, want 2", len(txs))
+	}
+	if d := txs[0].SubscriptionDetails; d == nil || d.RawBillingPeriodStartDate() != "2026-03-15" || d.RawBillingPeriodEndDate() != "2026-04-14" {
+		t.Errorf("got subscription details %+v", d)
+	}
+	if d := txs[1].SubscriptionDetails; d != nil {
+		t.Errorf("got subscription details %+v, want nil", d)
+	}
+}
+
+func TestSubscriptionRawValues(t *testing.T) {
+	x := `
+	<subscription>
+		<id>sub1</id>
+		<days-past-due type="integer">0</days-past-due>
+		<trial-duration type="integer">0</trial-duration>
+		<status>Active</status>
+	</subscription>
+	`
+	var s Subscription
+	if err := xml.Unmarshal([]byte(x), &s); err != nil {
+		t.Fatal(err)
+	}
+	raw := []string{s.RawBillingDayOfMonth(), s.RawCurrentBillingCycle(), s.RawDaysPastDue(), s.RawFailureCount(), s.RawTrialDuration()}
+	wantRaw := []string{"", "", "0", "", "0"}
+	if !reflect.DeepEqual(raw, wantRaw) {
+		t.Errorf("got raw values %q, want %q", raw, wantRaw)
+	}
+
+	built := &Subscription{BillingDayOfMonth: 15, NextBillingDate: &Date{Time: time.Date(2026, time.April, 15, 0, 0, 0, 0, time.UTC)}}
+	raw = []string{built.RawBillingDayOfMonth(), built.RawNextBillingDate(), built.RawDaysPastDue(), built.RawPaidThroughDate()}
+	wantRaw = []string{"15", "2026-04-15", "", ""}
+	if !reflect.DeepEqual(raw, wantRaw) {
+		t.Errorf("got formatted values %q, want %q", raw, wantRaw)
+	}
+}
+
+func TestSubscriptionRequestFirstBillingDate(t *testing.T) {
+	r := SubscriptionRequest{PlanId: "monthly", FirstBillingDate: &Date{Time: time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC)}}
+	b, err := xml.Marshal(&r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<subscription><first-billing-date>2026-12-31</first-billing-date><planId>monthly</planId></subscription>`
+	if string(b) != want {
+		t.Errorf("got %s, want %s", b, want)
+	}
+}
@@ -0,0 +1,200 @@
+// +build unit
+
+package tests
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"testing"
+
+	. "github.com/badu/braintree"
+)
+
+func TestCreateVerification(t *testing.T) {
+	c, _, done := fakeClient(t)
+	defer done()
+	ctx := context.Background()
+
+	v, err := c.CreateVerification(ctx, &CreditCardVerificationRequest{
+		CreditCard: &CreditCard{Number: testCardVisa, ExpirationDate: "05/2030", CardholderName: "Jane Doe"},
+		Options:    &VerificationOpts{Amount: NewDecimal(150, 2)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v.Verified() || v.Amount.String() != "1.50" || v.ProcessorResponseCode != 1000 || v.AVSPostalCodeResponseCode != AVSResponseCodeMatches {
+		t.Fatalf("unexpected verification %+v", v)
+	}
+	if v.CreditCard == nil || v.CreditCard.Last4 != "1111" || v.CreditCard.Token != "" {
+		t.Fatalf("unexpected verified card %+v", v.CreditCard)
+	}
+
+	found, err := c.FindVerification(ctx, v.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Id != v.Id || found.Status != VerificationStatusVerified {
+		t.Fatalf("found %+v, want %+v", found, v)
+	}
+
+	_, err = c.FindVerification(ctx, "missing")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got %v, want not found", err)
+	}
+}
+
+func TestCreateVerificationDeclined(t *testing.T) {
+	c, _, done := fakeClient(t)
+	defer done()
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		number string
+		amount *Decimal
+		code   ResponseCode
+	}{
+		{number: "4000111111111115", code: 2000},
+		{number: testCardVisa, amount: NewDecimal(201000, 2), code: 2010},
+	} {
+		_, err := c.CreateVerification(ctx, &CreditCardVerificationRequest{
+			CreditCard: &CreditCard{Number: tc.number, ExpirationDate: "05/2030"},
+			Options:    &VerificationOpts{Amount: tc.amount},
+		})
+		if !errors.Is(err, ErrProcessorDeclined) {
+			t.Fatalf("%s : got %v, want a processor declined error", tc.number, err)
+		}
+		apiErr := err.(*APIError)
+		v := apiErr.Verification
+		if v == nil || v.Status != VerificationStatusProcessorDeclined || v.ProcessorResponseCode != tc.code || apiErr.Error() != v.ProcessorResponseText {
+			t.Fatalf("%s : unexpected verification %+v", tc.number, v)
+		}
+	}
+
+	_, err := c.CreateVerification(ctx, &CreditCardVerificationRequest{
+		CreditCard: &CreditCard{Number: "4000111111111511", ExpirationDate: "05/2030"},
+	})
+	if !errors.Is(err, ErrGatewayRejected) {
+		t.Fatalf("got %v, want a gateway rejected error", err)
+	}
+	if v := err.(*APIError).Verification; v.GatewayRejectionReason != FraudReason {
+		t.Fatalf("unexpected verification %+v", v)
+	}
+}
+
+func TestVerifyCardOnVault(t *testing.T) {
+	c, _, done := fakeClient(t)
+	defer done()
+	ctx := context.Background()
+
+	_, err := c.CreateCustomer(ctx, &CustomerRequest{
+		ID: "declined",
+		CreditCard: &CreditCard{
+			Number:         "4000111111111115",
+			ExpirationDate: "05/2030",
+			Options:        &CreditCardOptions{VerifyCard: BoolPtr(true)},
+		},
+	})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Verification == nil || apiErr.Verification.Status != VerificationStatusProcessorDeclined {
+		t.Fatalf("got %v, want the failed verification", err)
+	}
+	if apiErr.Verification.CreditCard == nil || apiErr.Verification.CreditCard.Last4 != "1115" {
+		t.Fatalf("unexpected verification card %+v", apiErr.Verification.CreditCard)
+	}
+	if _, err := c.FindCustomer(ctx, "declined"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got %v, want the customer not to be created", err)
+	}
+
+	if _, err := c.CreateCustomer(ctx, &CustomerRequest{
+		ID: "verified",
+		CreditCard: &CreditCard{
+			Number:         testCardVisa,
+			ExpirationDate: "05/2030",
+			Options:        &CreditCardOptions{VerifyCard: BoolPtr(true)},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.CreatePayMethod(ctx, &PaymentMethodRequest{
+		CustomerId:         "verified",
+		PaymentMethodNonce: "fake-valid-visa-nonce",
+		Options:            &PaymentMethodRequestOptions{VerifyCard: BoolPtr(true), VerificationMerchantAccountId: "eur_account"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query, err := NewCreditCardVerificationSearch().Status(VerificationStatusVerified).Search()
+	if err != nil {
+		t.Fatal(err)
+	}
+	it := c.IterateVerifications(query)
+	var merchants []string
+	for it.Next(ctx) {
+		merchants = append(merchants, it.Value().MerchantAccountId)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(merchants) != 2 || merchants[1] != "eur_account" {
+		t.Fatalf("got verified merchant accounts %v", merchants)
+	}
+
+	query, err = NewCreditCardVerificationSearch().CreditCardLast4("1115").Search()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := c.SearchVerifications(ctx, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.IDs) != 1 {
+		t.Fatalf("got %d declined verifications, want 1", len(result.IDs))
+	}
+}
+
+func TestAPIErrorVerificationUnmarshalXML(t *testing.T) {
+	x := `<?xml version="1.0" encoding="UTF-8"?>
+	<api-error-response>
+		<errors><errors type="array"/></errors>
+		<verification>
+			<id>v1</id>
+			<status>processor_declined</status>
+			<amount>1.00</amount>
+			<processor-response-code>2000</processor-response-code>
+			<processor-response-text>Do Not Honor</processor-response-text>
+			<avs-postal-code-response-code>M</avs-postal-code-response-code>
+			<avs-street-address-response-code>I</avs-street-address-response-code>
+			<cvv-response-code>N</cvv-response-code>
+			<credit-card><bin>400011</bin><last-4>1115</last-4></credit-card>
+			<risk-data><id>risk1</id><decision>Approve</decision></risk-data>
+			<three-d-secure-info><status>authenticate_successful</status><liability-shifted>true</liability-shifted></three-d-secure-info>
+		</verification>
+		<message>Do Not Honor</message>
+	</api-error-response>`
+
+	var apiErr APIError
+	if err := xml.Unmarshal([]byte(x), &apiErr); err != nil {
+		t.Fatal(err)
+	}
+	v := apiErr.Verification
+	if v == nil {
+		t.Fatal("expected a verification")
+	}
+	if v.Status != VerificationStatusProcessorDeclined || v.ProcessorResponseCode != 2000 || v.Amount.String() != "1.00" {
+		t.Errorf("unexpected verification %+v", v)
+	}
+	if v.AVSPostalCodeResponseCode != AVSResponseCodeMatches || v.AVSStreetAddressResponseCode != AVSResponseCodeNotProvided || v.CVVResponseCode != CVVResponseCodeDoesNotMatch {
+		t.Errorf("unexpected response codes %q %q %q", v.AVSPostalCodeResponseCode, v.AVSStreetAddressResponseCode, v.CVVResponseCode)
+	}
+	if v.CreditCard == nil || v.CreditCard.Last4 != "1115" || v.RiskData == nil || v.RiskData.Decision != "Approve" {
+		t.Errorf("unexpected card or risk data %+v %+v", v.CreditCard, v.RiskData)
+	}
+	if v.ThreeDSecureInfo == nil || !v.ThreeDSecureInfo.LiabilityShifted {
+		t.Errorf("unexpected 3D secure info %+v", v.ThreeDSecureInfo)
+	}
+	if !errors.Is(&apiErr, ErrProcessorDeclined) {
+		t.Error("expected the error to match ErrProcessorDeclined")
+	}
+}
@@ -9,11 +9,11 @@ import (
 const clientTokenVersion = 2
 
 type TokenRequest struct {
-	XMLName           string   `xml:"client-token"`
-	CustomerID        string   `xml:"customer-id,omitempty"`
-	MerchantAccountID string   `xml:"merchant-account-id,omitempty"`
-	Options           *Options `xml:"options,omitempty"`
-	Version           int      `xml:"version"`
+	XMLName           string   `xml:"client-token" json:"-"`
+	CustomerID        string   `xml:"customer-id,omitempty" json:"customer_id,omitempty"`
+	MerchantAccountID string   `xml:"merchant-account-id,omitempty" json:"merchant_account_id,omitempty"`
+	Options           *Options `xml:"options,omitempty" json:"options,omitempty"`
+	Version           int      `xml:"version" json:"version"`
 }
 
 type clientToken struct {
@@ -21,21 +21,21 @@ type clientToken struct {
 }
 
 type Options struct {
-	FailOnDuplicatePaymentMethod bool  `xml:"fail-on-duplicate-payment-method,omitempty"`
-	MakeDefault                  bool  `xml:"make-default,omitempty"`
-	VerifyCard                   *bool `xml:"verify-card,omitempty"`
+	FailOnDuplicatePaymentMethod bool  `xml:"fail-on-duplicate-payment-method,omitempty" json:"fail_on_duplicate_payment_method,omitempty"`
+	MakeDefault                  bool  `xml:"make-default,omitempty" json:"make_default,omitempty"`
+	VerifyCard                   *bool `xml:"verify-card,omitempty" json:"verify_card,omitempty"`
 }
 
 const clientTokenPath = "client_token"
 
 func (c *APIClient) GenerateToken(ctx context.Context) (string, error) {
-	return c.generate(ctx, &TokenRequest{
+	return c.generate(ctx, "GenerateToken", &TokenRequest{
 		Version: clientTokenVersion,
 	})
 }
 
 func (c *APIClient) GenerateWithCustomer(ctx context.Context, custID string) (string, error) {
-	return c.generate(ctx, &TokenRequest{
+	return c.generate(ctx, "GenerateWithCustomer", &TokenRequest{
 		Version:    clientTokenVersion,
 		CustomerID: custID,
 	})
@@ -48,11 +48,11 @@ func (c *APIClient) GenerateWithRequest(ctx context.Context, request *TokenReque
 	if request.Version == 0 {
 		request.Version = clientTokenVersion
 	}
-	return c.generate(ctx, request)
+	return c.generate(ctx, "GenerateWithRequest", request)
 }
 
-func (c *APIClient) generate(ctx context.Context, request *TokenRequest) (string, error) {
-	resp, err := c.do(ctx, http.MethodPost, clientTokenPath, request)
+func (c *APIClient) generate(ctx context.Context, operation string, request *TokenRequest) (string, error) {
+	resp, err := c.do(ctx, operation, http.MethodPost, clientTokenPath, request)
 	if err != nil {
 		return "", err
 	}
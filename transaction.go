@@ -5,8 +5,10 @@ import (
 	"encoding/xml"
 	"fmt"
 	"net/http"
+	"regexp"
 	"strconv"
 	"time"
+	"unicode/utf8"
 )
 
 const transactionsPath = "transactions"
@@ -65,19 +67,19 @@ const (
 )
 
 type AndroidPayDetail struct {
-	Token               string `xml:"token"`
-	CardType            string `xml:"-"`
-	Last4               string `xml:"-"`
-	SourceCardType      string `xml:"source-card-type"`
-	SourceCardLast4     string `xml:"source-card-last-4"`
-	SourceDescription   string `xml:"source-description"`
-	VirtualCardType     string `xml:"virtual-card-type"`
-	VirtualCardLast4    string `xml:"virtual-card-last-4"`
-	ExpirationMonth     string `xml:"expiration-month"`
-	ExpirationYear      string `xml:"expiration-year"`
-	BIN                 string `xml:"bin"`
-	GoogleTransactionID string `xml:"google-transaction-id"`
-	ImageURL            string `xml:"image-url"`
+	Token               string `xml:"token" json:"token"`
+	CardType            string `xml:"-" json:"-"`
+	Last4               string `xml:"-" json:"-"`
+	SourceCardType      string `xml:"source-card-type" json:"source_card_type"`
+	SourceCardLast4     string `xml:"source-card-last-4" json:"source_card_last_4"`
+	SourceDescription   string `xml:"source-description" json:"source_description"`
+	VirtualCardType     string `xml:"virtual-card-type" json:"virtual_card_type"`
+	VirtualCardLast4    string `xml:"virtual-card-last-4" json:"virtual_card_last_4"`
+	ExpirationMonth     string `xml:"expiration-month" json:"expiration_month"`
+	ExpirationYear      string `xml:"expiration-year" json:"expiration_year"`
+	BIN                 string `xml:"bin" json:"bin"`
+	GoogleTransactionID string `xml:"google-transaction-id" json:"google_transaction_id"`
+	ImageURL            string `xml:"image-url" json:"image_url"`
 }
 
 func (a *AndroidPayDetail) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
@@ -91,17 +93,25 @@ func (a *AndroidPayDetail) UnmarshalXML(d *xml.Decoder, start xml.StartElement)
 }
 
 type ApplePayDetail struct {
-	Token                 string `xml:"token"`
-	CardType              string `xml:"card-type"`
-	PaymentInstrumentName string `xml:"payment-instrument-name"`
-	SourceDescription     string `xml:"source-description"`
-	CardholderName        string `xml:"cardholder-name"`
-	ExpirationMonth       string `xml:"expiration-month"`
-	ExpirationYear        string `xml:"expiration-year"`
-	Last4                 string `xml:"last-4"`
-	BIN                   string `xml:"bin"`
+	Token                 string `xml:"token" json:"token"`
+	CardType              string `xml:"card-type" json:"card_type"`
+	PaymentInstrumentName string `xml:"payment-instrument-name" json:"payment_instrument_name"`
+	SourceDescription     string `xml:"source-description" json:"source_description"`
+	CardholderName        string `xml:"cardholder-name" json:"cardholder_name"`
+	ExpirationMonth       string `xml:"expiration-month" json:"expiration_month"`
+	ExpirationYear        string `xml:"expiration-year" json:"expiration_year"`
+	Last4                 string `xml:"last-4" json:"last_4"`
+	BIN                   string `xml:"bin" json:"bin"`
 }
 
+// TxType is the type of a transaction : a sale charges the payment method, a credit pays money to it
+type TxType string
+
+const (
+	TxTypeSale   TxType = "sale"
+	TxTypeCredit TxType = "credit"
+)
+
 type AVSResponseCode string
 
 const (
@@ -126,9 +136,9 @@ const (
 )
 
 type Descriptor struct {
-	Name  string `xml:"name,omitempty"`
-	Phone string `xml:"phone,omitempty"`
-	URL   string `xml:"url,omitempty"`
+	Name  string `xml:"name,omitempty" json:"name,omitempty"`
+	Phone string `xml:"phone,omitempty" json:"phone,omitempty"`
+	URL   string `xml:"url,omitempty" json:"url,omitempty"`
 }
 
 type EscrowStatus string
@@ -142,10 +152,10 @@ const (
 )
 
 type ThreeDSecureInfo struct {
-	Status                 ThreeDSecureStatus   `xml:"status"`
-	Enrolled               ThreeDSecureEnrolled `xml:"enrolled"`
-	LiabilityShiftPossible bool                 `xml:"liability-shift-possible"`
-	LiabilityShifted       bool                 `xml:"liability-shifted"`
+	Status                 ThreeDSecureStatus   `xml:"status" json:"status"`
+	Enrolled               ThreeDSecureEnrolled `xml:"enrolled" json:"enrolled"`
+	LiabilityShiftPossible bool                 `xml:"liability-shift-possible" json:"liability_shift_possible"`
+	LiabilityShifted       bool                 `xml:"liability-shifted" json:"liability_shifted"`
 }
 
 type ThreeDSecureStatus string
@@ -212,131 +222,136 @@ const (
 )
 
 type DisbursementDetail struct {
-	XMLName                        xml.Name `xml:"disbursement-details"`
-	DisbursementDate               string   `xml:"disbursement-date"`
-	SettlementAmount               *Decimal `xml:"settlement-amount"`
-	SettlementCurrencyIsoCode      string   `xml:"settlement-currency-iso-code"`
-	SettlementCurrencyExchangeRate *Decimal `xml:"settlement-currency-exchange-rate"`
-	FundsHeld                      bool     `xml:"funds-held"`
-	Success                        bool     `xml:"success"`
+	XMLName                        xml.Name `xml:"disbursement-details" json:"-"`
+	DisbursementDate               string   `xml:"disbursement-date" json:"disbursement_date"`
+	SettlementAmount               *Decimal `xml:"settlement-amount" json:"settlement_amount"`
+	SettlementCurrencyIsoCode      string   `xml:"settlement-currency-iso-code" json:"settlement_currency_iso_code"`
+	SettlementCurrencyExchangeRate *Decimal `xml:"settlement-currency-exchange-rate" json:"settlement_currency_exchange_rate"`
+	FundsHeld                      bool     `xml:"funds-held" json:"funds_held"`
+	Success                        bool     `xml:"success" json:"success"`
 }
 
 type PayPalDetail struct {
-	PayerEmail                    string `xml:"payer-email,omitempty"`
-	PaymentID                     string `xml:"payment-id,omitempty"`
-	AuthorizationID               string `xml:"authorization-id,omitempty"`
-	Token                         string `xml:"token,omitempty"`
-	ImageURL                      string `xml:"image-url,omitempty"`
-	DebugID                       string `xml:"debug-id,omitempty"`
-	PayeeEmail                    string `xml:"payee-email,omitempty"`
-	CustomField                   string `xml:"custom-field,omitempty"`
-	PayerID                       string `xml:"payer-id,omitempty"`
-	PayerFirstName                string `xml:"payer-first-name,omitempty"`
-	PayerLastName                 string `xml:"payer-last-name,omitempty"`
-	PayerStatus                   string `xml:"payer-status,omitempty"`
-	SellerProtectionStatus        string `xml:"seller-protection-status,omitempty"`
-	RefundID                      string `xml:"refund-id,omitempty"`
-	CaptureID                     string `xml:"capture-id,omitempty"`
-	TransactionFeeAmount          string `xml:"transaction-fee-amount,omitempty"`
-	TransactionFeeCurrencyISOCode string `xml:"transaction-fee-currency-iso-code,omitempty"`
-	Description                   string `xml:"description,omitempty"`
+	PayerEmail                    string `xml:"payer-email,omitempty" json:"payer_email,omitempty"`
+	PaymentID                     string `xml:"payment-id,omitempty" json:"payment_id,omitempty"`
+	AuthorizationID               string `xml:"authorization-id,omitempty" json:"authorization_id,omitempty"`
+	Token                         string `xml:"token,omitempty" json:"token,omitempty"`
+	ImageURL                      string `xml:"image-url,omitempty" json:"image_url,omitempty"`
+	DebugID                       string `xml:"debug-id,omitempty" json:"debug_id,omitempty"`
+	PayeeEmail                    string `xml:"payee-email,omitempty" json:"payee_email,omitempty"`
+	CustomField                   string `xml:"custom-field,omitempty" json:"custom_field,omitempty"`
+	PayerID                       string `xml:"payer-id,omitempty" json:"payer_id,omitempty"`
+	PayerFirstName                string `xml:"payer-first-name,omitempty" json:"payer_first_name,omitempty"`
+	PayerLastName                 string `xml:"payer-last-name,omitempty" json:"payer_last_name,omitempty"`
+	PayerStatus                   string `xml:"payer-status,omitempty" json:"payer_status,omitempty"`
+	SellerProtectionStatus        string `xml:"seller-protection-status,omitempty" json:"seller_protection_status,omitempty"`
+	RefundID                      string `xml:"refund-id,omitempty" json:"refund_id,omitempty"`
+	CaptureID                     string `xml:"capture-id,omitempty" json:"capture_id,omitempty"`
+	TransactionFeeAmount          string `xml:"transaction-fee-amount,omitempty" json:"transaction_fee_amount,omitempty"`
+	TransactionFeeCurrencyISOCode string `xml:"transaction-fee-currency-iso-code,omitempty" json:"transaction_fee_currency_iso_code,omitempty"`
+	Description                   string `xml:"description,omitempty" json:"description,omitempty"`
 }
 
 type VenmoAccountDetail struct {
-	Token             string `xml:"token,omitempty"`
-	Username          string `xml:"username,omitempty"`
-	VenmoUserID       string `xml:"venmo-user-id,omitempty"`
-	SourceDescription string `xml:"source-description,omitempty"`
-	ImageURL          string `xml:"image-url,omitempty"`
+	Token             string `xml:"token,omitempty" json:"token,omitempty"`
+	Username          string `xml:"username,omitempty" json:"username,omitempty"`
+	VenmoUserID       string `xml:"venmo-user-id,omitempty" json:"venmo_user_id,omitempty"`
+	SourceDescription string `xml:"source-description,omitempty" json:"source_description,omitempty"`
+	ImageURL          string `xml:"image-url,omitempty" json:"image_url,omitempty"`
 }
 
 type Tx struct {
-	ProcessorResponseCode        ResponseCode        `xml:"processor-response-code"`
-	ProcessorResponseType        ResponseType        `xml:"processor-response-type"`
-	EscrowStatus                 EscrowStatus        `xml:"escrow-status"`
-	PaymentInstrumentType        PaymentType         `xml:"payment-instrument-type"`
-	AVSErrorResponseCode         AVSResponseCode     `xml:"avs-error-response-code"`
-	AVSPostalCodeResponseCode    AVSResponseCode     `xml:"avs-postal-code-response-code"`
-	AVSStreetAddressResponseCode AVSResponseCode     `xml:"avs-street-address-response-code"`
-	CVVResponseCode              CVVResponseCode     `xml:"cvv-response-code"`
-	GatewayRejectionReason       RejectionReason     `xml:"gateway-rejection-reason"`
-	CustomFields                 CustomFields        `xml:"custom-fields"`
-	ProcessorAuthorizationCode   string              `xml:"processor-authorization-code"`
-	SettlementBatchId            string              `xml:"settlement-batch-id"`
-	XMLName                      string              `xml:"transaction"`
-	Id                           string              `xml:"id"`
-	Status                       Status              `xml:"status"`
-	Type                         string              `xml:"type"`
-	CurrencyISOCode              string              `xml:"currency-iso-code"`
-	OrderId                      string              `xml:"order-id"`
-	PaymentMethodToken           string              `xml:"payment-method-token"`
-	PaymentMethodNonce           string              `xml:"payment-method-nonce"`
-	MerchantAccountId            string              `xml:"merchant-account-id"`
-	PlanId                       string              `xml:"plan-id"`
-	SubscriptionId               string              `xml:"subscription-id"`
-	DeviceData                   string              `xml:"device-data"`
-	RefundId                     string              `xml:"refund-id"`
-	ProcessorResponseText        string              `xml:"processor-response-text"`
-	AdditionalProcessorResponse  string              `xml:"additional-processor-response"`
-	Channel                      string              `xml:"channel"`
-	PurchaseOrderNumber          string              `xml:"purchase-order-number"`
-	TaxExempt                    bool                `xml:"tax-exempt"`
-	CreatedAt                    *time.Time          `xml:"created-at"`
-	UpdatedAt                    *time.Time          `xml:"updated-at"`
-	AuthorizationExpiresAt       *time.Time          `xml:"authorization-expires-at"`
-	ThreeDSecureInfo             *ThreeDSecureInfo   `xml:"three-d-secure-info,omitempty"`
-	Amount                       *Decimal            `xml:"amount"`
-	SubscriptionDetails          *SubscriptionDetail `xml:"subscription"`
-	CreditCard                   *CreditCard         `xml:"credit-card"`
-	Customer                     *Customer           `xml:"customer"`
-	BillingAddress               *Address            `xml:"billing"`
-	ShippingAddress              *Address            `xml:"shipping"`
-	TaxAmount                    *Decimal            `xml:"tax-amount"`
-	ServiceFeeAmount             *Decimal            `xml:"service-fee-amount,attr"`
-	DisbursementDetails          *DisbursementDetail `xml:"disbursement-details"`
-	PayPalDetails                *PayPalDetail       `xml:"paypal"`
-	VenmoAccountDetails          *VenmoAccountDetail `xml:"venmo-account"`
-	AndroidPayDetails            *AndroidPayDetail   `xml:"android-pay-card"`
-	ApplePayDetails              *ApplePayDetail     `xml:"apple-pay"`
-	RiskData                     *RiskData           `xml:"risk-data"`
-	Descriptor                   *Descriptor         `xml:"descriptor"`
-	RefundedTransactionId        *string             `xml:"refunded-transaction-id"`
-	RefundIds                    *[]string           `xml:"refund-ids>item"`
-	Disputes                     []*Dispute          `xml:"disputes>dispute"`
+	ProcessorResponseCode        ResponseCode        `xml:"processor-response-code" json:"processor_response_code"`
+	ProcessorResponseType        ResponseType        `xml:"processor-response-type" json:"processor_response_type"`
+	EscrowStatus                 EscrowStatus        `xml:"escrow-status" json:"escrow_status"`
+	PaymentInstrumentType        PaymentType         `xml:"payment-instrument-type" json:"payment_instrument_type"`
+	AVSErrorResponseCode         AVSResponseCode     `xml:"avs-error-response-code" json:"avs_error_response_code"`
+	AVSPostalCodeResponseCode    AVSResponseCode     `xml:"avs-postal-code-response-code" json:"avs_postal_code_response_code"`
+	AVSStreetAddressResponseCode AVSResponseCode     `xml:"avs-street-address-response-code" json:"avs_street_address_response_code"`
+	CVVResponseCode              CVVResponseCode     `xml:"cvv-response-code" json:"cvv_response_code"`
+	GatewayRejectionReason       RejectionReason     `xml:"gateway-rejection-reason" json:"gateway_rejection_reason"`
+	CustomFields                 CustomFields        `xml:"custom-fields" json:"custom_fields"`
+	ProcessorAuthorizationCode   string              `xml:"processor-authorization-code" json:"processor_authorization_code"`
+	SettlementBatchId            string              `xml:"settlement-batch-id" json:"settlement_batch_id"`
+	XMLName                      string              `xml:"transaction" json:"-"`
+	Id                           string              `xml:"id" json:"id"`
+	Status                       Status              `xml:"status" json:"status"`
+	Type                         TxType              `xml:"type" json:"type"`
+	CurrencyISOCode              string              `xml:"currency-iso-code" json:"currency_iso_code"`
+	OrderId                      string              `xml:"order-id" json:"order_id"`
+	PaymentMethodToken           string              `xml:"payment-method-token" json:"payment_method_token"`
+	PaymentMethodNonce           string              `xml:"payment-method-nonce" json:"payment_method_nonce"`
+	MerchantAccountId            string              `xml:"merchant-account-id" json:"merchant_account_id"`
+	PlanId                       string              `xml:"plan-id" json:"plan_id"`
+	SubscriptionId               string              `xml:"subscription-id" json:"subscription_id"`
+	DeviceData                   string              `xml:"device-data" json:"device_data"`
+	RefundId                     string              `xml:"refund-id" json:"refund_id"`
+	ProcessorResponseText        string              `xml:"processor-response-text" json:"processor_response_text"`
+	AdditionalProcessorResponse  string              `xml:"additional-processor-response" json:"additional_processor_response"`
+	Channel                      string              `xml:"channel" json:"channel"`
+	PurchaseOrderNumber          string              `xml:"purchase-order-number" json:"purchase_order_number"`
+	TaxExempt                    bool                `xml:"tax-exempt" json:"tax_exempt"`
+	CreatedAt                    *time.Time          `xml:"created-at" json:"created_at"`
+	UpdatedAt                    *time.Time          `xml:"updated-at" json:"updated_at"`
+	AuthorizationExpiresAt       *time.Time          `xml:"authorization-expires-at" json:"authorization_expires_at"`
+	ThreeDSecureInfo             *ThreeDSecureInfo   `xml:"three-d-secure-info,omitempty" json:"three_d_secure_info,omitempty"`
+	Amount                       *Decimal            `xml:"amount" json:"amount"`
+	SubscriptionDetails          *SubscriptionDetail `xml:"subscription" json:"subscription"`
+	CreditCard                   *CreditCard         `xml:"credit-card" json:"credit_card"`
+	Customer                     *Customer           `xml:"customer" json:"customer"`
+	BillingAddress               *Address            `xml:"billing" json:"billing"`
+	ShippingAddress              *Address            `xml:"shipping" json:"shipping"`
+	TaxAmount                    *Decimal            `xml:"tax-amount" json:"tax_amount"`
+	ServiceFeeAmount             *Decimal            `xml:"service-fee-amount,attr,omitempty" json:"service_fee_amount,omitempty"`
+	DisbursementDetails          *DisbursementDetail `xml:"disbursement-details" json:"disbursement_details"`
+	PayPalDetails                *PayPalDetail       `xml:"paypal" json:"paypal"`
+	VenmoAccountDetails          *VenmoAccountDetail `xml:"venmo-account" json:"venmo_account"`
+	AndroidPayDetails            *AndroidPayDetail   `xml:"android-pay-card" json:"android_pay_card"`
+	ApplePayDetails              *ApplePayDetail     `xml:"apple-pay" json:"apple_pay"`
+	RiskData                     *RiskData           `xml:"risk-data" json:"risk_data"`
+	Descriptor                   *Descriptor         `xml:"descriptor" json:"descriptor"`
+	RefundedTransactionId        *string             `xml:"refunded-transaction-id" json:"refunded_transaction_id"`
+	RefundIds                    *[]string           `xml:"refund-ids>item" json:"refund_ids"`
+	// AuthorizedTransactionId is the authorization a partial settlement was submitted for, and
+	// PartialSettlementTransactionIds the partial settlements of an authorization
+	AuthorizedTransactionId         *string                    `xml:"authorized-transaction-id" json:"authorized_transaction_id"`
+	PartialSettlementTransactionIds *[]string                  `xml:"partial-settlement-transaction-ids>item" json:"partial_settlement_transaction_ids"`
+	Disputes                        []*Dispute                 `xml:"disputes>dispute" json:"disputes"`
+	AuthorizationAdjustments        []*AuthorizationAdjustment `xml:"authorization-adjustments>authorization-adjustment" json:"authorization_adjustments"`
 }
 
 type TxRequest struct {
-	XMLName             string           `xml:"transaction"`
-	CustomerID          string           `xml:"customer-id,omitempty"`
-	Type                string           `xml:"type,omitempty"`
-	OrderId             string           `xml:"order-id,omitempty"`
-	PaymentMethodToken  string           `xml:"payment-method-token,omitempty"`
-	PaymentMethodNonce  string           `xml:"payment-method-nonce,omitempty"`
-	MerchantAccountId   string           `xml:"merchant-account-id,omitempty"`
-	PlanId              string           `xml:"plan-id,omitempty"`
-	DeviceData          string           `xml:"device-data,omitempty"`
-	Channel             string           `xml:"channel,omitempty"`
-	PurchaseOrderNumber string           `xml:"purchase-order-number,omitempty"`
-	TaxExempt           bool             `xml:"tax-exempt,omitempty"`
-	CustomFields        CustomFields     `xml:"custom-fields,omitempty"`
-	TransactionSource   TxSource         `xml:"transaction-source,omitempty"`
-	LineItems           LineItemRequests `xml:"line-items,omitempty"`
-	Amount              *Decimal         `xml:"amount"`
-	CreditCard          *CreditCard      `xml:"credit-card,omitempty"`
-	Customer            *CustomerRequest `xml:"customer,omitempty"`
-	BillingAddress      *Address         `xml:"billing,omitempty"`
-	ShippingAddress     *Address         `xml:"shipping,omitempty"`
-	TaxAmount           *Decimal         `xml:"tax-amount,omitempty"`
-	Options             *TxOpts          `xml:"options,omitempty"`
-	ServiceFeeAmount    *Decimal         `xml:"service-fee-amount,attr,omitempty"`
-	RiskData            *RiskDataRequest `xml:"risk-data,omitempty"`
-	Descriptor          *Descriptor      `xml:"descriptor,omitempty"`
+	XMLName             string           `xml:"transaction" json:"-"`
+	CustomerID          string           `xml:"customer-id,omitempty" json:"customer_id,omitempty"`
+	Type                TxType           `xml:"type,omitempty" json:"type,omitempty"`
+	OrderId             string           `xml:"order-id,omitempty" json:"order_id,omitempty"`
+	PaymentMethodToken  string           `xml:"payment-method-token,omitempty" json:"payment_method_token,omitempty"`
+	PaymentMethodNonce  string           `xml:"payment-method-nonce,omitempty" json:"payment_method_nonce,omitempty"`
+	MerchantAccountId   string           `xml:"merchant-account-id,omitempty" json:"merchant_account_id,omitempty"`
+	PlanId              string           `xml:"plan-id,omitempty" json:"plan_id,omitempty"`
+	DeviceData          string           `xml:"device-data,omitempty" json:"device_data,omitempty"`
+	Channel             string           `xml:"channel,omitempty" json:"channel,omitempty"`
+	PurchaseOrderNumber string           `xml:"purchase-order-number,omitempty" json:"purchase_order_number,omitempty"`
+	TaxExempt           bool             `xml:"tax-exempt,omitempty" json:"tax_exempt,omitempty"`
+	CustomFields        CustomFields     `xml:"custom-fields,omitempty" json:"custom_fields,omitempty"`
+	TransactionSource   TxSource         `xml:"transaction-source,omitempty" json:"transaction_source,omitempty"`
+	LineItems           LineItemRequests `xml:"line-items,omitempty" json:"line_items,omitempty"`
+	Amount              *Decimal         `xml:"amount" json:"amount"`
+	CreditCard          *CreditCard      `xml:"credit-card,omitempty" json:"credit_card,omitempty"`
+	Customer            *CustomerRequest `xml:"customer,omitempty" json:"customer,omitempty"`
+	BillingAddress      *Address         `xml:"billing,omitempty" json:"billing,omitempty"`
+	ShippingAddress     *Address         `xml:"shipping,omitempty" json:"shipping,omitempty"`
+	TaxAmount           *Decimal         `xml:"tax-amount,omitempty" json:"tax_amount,omitempty"`
+	Options             *TxOpts          `xml:"options,omitempty" json:"options,omitempty"`
+	ServiceFeeAmount    *Decimal         `xml:"service-fee-amount,attr,omitempty" json:"service_fee_amount,omitempty"`
+	RiskData            *RiskDataRequest `xml:"risk-data,omitempty" json:"risk_data,omitempty"`
+	Descriptor          *Descriptor      `xml:"descriptor,omitempty" json:"descriptor,omitempty"`
 }
 
 type RefundRequest struct {
-	XMLName string   `xml:"transaction"`
-	Amount  *Decimal `xml:"amount"`
-	OrderID string   `xml:"order-id,omitempty"`
+	XMLName string   `xml:"transaction" json:"-"`
+	Amount  *Decimal `xml:"amount" json:"amount"`
+	OrderID string   `xml:"order-id,omitempty" json:"order_id,omitempty"`
 }
 
 func (t *Tx) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
@@ -344,35 +359,37 @@ func (t *Tx) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	if err := d.DecodeElement((*typeWithNoFunctions)(t), &start); err != nil {
 		return err
 	}
-	if t.SubscriptionDetails != nil &&
-		t.SubscriptionDetails.BillingPeriodStartDate == "" &&
-		t.SubscriptionDetails.BillingPeriodEndDate == "" {
-		t.SubscriptionDetails = nil
+	if t.SubscriptionDetails != nil {
+		t.SubscriptionDetails.BillingPeriodStartDate = dateOrNil(t.SubscriptionDetails.BillingPeriodStartDate)
+		t.SubscriptionDetails.BillingPeriodEndDate = dateOrNil(t.SubscriptionDetails.BillingPeriodEndDate)
+		if t.SubscriptionDetails.BillingPeriodStartDate == nil && t.SubscriptionDetails.BillingPeriodEndDate == nil {
+			t.SubscriptionDetails = nil
+		}
 	}
 	return nil
 }
 
 type Transactions struct {
-	Transaction []*Tx `xml:"transaction"`
+	Transaction []*Tx `xml:"transaction" json:"transaction"`
 }
 
 type TxOpts struct {
-	SubmitForSettlement              bool                       `xml:"submit-for-settlement,omitempty"`
-	StoreInVault                     bool                       `xml:"store-in-vault,omitempty"`
-	StoreInVaultOnSuccess            bool                       `xml:"store-in-vault-on-success,omitempty"`
-	AddBillingAddressToPaymentMethod bool                       `xml:"add-billing-address-to-payment-method,omitempty"`
-	StoreShippingAddressInVault      bool                       `xml:"store-shipping-address-in-vault,omitempty"`
-	HoldInEscrow                     bool                       `xml:"hold-in-escrow,omitempty"`
-	SkipAdvancedFraudChecking        bool                       `xml:"skip_advanced_fraud_checking,omitempty"`
-	TransactionOptionsPaypalRequest  *TxPaypalOptsRequest       `xml:"paypal,omitempty"`
-	ThreeDSecure                     *TxThreeDSecureOptsRequest `xml:"three-d-secure,omitempty"`
+	SubmitForSettlement              bool                       `xml:"submit-for-settlement,omitempty" json:"submit_for_settlement,omitempty"`
+	StoreInVault                     bool                       `xml:"store-in-vault,omitempty" json:"store_in_vault,omitempty"`
+	StoreInVaultOnSuccess            bool                       `xml:"store-in-vault-on-success,omitempty" json:"store_in_vault_on_success,omitempty"`
+	AddBillingAddressToPaymentMethod bool                       `xml:"add-billing-address-to-payment-method,omitempty" json:"add_billing_address_to_payment_method,omitempty"`
+	StoreShippingAddressInVault      bool                       `xml:"store-shipping-address-in-vault,omitempty" json:"store_shipping_address_in_vault,omitempty"`
+	HoldInEscrow                     bool                       `xml:"hold-in-escrow,omitempty" json:"hold_in_escrow,omitempty"`
+	SkipAdvancedFraudChecking        bool                       `xml:"skip_advanced_fraud_checking,omitempty" json:"skip_advanced_fraud_checking,omitempty"`
+	TransactionOptionsPaypalRequest  *TxPaypalOptsRequest       `xml:"paypal,omitempty" json:"paypal,omitempty"`
+	ThreeDSecure                     *TxThreeDSecureOptsRequest `xml:"three-d-secure,omitempty" json:"three_d_secure,omitempty"`
 }
 
 type TxPaypalOptsRequest struct {
-	CustomField       string
-	PayeeEmail        string
-	Description       string
-	SupplementaryData map[string]string
+	CustomField       string            `json:"custom_field"`
+	PayeeEmail        string            `json:"payee_email"`
+	Description       string            `json:"description"`
+	SupplementaryData map[string]string `json:"supplementary_data"`
 }
 
 func (r TxPaypalOptsRequest) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
@@ -422,34 +439,73 @@ func (r TxPaypalOptsRequest) MarshalXML(e *xml.Encoder, start xml.StartElement)
 }
 
 type TxThreeDSecureOptsRequest struct {
-	Required bool `xml:"required"`
+	Required bool `xml:"required" json:"required"`
 }
 
 type TransactionSearchResult struct {
-	TotalItems        int
-	CurrentPageNumber int
-	PageSize          int
-	TotalIDs          []string
-	Transactions      []*Tx
+	TotalItems        int      `json:"total_items"`
+	CurrentPageNumber int      `json:"current_page_number"`
+	PageSize          int      `json:"page_size"`
+	TotalIDs          []string `json:"total_ids"`
+	Transactions      []*Tx    `json:"transactions"`
 }
 
 type RiskData struct {
-	ID       string `xml:"id"`
-	Decision string `xml:"decision"`
+	ID       string `xml:"id" json:"id"`
+	Decision string `xml:"decision" json:"decision"`
 }
 
 type RiskDataRequest struct {
-	CustomerBrowser string `xml:"customer-browser"`
-	CustomerIP      string `xml:"customer-ip"`
+	CustomerBrowser string `xml:"customer-browser" json:"customer_browser"`
+	CustomerIP      string `xml:"customer-ip" json:"customer_ip"`
 }
 
 type SubscriptionDetail struct {
-	BillingPeriodStartDate string `xml:"billing-period-start-date"`
-	BillingPeriodEndDate   string `xml:"billing-period-end-date"`
+	BillingPeriodStartDate *Date `xml:"billing-period-start-date" json:"billing_period_start_date"`
+	BillingPeriodEndDate   *Date `xml:"billing-period-end-date" json:"billing_period_end_date"`
+}
+
+// The raw accessors return the dates formatted with DateFormat, which is how the gateway sends them, empty when missing.
+
+func (d *SubscriptionDetail) RawBillingPeriodStartDate() string {
+	return formatDate(d.BillingPeriodStartDate)
 }
 
+func (d *SubscriptionDetail) RawBillingPeriodEndDate() string {
+	return formatDate(d.BillingPeriodEndDate)
+}
+
+// amount returns the amount of the request, nil for a nil request
+func (r *TxRequest) amount() *Decimal {
+	if r == nil {
+		return nil
+	}
+	return r.Amount
+}
+
+// ValidateType returns an error wrapping ErrInvalidTxType unless the type is a sale or a credit
+func (r *TxRequest) ValidateType() error {
+	if r == nil {
+		return fmt.Errorf("%w : no transaction", ErrInvalidTxType)
+	}
+	switch r.Type {
+	case TxTypeSale, TxTypeCredit:
+		return nil
+	}
+	return fmt.Errorf("%w %q, expecting %q or %q", ErrInvalidTxType, r.Type, TxTypeSale, TxTypeCredit)
+}
+
+// Pay creates the transaction, a sale or a credit as given by its type
 func (c *APIClient) Pay(ctx context.Context, tx *TxRequest) (*Tx, error) {
-	response, err := c.do(ctx, http.MethodPost, transactionsPath, tx)
+	if err := tx.ValidateType(); err != nil {
+		return nil, err
+	}
+	if currency, ok := c.Currencies[tx.MerchantAccountId]; ok {
+		if err := tx.ValidateAmount(currency); err != nil {
+			return nil, err
+		}
+	}
+	response, err := c.do(ctx, "Pay", http.MethodPost, transactionsPath, tx)
 	if err != nil {
 		return nil, err
 	}
@@ -464,19 +520,35 @@ func (c *APIClient) Pay(ctx context.Context, tx *TxRequest) (*Tx, error) {
 	return nil, &invalidResponseError{response}
 }
 
+// Credit pays the amount to the payment method, without a sale to refund. The type of the request is set to
+// credit when empty, any other type than credit being an error
+func (c *APIClient) Credit(ctx context.Context, tx *TxRequest) (*Tx, error) {
+	if tx == nil {
+		return nil, fmt.Errorf("%w : no transaction", ErrInvalidTxType)
+	}
+	credit := *tx
+	if credit.Type == "" {
+		credit.Type = TxTypeCredit
+	}
+	if credit.Type != TxTypeCredit {
+		return nil, fmt.Errorf("%w %q, a credit is expected", ErrInvalidTxType, credit.Type)
+	}
+	return c.Pay(ctx, &credit)
+}
+
 type TxCloneRequest struct {
-	XMLName string       `xml:"transaction-clone"`
-	Amount  *Decimal     `xml:"amount"`
-	Channel string       `xml:"channel"`
-	Options *TxCloneOpts `xml:"options"`
+	XMLName string       `xml:"transaction-clone" json:"-"`
+	Amount  *Decimal     `xml:"amount" json:"amount"`
+	Channel string       `xml:"channel" json:"channel"`
+	Options *TxCloneOpts `xml:"options" json:"options"`
 }
 
 type TxCloneOpts struct {
-	SubmitForSettlement bool `xml:"submit-for-settlement"`
+	SubmitForSettlement bool `xml:"submit-for-settlement" json:"submit_for_settlement"`
 }
 
 func (c *APIClient) Clone(ctx context.Context, id string, tx *TxCloneRequest) (*Tx, error) {
-	response, err := c.do(ctx, http.MethodPost, transactionsPath+"/"+id+"/clone", tx)
+	response, err := c.do(ctx, "Clone", http.MethodPost, transactionsPath+"/"+id+"/clone", tx)
 	if err != nil {
 		return nil, err
 	}
@@ -498,7 +570,156 @@ func (c *APIClient) SubmitForSettlement(ctx context.Context, id string, amounts
 			Amount: amounts[0],
 		}
 	}
-	response, err := c.do(ctx, http.MethodPut, transactionsPath+"/"+id+"/submit_for_settlement", tx)
+	if err := c.guard(ctx, id, OpSubmitForSettlement, tx.amount()); err != nil {
+		return nil, err
+	}
+	response, err := c.do(ctx, "SubmitForSettlement", http.MethodPut, transactionsPath+"/"+id+"/submit_for_settlement", tx)
+	if err != nil {
+		return nil, err
+	}
+	switch response.StatusCode {
+	case 200:
+		var result Tx
+		if err := xml.Unmarshal(response.Body, &result); err != nil {
+			return nil, err
+		}
+		return &result, nil
+	}
+	return nil, &invalidResponseError{response}
+}
+
+var (
+	descriptorNameFormat  = regexp.MustCompile(`^([^*]{3}\*.{1,18}|[^*]{7}\*.{1,14}|[^*]{12}\*.{1,9})$`)
+	descriptorPhoneFormat = regexp.MustCompile(`^[0-9 ().-]+$`)
+)
+
+// ValidDescriptorName reports whether the descriptor name is empty or made of a business part of 3, 7 or
+// 12 characters, a star and a product part, 22 characters at most
+func ValidDescriptorName(name string) bool {
+	return name == "" || utf8.RuneCountInString(name) <= 22 && descriptorNameFormat.MatchString(name)
+}
+
+// ValidDescriptorPhone reports whether the descriptor phone is empty or has 10 to 14 digits, separated
+// by spaces, parentheses, dots or dashes
+func ValidDescriptorPhone(phone string) bool {
+	if phone == "" {
+		return true
+	}
+	digits := 0
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	return digits >= 10 && digits <= 14 && descriptorPhoneFormat.MatchString(phone)
+}
+
+// ValidDescriptorURL reports whether the descriptor url has 13 characters at most
+func ValidDescriptorURL(url string) bool {
+	return utf8.RuneCountInString(url) <= 13
+}
+
+// Validate checks the descriptor against the formats the gateway accepts, see ValidDescriptorName,
+// ValidDescriptorPhone and ValidDescriptorURL
+func (d *Descriptor) Validate() error {
+	if d == nil {
+		return nil
+	}
+	if !ValidDescriptorName(d.Name) {
+		return fmt.Errorf("%w : descriptor name %q is not formatted as business*product", ErrInvalidTxUpdate, d.Name)
+	}
+	if !ValidDescriptorPhone(d.Phone) {
+		return fmt.Errorf("%w : descriptor phone %q must have 10 to 14 digits", ErrInvalidTxUpdate, d.Phone)
+	}
+	if !ValidDescriptorURL(d.URL) {
+		return fmt.Errorf("%w : descriptor url %q is longer than 13 characters", ErrInvalidTxUpdate, d.URL)
+	}
+	return nil
+}
+
+// TxDetailsRequest changes the details of a transaction submitted for settlement. The amount can only be
+// lowered, the fields left empty are not changed
+type TxDetailsRequest struct {
+	XMLName    string      `xml:"transaction" json:"-"`
+	Amount     *Decimal    `xml:"amount,omitempty" json:"amount,omitempty"`
+	OrderId    string      `xml:"order-id,omitempty" json:"order_id,omitempty"`
+	Descriptor *Descriptor `xml:"descriptor,omitempty" json:"descriptor,omitempty"`
+}
+
+// Validate returns an error wrapping ErrInvalidTxUpdate when nothing is updated, the amount is not positive,
+// the order id is too long or the descriptor is malformed
+func (r *TxDetailsRequest) Validate() error {
+	if r == nil || (r.Amount == nil && r.OrderId == "" && r.Descriptor == nil) {
+		return fmt.Errorf("%w : no details to update", ErrInvalidTxUpdate)
+	}
+	if r.Amount != nil && r.Amount.Sign() <= 0 {
+		return fmt.Errorf("%w : amount %s must be positive", ErrInvalidTxUpdate, r.Amount)
+	}
+	if utf8.RuneCountInString(r.OrderId) > 255 {
+		return fmt.Errorf("%w : order id is longer than 255 characters", ErrInvalidTxUpdate)
+	}
+	if r.Descriptor != nil {
+		return r.Descriptor.Validate()
+	}
+	return nil
+}
+
+// UpdateTransactionDetails changes the amount, the order id or the descriptor of a transaction submitted for
+// settlement, e.g. when an order is edited before it settles
+func (c *APIClient) UpdateTransactionDetails(ctx context.Context, id string, details *TxDetailsRequest) (*Tx, error) {
+	if err := details.Validate(); err != nil {
+		return nil, err
+	}
+	response, err := c.do(ctx, "UpdateTransactionDetails", http.MethodPut, transactionsPath+"/"+id+"/update_details", details)
+	if err != nil {
+		return nil, err
+	}
+	switch response.StatusCode {
+	case 200:
+		var result Tx
+		if err := xml.Unmarshal(response.Body, &result); err != nil {
+			return nil, err
+		}
+		return &result, nil
+	}
+	return nil, &invalidResponseError{response}
+}
+
+// AuthorizationAdjustment is an attempt to change the amount of an authorization, see AdjustAuthorization
+type AuthorizationAdjustment struct {
+	Amount                *Decimal     `xml:"amount" json:"amount"`
+	Success               bool         `xml:"success" json:"success"`
+	Timestamp             *time.Time   `xml:"timestamp" json:"timestamp"`
+	ProcessorResponseCode ResponseCode `xml:"processor-response-code" json:"processor_response_code"`
+	ProcessorResponseText string       `xml:"processor-response-text" json:"processor_response_text"`
+	ProcessorResponseType ResponseType `xml:"processor-response-type" json:"processor_response_type"`
+}
+
+// AdjustAuthorizationRequest carries the new amount of the authorization, higher or lower than the current one
+type AdjustAuthorizationRequest struct {
+	XMLName string   `xml:"transaction" json:"-"`
+	Amount  *Decimal `xml:"amount" json:"amount"`
+}
+
+// Validate returns an error wrapping ErrInvalidTxUpdate unless the amount is positive
+func (r *AdjustAuthorizationRequest) Validate() error {
+	if r == nil {
+		return fmt.Errorf("%w : no adjusted amount", ErrInvalidTxUpdate)
+	}
+	if r.Amount == nil || r.Amount.Sign() <= 0 {
+		return fmt.Errorf("%w : the adjusted amount %s must be positive", ErrInvalidTxUpdate, r.Amount)
+	}
+	return nil
+}
+
+// AdjustAuthorization raises or lowers the amount of an authorized transaction, e.g. to add a tip, without
+// voiding it and authorizing again. Every attempt is recorded in Tx.AuthorizationAdjustments : when the
+// processor declines it, the error is an *APIError carrying the transaction, left with its former amount
+func (c *APIClient) AdjustAuthorization(ctx context.Context, id string, adjustment *AdjustAuthorizationRequest) (*Tx, error) {
+	if err := adjustment.Validate(); err != nil {
+		return nil, err
+	}
+	response, err := c.do(ctx, "AdjustAuthorization", http.MethodPut, transactionsPath+"/"+id+"/adjust_authorization", adjustment)
 	if err != nil {
 		return nil, err
 	}
@@ -512,8 +733,12 @@ func (c *APIClient) SubmitForSettlement(ctx context.Context, id string, amounts
 	}
 	return nil, &invalidResponseError{response}
 }
+
 func (c *APIClient) Void(ctx context.Context, id string) (*Tx, error) {
-	response, err := c.do(ctx, http.MethodPut, transactionsPath+"/"+id+"/void", nil)
+	if err := c.guard(ctx, id, OpVoid, nil); err != nil {
+		return nil, err
+	}
+	response, err := c.do(ctx, "Void", http.MethodPut, transactionsPath+"/"+id+"/void", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -529,7 +754,7 @@ func (c *APIClient) Void(ctx context.Context, id string) (*Tx, error) {
 }
 
 func (c *APIClient) CancelRelease(ctx context.Context, id string) (*Tx, error) {
-	response, err := c.do(ctx, http.MethodPut, transactionsPath+"/"+id+"/cancel_release", nil)
+	response, err := c.do(ctx, "CancelRelease", http.MethodPut, transactionsPath+"/"+id+"/cancel_release", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -545,7 +770,10 @@ func (c *APIClient) CancelRelease(ctx context.Context, id string) (*Tx, error) {
 }
 
 func (c *APIClient) ReleaseFromEscrow(ctx context.Context, id string) (*Tx, error) {
-	response, err := c.do(ctx, http.MethodPut, transactionsPath+"/"+id+"/release_from_escrow", nil)
+	if err := c.guard(ctx, id, OpReleaseFromEscrow, nil); err != nil {
+		return nil, err
+	}
+	response, err := c.do(ctx, "ReleaseFromEscrow", http.MethodPut, transactionsPath+"/"+id+"/release_from_escrow", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -561,7 +789,7 @@ func (c *APIClient) ReleaseFromEscrow(ctx context.Context, id string) (*Tx, erro
 }
 
 func (c *APIClient) HoldInEscrow(ctx context.Context, id string) (*Tx, error) {
-	response, err := c.do(ctx, http.MethodPut, transactionsPath+"/"+id+"/hold_in_escrow", nil)
+	response, err := c.do(ctx, "HoldInEscrow", http.MethodPut, transactionsPath+"/"+id+"/hold_in_escrow", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -583,7 +811,10 @@ func (c *APIClient) Refund(ctx context.Context, id string, amount ...*Decimal) (
 			Amount: amount[0],
 		}
 	}
-	response, err := c.do(ctx, http.MethodPost, transactionsPath+"/"+id+"/refund", tx)
+	if err := c.guard(ctx, id, OpRefund, tx.amount()); err != nil {
+		return nil, err
+	}
+	response, err := c.do(ctx, "Refund", http.MethodPost, transactionsPath+"/"+id+"/refund", tx)
 	if err != nil {
 		return nil, err
 	}
@@ -599,7 +830,14 @@ func (c *APIClient) Refund(ctx context.Context, id string, amount ...*Decimal) (
 }
 
 func (c *APIClient) RefundWithRequest(ctx context.Context, id string, request *RefundRequest) (*Tx, error) {
-	response, err := c.do(ctx, http.MethodPost, transactionsPath+"/"+id+"/refund", request)
+	var amount *Decimal
+	if request != nil {
+		amount = request.Amount
+	}
+	if err := c.guard(ctx, id, OpRefund, amount); err != nil {
+		return nil, err
+	}
+	response, err := c.do(ctx, "RefundWithRequest", http.MethodPost, transactionsPath+"/"+id+"/refund", request)
 	if err != nil {
 		return nil, err
 	}
@@ -615,7 +853,7 @@ func (c *APIClient) RefundWithRequest(ctx context.Context, id string, request *R
 }
 
 func (c *APIClient) FindTransaction(ctx context.Context, id string) (*Tx, error) {
-	response, err := c.do(ctx, http.MethodGet, transactionsPath+"/"+id, nil)
+	response, err := c.do(ctx, "FindTransaction", http.MethodGet, transactionsPath+"/"+id, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -631,7 +869,7 @@ func (c *APIClient) FindTransaction(ctx context.Context, id string) (*Tx, error)
 }
 
 func (c *APIClient) SearchTxs(ctx context.Context, query *Search) (*SearchResult, error) {
-	response, err := c.do(ctx, http.MethodPost, transactionsPath+"/"+advancedSearchIdsPath, query)
+	response, err := c.do(ctx, "SearchTxs", http.MethodPost, transactionsPath+"/"+advancedSearchIdsPath, query)
 	if err != nil {
 		return nil, err
 	}
@@ -680,7 +918,7 @@ func (c *APIClient) SearchTx(ctx context.Context, query *Search, searchResult *S
 }
 
 func (c *APIClient) FetchTx(ctx context.Context, query *Search) ([]*Tx, error) {
-	response, err := c.do(ctx, http.MethodPost, transactionsPath+"/"+advancedSearchPath, query)
+	response, err := c.do(ctx, "FetchTx", http.MethodPost, transactionsPath+"/"+advancedSearchPath, query)
 	if err != nil {
 		return nil, err
 	}
@@ -694,11 +932,3 @@ func (c *APIClient) FetchTx(ctx context.Context, query *Search) ([]*Tx, error) {
 	}
 	return v.Transactions, err
 }
-
-type testOperationPerformedInProductionError struct {
-	error
-}
-
-func (e *testOperationPerformedInProductionError) Error() string {
-	return fmt.Sprint("Operation not allowed in production environment")
-}
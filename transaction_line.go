@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/xml"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
 type LineItemKind string
@@ -15,35 +17,35 @@ const (
 )
 
 type LineItem struct {
-	Kind           LineItemKind `xml:"kind"`
-	Name           string       `xml:"name"`
-	Description    string       `xml:"description"`
-	UnitOfMeasure  string       `xml:"unit-of-measure"`
-	ProductCode    string       `xml:"product-code"`
-	CommodityCode  string       `xml:"commodity-code"`
-	URL            string       `xml:"url"`
-	Quantity       *Decimal     `xml:"quantity"`
-	UnitAmount     *Decimal     `xml:"unit-amount"`
-	UnitTaxAmount  *Decimal     `xml:"unit-tax-amount"`
-	TotalAmount    *Decimal     `xml:"total-amount"`
-	TaxAmount      *Decimal     `xml:"tax-amount"`
-	DiscountAmount *Decimal     `xml:"discount-amount"`
+	Kind           LineItemKind `xml:"kind" json:"kind"`
+	Name           string       `xml:"name" json:"name"`
+	Description    string       `xml:"description" json:"description"`
+	UnitOfMeasure  string       `xml:"unit-of-measure" json:"unit_of_measure"`
+	ProductCode    string       `xml:"product-code" json:"product_code"`
+	CommodityCode  string       `xml:"commodity-code" json:"commodity_code"`
+	URL            string       `xml:"url" json:"url"`
+	Quantity       *Decimal     `xml:"quantity" json:"quantity"`
+	UnitAmount     *Decimal     `xml:"unit-amount" json:"unit_amount"`
+	UnitTaxAmount  *Decimal     `xml:"unit-tax-amount" json:"unit_tax_amount"`
+	TotalAmount    *Decimal     `xml:"total-amount" json:"total_amount"`
+	TaxAmount      *Decimal     `xml:"tax-amount" json:"tax_amount"`
+	DiscountAmount *Decimal     `xml:"discount-amount" json:"discount_amount"`
 }
 
 type LineItemRequest struct {
-	Kind           LineItemKind `xml:"kind"`
-	Name           string       `xml:"name"`
-	Description    string       `xml:"description,omitempty"`
-	UnitOfMeasure  string       `xml:"unit-of-measure,omitempty"`
-	ProductCode    string       `xml:"product-code,omitempty"`
-	CommodityCode  string       `xml:"commodity-code,omitempty"`
-	URL            string       `xml:"url,omitempty"`
-	Quantity       *Decimal     `xml:"quantity"`
-	UnitAmount     *Decimal     `xml:"unit-amount"`
-	UnitTaxAmount  *Decimal     `xml:"unit-tax-amount,omitempty"`
-	TotalAmount    *Decimal     `xml:"total-amount"`
-	TaxAmount      *Decimal     `xml:"tax-amount,omitempty"`
-	DiscountAmount *Decimal     `xml:"discount-amount,omitempty"`
+	Kind           LineItemKind `xml:"kind" json:"kind"`
+	Name           string       `xml:"name" json:"name"`
+	Description    string       `xml:"description,omitempty" json:"description,omitempty"`
+	UnitOfMeasure  string       `xml:"unit-of-measure,omitempty" json:"unit_of_measure,omitempty"`
+	ProductCode    string       `xml:"product-code,omitempty" json:"product_code,omitempty"`
+	CommodityCode  string       `xml:"commodity-code,omitempty" json:"commodity_code,omitempty"`
+	URL            string       `xml:"url,omitempty" json:"url,omitempty"`
+	Quantity       *Decimal     `xml:"quantity" json:"quantity"`
+	UnitAmount     *Decimal     `xml:"unit-amount" json:"unit_amount"`
+	UnitTaxAmount  *Decimal     `xml:"unit-tax-amount,omitempty" json:"unit_tax_amount,omitempty"`
+	TotalAmount    *Decimal     `xml:"total-amount" json:"total_amount"`
+	TaxAmount      *Decimal     `xml:"tax-amount,omitempty" json:"tax_amount,omitempty"`
+	DiscountAmount *Decimal     `xml:"discount-amount,omitempty" json:"discount_amount,omitempty"`
 }
 
 type LineItems []*LineItem
@@ -83,7 +85,7 @@ func (r LineItemRequests) MarshalXML(e *xml.Encoder, start xml.StartElement) err
 }
 
 func (c *APIClient) FindTransactionLineItem(ctx context.Context, txId string) (LineItems, error) {
-	response, err := c.do(ctx, http.MethodGet, transactionsPath+"/"+txId+"/"+lineItemsPath, nil)
+	response, err := c.do(ctx, "FindTransactionLineItem", http.MethodGet, transactionsPath+"/"+txId+"/"+lineItemsPath, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -97,3 +99,111 @@ func (c *APIClient) FindTransactionLineItem(ctx context.Context, txId string) (L
 	}
 	return nil, &invalidResponseError{response}
 }
+
+// AmountMismatch is a field of a transaction request which does not add up, named as in APIError.FieldErrors
+type AmountMismatch struct {
+	Field    string
+	Actual   *Decimal
+	Expected *Decimal
+}
+
+// LineItemsMismatchError lists the amounts of a transaction request which disagree with its line items
+type LineItemsMismatchError struct {
+	Mismatches []AmountMismatch
+}
+
+func (e *LineItemsMismatchError) Error() string {
+	problems := make([]string, len(e.Mismatches))
+	for i, m := range e.Mismatches {
+		problems[i] = m.Field + " is " + m.Actual.String() + ", expected " + m.Expected.String()
+	}
+	return "line items do not add up : " + strings.Join(problems, "; ")
+}
+
+// ReconcileLineItems checks the amounts of the request against its line items, before it is sent to Pay :
+//   - the total amount of each item is its quantity times its unit amount, and its tax amount its quantity
+//     times its unit tax amount, rounded half up to the scale of the total
+//   - the tax amount of the transaction is the sum of the tax amounts of the items, when they have some
+//   - the amount of the transaction is the total of the debit items, less the credit items and the discounts,
+//     plus the tax amount
+//
+// It returns a *LineItemsMismatchError listing the amounts which do not add up, nil when there are no items.
+func (r *TxRequest) ReconcileLineItems() error {
+	if len(r.LineItems) == 0 {
+		return nil
+	}
+	var mismatches []AmountMismatch
+	check := func(field string, actual, expected *Decimal) {
+		if actual != nil && expected != nil && actual.Cmp(expected) != 0 {
+			mismatches = append(mismatches, AmountMismatch{Field: field, Actual: actual, Expected: expected})
+		}
+	}
+	product := func(quantity, unit, total *Decimal) (*Decimal, error) {
+		if quantity == nil || unit == nil || total == nil {
+			return nil, nil
+		}
+		p, err := quantity.Mul(unit)
+		if err != nil {
+			return nil, err
+		}
+		return p.Round(total.Scale, RoundHalfUp)
+	}
+
+	var (
+		amount = NewDecimal(0, 0)
+		taxes  = NewDecimal(0, 0)
+		taxed  bool
+		err    error
+	)
+	for i, item := range r.LineItems {
+		prefix := "transaction.line-items." + strconv.Itoa(i) + "."
+		total, err := product(item.Quantity, item.UnitAmount, item.TotalAmount)
+		if err != nil {
+			return err
+		}
+		check(prefix+"total-amount", item.TotalAmount, total)
+		tax, err := product(item.Quantity, item.UnitTaxAmount, item.TaxAmount)
+		if err != nil {
+			return err
+		}
+		check(prefix+"tax-amount", item.TaxAmount, tax)
+
+		if item.TotalAmount != nil {
+			if item.Kind == LineItemCreditKind {
+				amount, err = amount.Sub(item.TotalAmount)
+			} else {
+				amount, err = amount.Add(item.TotalAmount)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if item.DiscountAmount != nil {
+			if amount, err = amount.Sub(item.DiscountAmount); err != nil {
+				return err
+			}
+		}
+		if item.TaxAmount != nil {
+			taxed = true
+			if taxes, err = taxes.Add(item.TaxAmount); err != nil {
+				return err
+			}
+		}
+	}
+
+	if taxed && r.TaxAmount != nil {
+		check("transaction.tax-amount", r.TaxAmount, taxes)
+	}
+	if r.TaxAmount != nil {
+		taxes = r.TaxAmount
+	}
+	if amount, err = amount.Add(taxes); err != nil {
+		return err
+	}
+	check("transaction.amount", r.Amount, amount)
+
+	if len(mismatches) > 0 {
+		return &LineItemsMismatchError{Mismatches: mismatches}
+	}
+	return nil
+}
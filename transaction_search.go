@@ -0,0 +1,330 @@
+package braintree
+
+import (
+	"fmt"
+	"time"
+)
+
+// SearchSource is where a transaction was created, as searched with TransactionSearch.Source
+type SearchSource string
+
+const (
+	SourceAPI          SearchSource = "api"
+	SourceControlPanel SearchSource = "control_panel"
+	SourceRecurring    SearchSource = "recurring"
+)
+
+var (
+	txStatuses = []string{
+		string(StatusAuthorizationExpired), string(StatusAuthorizing), string(StatusAuthorized),
+		string(StatusGatewayRejected), string(StatusFailed), string(StatusProcessorDeclined), string(StatusSettled),
+		string(StatusSettlementConfirmed), string(StatusSettlementDeclined), string(StatusSettlementPending),
+		string(StatusSettling), string(StatusSubmittedForSettlement), string(StatusVoided),
+	}
+	txTypes       = []string{string(TxTypeSale), string(TxTypeCredit)}
+	txSources     = []string{string(SourceAPI), string(SourceControlPanel), string(SourceRecurring)}
+	paymentTypes  = []string{string(AndroidPayCardType), string(ApplePayCardType), string(CreditCardType), string(MasterpassCardType), string(PaypalAccountType), string(VenmoAccountType), string(VisaCheckoutCardType)}
+	createdUsing  = []string{"full_information", "token"}
+	cardLocations = []string{"us", "international"}
+	cardTypes     = []string{"American Express", "Carte Blanche", "China UnionPay", "Diners Club", "Discover", "Elo", "JCB", "Laser", "Maestro", "MasterCard", "Solo", "Switch", "UK Maestro", "Visa", "Unknown"}
+)
+
+// TransactionSearch builds the criteria of a transaction search with the field names and value types the
+// gateway expects. The values are checked as they are given, and the problems reported by Search.
+//
+//	query, err := NewTransactionSearch().
+//		Status(StatusSettled, StatusSettling).
+//		CreatedAt(from, time.Time{}).
+//		Amount(NewDecimal(1000, 2), nil).
+//		Search()
+//	it := c.IterateTxs(query)
+type TransactionSearch struct {
+	criteria
+	types  []string
+	refund *bool
+}
+
+func NewTransactionSearch() *TransactionSearch {
+	return &TransactionSearch{}
+}
+
+// Search returns the search to be given to SearchTxs or IterateTxs, or an *InvalidSearchError
+func (s *TransactionSearch) Search() (*Search, error) {
+	var problems []string
+	if s.refund != nil && *s.refund && s.types != nil && !contains(s.types, string(TxTypeCredit)) {
+		problems = append(problems, fmt.Sprintf("refund : refunds are credits, but the type is restricted to %v", s.types))
+	}
+	return s.build(problems...)
+}
+
+func (s *TransactionSearch) IDs(ids ...string) *TransactionSearch {
+	s.multi("ids", nil, ids)
+	return s
+}
+
+func (s *TransactionSearch) Status(statuses ...Status) *TransactionSearch {
+	values := make([]string, len(statuses))
+	for i, status := range statuses {
+		values[i] = string(status)
+	}
+	s.multi("status", txStatuses, values)
+	return s
+}
+
+// Type restricts the search to sales or credits
+func (s *TransactionSearch) Type(types ...TxType) *TransactionSearch {
+	values := make([]string, len(types))
+	for i, typ := range types {
+		values[i] = string(typ)
+	}
+	s.types = values
+	s.multi("type", txTypes, values)
+	return s
+}
+
+func (s *TransactionSearch) Source(sources ...SearchSource) *TransactionSearch {
+	values := make([]string, len(sources))
+	for i, source := range sources {
+		values[i] = string(source)
+	}
+	s.multi("source", txSources, values)
+	return s
+}
+
+func (s *TransactionSearch) PaymentInstrumentType(types ...PaymentType) *TransactionSearch {
+	values := make([]string, len(types))
+	for i, typ := range types {
+		values[i] = string(typ)
+	}
+	s.multi("payment-instrument-type", paymentTypes, values)
+	return s
+}
+
+func (s *TransactionSearch) MerchantAccountID(ids ...string) *TransactionSearch {
+	s.multi("merchant-account-id", nil, ids)
+	return s
+}
+
+// CreatedUsing restricts the search to transactions created with "full_information" or with a "token"
+func (s *TransactionSearch) CreatedUsing(values ...string) *TransactionSearch {
+	s.multi("created-using", createdUsing, values)
+	return s
+}
+
+// CreditCardType restricts the search to card brands, as found in CreditCard.CardType (e.g. "Visa")
+func (s *TransactionSearch) CreditCardType(types ...string) *TransactionSearch {
+	s.multi("credit-card-card-type", cardTypes, types)
+	return s
+}
+
+// CreditCardCustomerLocation restricts the search to cards issued in the "us" or "international"
+func (s *TransactionSearch) CreditCardCustomerLocation(locations ...string) *TransactionSearch {
+	s.multi("credit-card-customer-location", cardLocations, locations)
+	return s
+}
+
+// Refund restricts the search to the credits which refund a sale (true) or to the other transactions (false)
+func (s *TransactionSearch) Refund(refund bool) *TransactionSearch {
+	s.refund = &refund
+	s.keyValue("refund", refund)
+	return s
+}
+
+// Amount searches the amounts between min and max, inclusive. A nil bound leaves that side open
+func (s *TransactionSearch) Amount(min, max *Decimal) *TransactionSearch {
+	s.decimalRange("amount", min, max)
+	return s
+}
+
+// CreditCardBIN searches the cards starting with the 6 or 8 digits bank identification number
+func (s *TransactionSearch) CreditCardBIN(bin string) *TransactionSearch {
+	s.bin("credit-card-number", bin)
+	return s
+}
+
+func (s *TransactionSearch) CreditCardLast4(last4 string) *TransactionSearch {
+	s.last4("credit-card-number", last4)
+	return s
+}
+
+// The time ranges are inclusive, a zero time leaving that side open.
+
+func (s *TransactionSearch) CreatedAt(min, max time.Time) *TransactionSearch {
+	s.timeRange("created-at", min, max)
+	return s
+}
+
+func (s *TransactionSearch) AuthorizedAt(min, max time.Time) *TransactionSearch {
+	s.timeRange("authorized-at", min, max)
+	return s
+}
+
+func (s *TransactionSearch) AuthorizationExpiredAt(min, max time.Time) *TransactionSearch {
+	s.timeRange("authorization-expired-at", min, max)
+	return s
+}
+
+func (s *TransactionSearch) SubmittedForSettlementAt(min, max time.Time) *TransactionSearch {
+	s.timeRange("submitted-for-settlement-at", min, max)
+	return s
+}
+
+func (s *TransactionSearch) SettledAt(min, max time.Time) *TransactionSearch {
+	s.timeRange("settled-at", min, max)
+	return s
+}
+
+func (s *TransactionSearch) VoidedAt(min, max time.Time) *TransactionSearch {
+	s.timeRange("voided-at", min, max)
+	return s
+}
+
+func (s *TransactionSearch) FailedAt(min, max time.Time) *TransactionSearch {
+	s.timeRange("failed-at", min, max)
+	return s
+}
+
+func (s *TransactionSearch) GatewayRejectedAt(min, max time.Time) *TransactionSearch {
+	s.timeRange("gateway-rejected-at", min, max)
+	return s
+}
+
+func (s *TransactionSearch) ProcessorDeclinedAt(min, max time.Time) *TransactionSearch {
+	s.timeRange("processor-declined-at", min, max)
+	return s
+}
+
+func (s *TransactionSearch) DisbursementDate(min, max time.Time) *TransactionSearch {
+	s.timeRange("disbursement-date", min, max)
+	return s
+}
+
+func (s *TransactionSearch) DisputeDate(min, max time.Time) *TransactionSearch {
+	s.timeRange("dispute-date", min, max)
+	return s
+}
+
+// The text criteria return the field, on which one or more operators are set : Is, IsNot, StartsWith,
+// EndsWith and Contains.
+
+func (s *TransactionSearch) ID() *TextField {
+	return s.text("id")
+}
+
+func (s *TransactionSearch) OrderID() *TextField {
+	return s.text("order-id")
+}
+
+func (s *TransactionSearch) Currency() *TextField {
+	return s.text("currency")
+}
+
+func (s *TransactionSearch) PaymentMethodToken() *TextField {
+	return s.text("payment-method-token")
+}
+
+func (s *TransactionSearch) ProcessorAuthorizationCode() *TextField {
+	return s.text("processor-authorization-code")
+}
+
+func (s *TransactionSearch) SettlementBatchID() *TextField {
+	return s.text("settlement-batch-id")
+}
+
+func (s *TransactionSearch) SubscriptionID() *TextField {
+	return s.text("subscription-id")
+}
+
+func (s *TransactionSearch) PlanID() *TextField {
+	return s.text("plan-id")
+}
+
+func (s *TransactionSearch) PayPalPayerEmail() *TextField {
+	return s.text("paypal-payer-email")
+}
+
+func (s *TransactionSearch) PayPalPaymentID() *TextField {
+	return s.text("paypal-payment-id")
+}
+
+func (s *TransactionSearch) CreditCardCardholderName() *TextField {
+	return s.text("credit-card-cardholder-name")
+}
+
+func (s *TransactionSearch) CreditCardExpirationDate() *TextField {
+	return s.text("credit-card-expiration-date")
+}
+
+func (s *TransactionSearch) CreditCardUniqueIdentifier() *TextField {
+	return s.text("credit-card-unique-identifier")
+}
+
+func (s *TransactionSearch) CustomerID() *TextField {
+	return s.text("customer-id")
+}
+
+func (s *TransactionSearch) CustomerFirstName() *TextField {
+	return s.text("customer-first-name")
+}
+
+func (s *TransactionSearch) CustomerLastName() *TextField {
+	return s.text("customer-last-name")
+}
+
+func (s *TransactionSearch) CustomerCompany() *TextField {
+	return s.text("customer-company")
+}
+
+func (s *TransactionSearch) CustomerEmail() *TextField {
+	return s.text("customer-email")
+}
+
+func (s *TransactionSearch) CustomerPhone() *TextField {
+	return s.text("customer-phone")
+}
+
+func (s *TransactionSearch) CustomerFax() *TextField {
+	return s.text("customer-fax")
+}
+
+func (s *TransactionSearch) CustomerWebsite() *TextField {
+	return s.text("customer-website")
+}
+
+// AddressField is a searchable address field, of the billing and shipping addresses of transactions or of the
+// addresses of customers
+type AddressField string
+
+const (
+	AddressFirstName       AddressField = "first-name"
+	AddressLastName        AddressField = "last-name"
+	AddressCompany         AddressField = "company"
+	AddressStreetAddress   AddressField = "street-address"
+	AddressExtendedAddress AddressField = "extended-address"
+	AddressLocality        AddressField = "locality"
+	AddressRegion          AddressField = "region"
+	AddressPostalCode      AddressField = "postal-code"
+	AddressCountryName     AddressField = "country-name"
+)
+
+var addressFields = []string{
+	string(AddressFirstName), string(AddressLastName), string(AddressCompany), string(AddressStreetAddress),
+	string(AddressExtendedAddress), string(AddressLocality), string(AddressRegion), string(AddressPostalCode),
+	string(AddressCountryName),
+}
+
+func (s *TransactionSearch) Billing(field AddressField) *TextField {
+	return s.address("billing-", field)
+}
+
+func (s *TransactionSearch) Shipping(field AddressField) *TextField {
+	return s.address("shipping-", field)
+}
+
+func (s *TransactionSearch) address(prefix string, field AddressField) *TextField {
+	if !contains(addressFields, string(field)) {
+		s.problem("%s%s : unknown address field", prefix, field)
+		return &TextField{}
+	}
+	return s.text(prefix + string(field))
+}
@@ -0,0 +1,149 @@
+package braintree
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"time"
+)
+
+// VerificationStatus is the status of a credit card verification
+type VerificationStatus string
+
+const (
+	VerificationStatusVerifying         VerificationStatus = "verifying"
+	VerificationStatusVerified          VerificationStatus = "verified"
+	VerificationStatusProcessorDeclined VerificationStatus = "processor_declined"
+	VerificationStatusGatewayRejected   VerificationStatus = "gateway_rejected"
+	VerificationStatusFailed            VerificationStatus = "failed"
+)
+
+var verificationStatuses = []string{
+	string(VerificationStatusVerifying), string(VerificationStatusVerified),
+	string(VerificationStatusProcessorDeclined), string(VerificationStatusGatewayRejected),
+	string(VerificationStatusFailed),
+}
+
+// CreditCardVerification is the authorization run on a card, either on its own with CreateVerification or
+// when a card is vaulted with VerifyCard. A failed verification is found in APIError.Verification
+type CreditCardVerification struct {
+	XMLName                      string             `xml:"verification" json:"-"`
+	Id                           string             `xml:"id" json:"id"`
+	Status                       VerificationStatus `xml:"status" json:"status"`
+	Amount                       *Decimal           `xml:"amount" json:"amount"`
+	CurrencyISOCode              string             `xml:"currency-iso-code" json:"currency_iso_code"`
+	MerchantAccountId            string             `xml:"merchant-account-id" json:"merchant_account_id"`
+	ProcessorResponseCode        ResponseCode       `xml:"processor-response-code" json:"processor_response_code"`
+	ProcessorResponseText        string             `xml:"processor-response-text" json:"processor_response_text"`
+	ProcessorResponseType        ResponseType       `xml:"processor-response-type" json:"processor_response_type"`
+	AdditionalProcessorResponse  string             `xml:"additional-processor-response" json:"additional_processor_response"`
+	AVSErrorResponseCode         AVSResponseCode    `xml:"avs-error-response-code" json:"avs_error_response_code"`
+	AVSPostalCodeResponseCode    AVSResponseCode    `xml:"avs-postal-code-response-code" json:"avs_postal_code_response_code"`
+	AVSStreetAddressResponseCode AVSResponseCode    `xml:"avs-street-address-response-code" json:"avs_street_address_response_code"`
+	CVVResponseCode              CVVResponseCode    `xml:"cvv-response-code" json:"cvv_response_code"`
+	GatewayRejectionReason       RejectionReason    `xml:"gateway-rejection-reason" json:"gateway_rejection_reason"`
+	CreditCard                   *CreditCard        `xml:"credit-card" json:"credit_card"`
+	BillingAddress               *Address           `xml:"billing" json:"billing"`
+	RiskData                     *RiskData          `xml:"risk-data" json:"risk_data"`
+	ThreeDSecureInfo             *ThreeDSecureInfo  `xml:"three-d-secure-info,omitempty" json:"three_d_secure_info,omitempty"`
+	CreatedAt                    *time.Time         `xml:"created-at" json:"created_at"`
+}
+
+// Verified reports whether the card was verified, the other statuses being failures
+func (v *CreditCardVerification) Verified() bool {
+	return v.Status == VerificationStatusVerified
+}
+
+// CreditCardVerificationRequest verifies a card, given in clear or as a nonce, without vaulting it
+type CreditCardVerificationRequest struct {
+	XMLName            string            `xml:"verification" json:"-"`
+	PaymentMethodNonce string            `xml:"payment-method-nonce,omitempty" json:"payment_method_nonce,omitempty"`
+	CreditCard         *CreditCard       `xml:"credit-card,omitempty" json:"credit_card,omitempty"`
+	Options            *VerificationOpts `xml:"options,omitempty" json:"options,omitempty"`
+	RiskData           *RiskDataRequest  `xml:"risk-data,omitempty" json:"risk_data,omitempty"`
+}
+
+// VerificationOpts selects the amount authorized to verify the card, and the merchant account which
+// authorizes it
+type VerificationOpts struct {
+	Amount            *Decimal `xml:"amount,omitempty" json:"amount,omitempty"`
+	MerchantAccountId string   `xml:"merchant-account-id,omitempty" json:"merchant_account_id,omitempty"`
+}
+
+const verificationsPath = "verifications"
+
+// CreateVerification verifies the card. When it is declined or rejected, the error is an *APIError
+// carrying the verification
+func (c *APIClient) CreateVerification(ctx context.Context, verification *CreditCardVerificationRequest) (*CreditCardVerification, error) {
+	response, err := c.do(ctx, "CreateVerification", http.MethodPost, verificationsPath, verification)
+	if err != nil {
+		return nil, err
+	}
+	switch response.StatusCode {
+	case 201:
+		var result CreditCardVerification
+		if err := xml.Unmarshal(response.Body, &result); err != nil {
+			return nil, err
+		}
+		return &result, nil
+	}
+	return nil, &invalidResponseError{response}
+}
+
+func (c *APIClient) FindVerification(ctx context.Context, id string) (*CreditCardVerification, error) {
+	response, err := c.do(ctx, "FindVerification", http.MethodGet, verificationsPath+"/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+	switch response.StatusCode {
+	case 200:
+		var result CreditCardVerification
+		if err := xml.Unmarshal(response.Body, &result); err != nil {
+			return nil, err
+		}
+		return &result, nil
+	}
+	return nil, &invalidResponseError{response}
+}
+
+// SearchVerifications returns the ids of the verifications matching the query, built with
+// CreditCardVerificationSearch. The verifications are fetched with FetchVerifications or IterateVerifications
+func (c *APIClient) SearchVerifications(ctx context.Context, query *Search) (*SearchResult, error) {
+	response, err := c.do(ctx, "SearchVerifications", http.MethodPost, verificationsPath+"/"+advancedSearchIdsPath, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var searchResult struct {
+		PageSize int `xml:"page-size"`
+		Ids      struct {
+			Item []string `xml:"item"`
+		} `xml:"ids"`
+	}
+	err = xml.Unmarshal(response.Body, &searchResult)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SearchResult{
+		PageSize:  searchResult.PageSize,
+		PageCount: (len(searchResult.Ids.Item) + searchResult.PageSize - 1) / searchResult.PageSize,
+		IDs:       searchResult.Ids.Item,
+	}, nil
+}
+
+func (c *APIClient) FetchVerifications(ctx context.Context, query *Search) ([]*CreditCardVerification, error) {
+	response, err := c.do(ctx, "FetchVerifications", http.MethodPost, verificationsPath+"/"+advancedSearchPath, query)
+	if err != nil {
+		return nil, err
+	}
+	var v struct {
+		XMLName       string                    `xml:"credit-card-verifications"`
+		Verifications []*CreditCardVerification `xml:"verification"`
+	}
+	err = xml.Unmarshal(response.Body, &v)
+	if err != nil {
+		return nil, err
+	}
+	return v.Verifications, err
+}
@@ -0,0 +1,82 @@
+package braintree
+
+import (
+	"time"
+)
+
+// CreditCardVerificationSearch builds the criteria of a credit card verification search, see TransactionSearch
+type CreditCardVerificationSearch struct {
+	criteria
+}
+
+func NewCreditCardVerificationSearch() *CreditCardVerificationSearch {
+	return &CreditCardVerificationSearch{}
+}
+
+// Search returns the search of the verifications, or an *InvalidSearchError
+func (s *CreditCardVerificationSearch) Search() (*Search, error) {
+	return s.build()
+}
+
+func (s *CreditCardVerificationSearch) IDs(ids ...string) *CreditCardVerificationSearch {
+	s.multi("ids", nil, ids)
+	return s
+}
+
+func (s *CreditCardVerificationSearch) Status(statuses ...VerificationStatus) *CreditCardVerificationSearch {
+	values := make([]string, len(statuses))
+	for i, status := range statuses {
+		values[i] = string(status)
+	}
+	s.multi("status", verificationStatuses, values)
+	return s
+}
+
+// CreditCardType restricts the search to card brands, as found in CreditCard.CardType (e.g. "Visa")
+func (s *CreditCardVerificationSearch) CreditCardType(types ...string) *CreditCardVerificationSearch {
+	s.multi("credit-card-card-type", cardTypes, types)
+	return s
+}
+
+func (s *CreditCardVerificationSearch) CreatedAt(min, max time.Time) *CreditCardVerificationSearch {
+	s.timeRange("created-at", min, max)
+	return s
+}
+
+func (s *CreditCardVerificationSearch) CreditCardBIN(bin string) *CreditCardVerificationSearch {
+	s.bin("credit-card-number", bin)
+	return s
+}
+
+func (s *CreditCardVerificationSearch) CreditCardLast4(last4 string) *CreditCardVerificationSearch {
+	s.last4("credit-card-number", last4)
+	return s
+}
+
+func (s *CreditCardVerificationSearch) ID() *TextField {
+	return s.text("id")
+}
+
+func (s *CreditCardVerificationSearch) CustomerID() *TextField {
+	return s.text("customer-id")
+}
+
+func (s *CreditCardVerificationSearch) CustomerEmail() *TextField {
+	return s.text("customer-email")
+}
+
+func (s *CreditCardVerificationSearch) PaymentMethodToken() *TextField {
+	return s.text("payment-method-token")
+}
+
+func (s *CreditCardVerificationSearch) CreditCardCardholderName() *TextField {
+	return s.text("credit-card-cardholder-name")
+}
+
+func (s *CreditCardVerificationSearch) CreditCardExpirationDate() *TextField {
+	return s.text("credit-card-expiration-date")
+}
+
+func (s *CreditCardVerificationSearch) BillingPostalCode() *TextField {
+	return s.text("billing-address-details-postal-code")
+}
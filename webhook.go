@@ -44,10 +44,10 @@ const (
 )
 
 type Notification struct {
-	XMLName   xml.Name  `xml:"notification"`
-	Kind      string    `xml:"kind"`
-	Timestamp time.Time `xml:"timestamp"`
-	Subject   *Subject  `xml:"subject"`
+	XMLName   xml.Name  `xml:"notification" json:"-"`
+	Kind      string    `xml:"kind" json:"kind"`
+	Timestamp time.Time `xml:"timestamp" json:"timestamp"`
+	Subject   *Subject  `xml:"subject" json:"subject"`
 }
 
 func (n *Notification) MerchantAccount() *MerchantAccount {
@@ -81,25 +81,25 @@ func (n *Notification) AccountUpdaterDailyReport() *DailyReport {
 }
 
 type DailyReport struct {
-	XMLName    xml.Name `xml:"account-updater-daily-report"`
-	ReportDate string   `xml:"report-date"`
-	ReportURL  string   `xml:"report-url"`
+	XMLName    xml.Name `xml:"account-updater-daily-report" json:"-"`
+	ReportDate string   `xml:"report-date" json:"report_date"`
+	ReportURL  string   `xml:"report-url" json:"report_url"`
 }
 
 type Disbursement struct {
-	XMLName          xml.Name         `xml:"disbursement"`
-	Id               string           `xml:"id"`
-	ExceptionMessage string           `xml:"exception-message"`
-	CurrencyIsoCode  string           `xml:"currency-iso-code"`
-	Status           string           `xml:"status"`
-	FollowUpAction   string           `xml:"follow-up-action"`
-	Success          bool             `xml:"success"`
-	Retry            bool             `xml:"retry"`
-	IsSubmerchant    bool             `xml:"sub-merchant-account"`
-	TransactionIds   []string         `xml:"transaction-ids>item"`
-	DisbursementDate *Date            `xml:"disbursement-date"`
-	Amount           *Decimal         `xml:"amount"`
-	MerchantAccount  *MerchantAccount `xml:"merchant-account"`
+	XMLName          xml.Name         `xml:"disbursement" json:"-"`
+	Id               string           `xml:"id" json:"id"`
+	ExceptionMessage string           `xml:"exception-message" json:"exception_message"`
+	CurrencyIsoCode  string           `xml:"currency-iso-code" json:"currency_iso_code"`
+	Status           string           `xml:"status" json:"status"`
+	FollowUpAction   string           `xml:"follow-up-action" json:"follow_up_action"`
+	Success          bool             `xml:"success" json:"success"`
+	Retry            bool             `xml:"retry" json:"retry"`
+	IsSubmerchant    bool             `xml:"sub-merchant-account" json:"sub_merchant_account"`
+	TransactionIds   []string         `xml:"transaction-ids>item" json:"transaction_ids"`
+	DisbursementDate *Date            `xml:"disbursement-date" json:"disbursement_date"`
+	Amount           *Decimal         `xml:"amount" json:"amount"`
+	MerchantAccount  *MerchantAccount `xml:"merchant-account" json:"merchant_account"`
 }
 
 const (
@@ -147,14 +147,14 @@ func (d *Disbursement) Transactions(ctx context.Context, c *APIClient) (*Transac
 }
 
 type Subject struct {
-	XMLName                   xml.Name         `xml:"subject"`
-	APIErrorResponse          *APIError        `xml:"api-error-response,omitempty"`
-	Disbursement              *Disbursement    `xml:"disbursement,omitempty"`
-	Subscription              *Subscription    `xml:",omitempty"`
-	MerchantAccount           *MerchantAccount `xml:"merchant-account,omitempty"`
-	Transaction               *Tx              `xml:",omitempty"`
-	Dispute                   *Dispute         `xml:"dispute,omitempty"`
-	AccountUpdaterDailyReport *DailyReport     `xml:"account-updater-daily-report,omitempty"`
+	XMLName                   xml.Name         `xml:"subject" json:"-"`
+	APIErrorResponse          *APIError        `xml:"api-error-response,omitempty" json:"api_error_response,omitempty"`
+	Disbursement              *Disbursement    `xml:"disbursement,omitempty" json:"disbursement,omitempty"`
+	Subscription              *Subscription    `xml:",omitempty" json:"subscription,omitempty"`
+	MerchantAccount           *MerchantAccount `xml:"merchant-account,omitempty" json:"merchant_account,omitempty"`
+	Transaction               *Tx              `xml:",omitempty" json:"transaction,omitempty"`
+	Dispute                   *Dispute         `xml:"dispute,omitempty" json:"dispute,omitempty"`
+	AccountUpdaterDailyReport *DailyReport     `xml:"account-updater-daily-report,omitempty" json:"account_updater_daily_report,omitempty"`
 }
 
 type SignatureError struct {